@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -49,6 +50,66 @@ type UpgradeConfigSpec struct {
 
 	// Specify if scaling up an extra node for capacity reservation before upgrade starts is needed
 	CapacityReservation bool `json:"capacityReservation,omitempty"`
+
+	// Specify if the upgrade should be paused. While paused no further upgrade steps are run, the worker
+	// MachineConfigPool is paused and node drain strategies are not executed. Clearing it resumes the upgrade
+	// from the step it was paused at.
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Optional human readable reason for pausing the upgrade
+	// +kubebuilder:validation:Optional
+	PausedReason string `json:"pausedReason,omitempty"`
+
+	// Recurring windows within which the upgrade is allowed to commence. If none are specified the upgrade
+	// may commence at any time after upgradeAt.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Date ranges within which the upgrade must not commence, regardless of upgradeAt and maintenanceWindows
+	// +kubebuilder:validation:Optional
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+
+	// Specify if the upgrade should only be checked and not performed. While set, the UpgradeConfig is
+	// validated and the pre-upgrade checks are run, with their results recorded in status.preflight, but
+	// the upgrade never commences. It has no effect once the upgrade has commenced.
+	// +kubebuilder:validation:Optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// MaintenanceWindow describes a recurring window of time within which an upgrade may commence
+type MaintenanceWindow struct {
+	// Days of the week on which the window opens. If not specified the window opens every day.
+	// +kubebuilder:validation:Optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Time of day at which the window opens, in 24-hour HH:MM format
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+
+	// How long the window stays open once it has opened, e.g. "4h"
+	Duration metav1.Duration `json:"duration"`
+
+	// IANA time zone name that days and startTime are expressed in. Defaults to UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum={"Sunday","Monday","Tuesday","Wednesday","Thursday","Friday","Saturday"}
+type Weekday string
+
+// FreezeWindow describes a period of time within which an upgrade must not commence
+type FreezeWindow struct {
+	// Start of the freeze
+	Start metav1.Time `json:"start"`
+
+	// End of the freeze
+	End metav1.Time `json:"end"`
+
+	// Optional human readable reason for the freeze
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // UpgradeConfigStatus defines the observed state of UpgradeConfig
@@ -57,6 +118,76 @@ type UpgradeConfigStatus struct {
 	// This record history of every upgrade
 	// +kubebuilder:validation:Optional
 	History UpgradeHistories `json:"history,omitempty"`
+
+	// Preflight records the results of the most recent dry run of the upgrade
+	// +kubebuilder:validation:Optional
+	Preflight *PreflightReport `json:"preflight,omitempty"`
+}
+
+// PreflightReport records the results of the checks run ahead of an upgrade during a dry run
+type PreflightReport struct {
+	// Desired version the checks were run for
+	Version string `json:"version"`
+
+	// Time the checks were run
+	RunTime metav1.Time `json:"runTime"`
+
+	// Ready indicates whether every check passed, so the upgrade would commence if it were due
+	Ready bool `json:"ready"`
+
+	// Results of the individual checks, in the order they were run
+	// +kubebuilder:validation:Optional
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+// PreflightCheck records the result of a single check run during a dry run
+type PreflightCheck struct {
+	// Name of the check
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum={"Passed","Failed","Skipped"}
+	// Result of the check
+	Result PreflightCheckResult `json:"result"`
+
+	// Human readable message explaining the result
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// PreflightCheckResult is a Go string type.
+type PreflightCheckResult string
+
+const (
+	// PreflightCheckPassed defines a check which passed.
+	PreflightCheckPassed PreflightCheckResult = "Passed"
+	// PreflightCheckFailed defines a check which failed.
+	PreflightCheckFailed PreflightCheckResult = "Failed"
+	// PreflightCheckSkipped defines a check which did not apply to the upgrade.
+	PreflightCheckSkipped PreflightCheckResult = "Skipped"
+)
+
+const (
+	// PreflightCheckUpgradeConfigValid is the name of the preflight check validating the UpgradeConfig
+	PreflightCheckUpgradeConfigValid = "UpgradeConfigValid"
+	// PreflightCheckCanScale is the name of the preflight check probing whether compute capacity can be reserved
+	PreflightCheckCanScale = "ComputeCapacityReservable"
+)
+
+// NewPreflightReport returns a report of the given checks run for the given version,
+// which is ready if none of the checks failed
+func NewPreflightReport(version string, checks ...PreflightCheck) *PreflightReport {
+	report := &PreflightReport{
+		Version: version,
+		RunTime: metav1.Time{Time: time.Now()},
+		Ready:   true,
+		Checks:  checks,
+	}
+	for _, check := range checks {
+		if check.Result == PreflightCheckFailed {
+			report.Ready = false
+		}
+	}
+	return report
 }
 
 // UpgradeHistories is a slice of UpgradeHistory
@@ -85,11 +216,224 @@ type UpgradeHistory struct {
 	WorkerStartTime *metav1.Time `json:"workerStartTime,omitempty"`
 
 	WorkerCompleteTime *metav1.Time `json:"workerCompleteTime,omitempty"`
+
+	// Time the upgrade was paused, if it is currently paused
+	// +kubebuilder:validation:Optional
+	PausedTime *metav1.Time `json:"pausedTime,omitempty"`
+
+	// Total time the upgrade has spent paused since its start time, excluding any current pause
+	// +kubebuilder:validation:Optional
+	PausedDuration *metav1.Duration `json:"pausedDuration,omitempty"`
+
+	// MachineConfigPools records the upgrade progress of each non-master MachineConfigPool
+	// +kubebuilder:validation:Optional
+	MachineConfigPools []MachineConfigPoolHistory `json:"machineConfigPools,omitempty"`
+
+	// HealthChecks records the most recent result of each health check run by each upgrade step
+	// +kubebuilder:validation:Optional
+	HealthChecks []HealthCheckResult `json:"healthChecks,omitempty"`
+
+	// NodeDrains summarises the drain of each worker node drained during the upgrade
+	// +kubebuilder:validation:Optional
+	NodeDrains []NodeDrainSummary `json:"nodeDrains,omitempty"`
+}
+
+// HealthCheckResult records the outcome of a health check run during an upgrade
+type HealthCheckResult struct {
+	// Name of the health check
+	Name string `json:"name"`
+
+	// Upgrade step which ran the health check
+	Step UpgradeConditionType `json:"step"`
+
+	// +kubebuilder:validation:Enum={"Passed","Failed"}
+	// Result of the health check
+	Result HealthCheckStatus `json:"result"`
+
+	// Time the health check was run
+	Time metav1.Time `json:"time"`
+
+	// Time the health check last changed result
+	// +kubebuilder:validation:Optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Machine-readable reason for the health check failing
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+
+	// Human readable message explaining the result
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// Objects in the cluster which caused the health check to fail
+	// +kubebuilder:validation:Optional
+	AffectedObjects []HealthCheckObject `json:"affectedObjects,omitempty"`
+}
+
+// HealthCheckObject identifies an object in the cluster which caused a health check to fail
+type HealthCheckObject struct {
+	// Kind of the object, such as Node, PodDisruptionBudget, ClusterOperator or Alert
+	Kind string `json:"kind"`
+
+	// Namespace of the object, if it is namespaced
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object
+	Name string `json:"name"`
+}
+
+// HealthCheckStatus is a Go string type.
+type HealthCheckStatus string
+
+const (
+	// HealthCheckPassed defines a health check which passed.
+	HealthCheckPassed HealthCheckStatus = "Passed"
+	// HealthCheckFailed defines a health check which failed.
+	HealthCheckFailed HealthCheckStatus = "Failed"
+)
+
+const (
+	// HealthCheckCriticalAlerts is the name of the health check for firing critical alerts
+	HealthCheckCriticalAlerts = "CriticalAlerts"
+	// HealthCheckClusterOperators is the name of the health check for degraded ClusterOperators
+	HealthCheckClusterOperators = "ClusterOperators"
+	// HealthCheckManuallyCordonedNodes is the name of the health check for manually cordoned worker nodes
+	HealthCheckManuallyCordonedNodes = "ManuallyCordonedNodes"
+	// HealthCheckNodeUnschedulableTaints is the name of the health check for nodes under resource pressure
+	HealthCheckNodeUnschedulableTaints = "NodeUnschedulableTaints"
+	// HealthCheckPodDisruptionBudgets is the name of the health check for misconfigured PodDisruptionBudgets
+	HealthCheckPodDisruptionBudgets = "PodDisruptionBudgets"
+	// HealthCheckCapacityReservation is the name of the health check for whether compute capacity can be reserved
+	HealthCheckCapacityReservation = "CapacityReservation"
+	// HealthCheckAPIServerErrorRate is the name of the health check for the API server error rate
+	HealthCheckAPIServerErrorRate = "APIServerErrorRate"
+)
+
+// NodeDrainStatus records the drain strategies applied to a node while it was drained during an upgrade
+type NodeDrainStatus struct {
+	// Desired version of the upgrade during which the node was drained
+	Version string `json:"version"`
+
+	// Time the node was cordoned
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Drain strategies executed on the node
+	// +kubebuilder:validation:Optional
+	Strategies []DrainStrategyExecution `json:"strategies,omitempty"`
+
+	// Namespaced names of the PodDisruptionBudgets which blocked the eviction of pods from the node
+	// +kubebuilder:validation:Optional
+	BlockingPDBs []string `json:"blockingPDBs,omitempty"`
+
+	// Failed is true if the node has failed to drain within its timeout
+	// +kubebuilder:validation:Optional
+	Failed bool `json:"failed,omitempty"`
+}
+
+// DrainStrategyExecution records the executions of a drain strategy on a node
+type DrainStrategyExecution struct {
+	// Name of the drain strategy
+	Name string `json:"name"`
+
+	// Time the drain strategy was first executed on the node
+	FirstExecutionTime metav1.Time `json:"firstExecutionTime"`
+
+	// Time the drain strategy was last executed on the node
+	LastExecutionTime metav1.Time `json:"lastExecutionTime"`
+
+	// Namespaced names of the pods evicted by the drain strategy
+	// +kubebuilder:validation:Optional
+	PodsEvicted []string `json:"podsEvicted,omitempty"`
+
+	// Namespaced names of the pods deleted by the drain strategy
+	// +kubebuilder:validation:Optional
+	PodsDeleted []string `json:"podsDeleted,omitempty"`
+
+	// Namespaced names of the pods whose finalizers were removed by the drain strategy
+	// +kubebuilder:validation:Optional
+	FinalizersRemoved []string `json:"finalizersRemoved,omitempty"`
+
+	// Namespaced names of the pods none of whose finalizers the drain strategy was allowed to remove
+	// +kubebuilder:validation:Optional
+	FinalizersSkipped []string `json:"finalizersSkipped,omitempty"`
+}
+
+// NodeDrainSummary summarises the drain of a node during an upgrade
+type NodeDrainSummary struct {
+	// Name of the node
+	Node string `json:"node"`
+
+	// Time the node was cordoned
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Names of the drain strategies executed on the node, in order of first execution
+	// +kubebuilder:validation:Optional
+	Strategies []string `json:"strategies,omitempty"`
+
+	// Number of pods evicted from the node
+	// +kubebuilder:validation:Optional
+	PodsEvicted int `json:"podsEvicted,omitempty"`
+
+	// Number of pods deleted from the node
+	// +kubebuilder:validation:Optional
+	PodsDeleted int `json:"podsDeleted,omitempty"`
+
+	// Number of pods on the node whose finalizers were removed
+	// +kubebuilder:validation:Optional
+	FinalizersRemoved int `json:"finalizersRemoved,omitempty"`
+
+	// Namespaced names of the pods on the node none of whose finalizers were allowed to be removed
+	// +kubebuilder:validation:Optional
+	FinalizersSkipped []string `json:"finalizersSkipped,omitempty"`
+
+	// Namespaced names of the PodDisruptionBudgets which blocked the eviction of pods from the node
+	// +kubebuilder:validation:Optional
+	BlockingPDBs []string `json:"blockingPDBs,omitempty"`
+
+	// Failed is true if the node has failed to drain within its timeout
+	// +kubebuilder:validation:Optional
+	Failed bool `json:"failed,omitempty"`
+}
+
+// MachineConfigPoolHistory records the upgrade progress of a MachineConfigPool
+type MachineConfigPoolHistory struct {
+	// Name of the MachineConfigPool
+	Name string `json:"name"`
+
+	// Number of machines in the MachineConfigPool
+	MachineCount int32 `json:"machineCount"`
+
+	// Number of machines in the MachineConfigPool which have been updated
+	UpdatedMachineCount int32 `json:"updatedMachineCount"`
+
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	CompleteTime *metav1.Time `json:"completeTime,omitempty"`
 }
 
 // UpgradeConditionType is a Go string type.
 type UpgradeConditionType string
 
+const (
+	// UpgradeConditionReasonPaused is the reason set on the condition of the upgrade step that is
+	// currently paused.
+	UpgradeConditionReasonPaused = "Paused"
+	// UpgradeConditionReasonTimedOut is the reason set on the condition of an upgrade step that
+	// failed the upgrade by exceeding its maximum duration.
+	UpgradeConditionReasonTimedOut = "TimedOut"
+	// UpgradeConditionReasonSkipped is the reason set on the condition of an upgrade step that
+	// was skipped after exceeding its maximum duration.
+	UpgradeConditionReasonSkipped = "Skipped"
+	// UpgradeConditionReasonFailed is the reason set on the condition of an upgrade step that
+	// failed the upgrade.
+	UpgradeConditionReasonFailed = "Failed"
+)
+
 // UpgradeCondition houses fields that describe the state of an Upgrade including metadata.
 type UpgradeCondition struct {
 	// Type of upgrade condition
@@ -108,6 +452,12 @@ type UpgradeCondition struct {
 	// Complete time of this condition.
 	// +kubebuilder:validation:Optional
 	CompleteTime *metav1.Time `json:"completeTime,omitempty"`
+	// Time the upgrade was paused at this condition, if it is currently paused.
+	// +kubebuilder:validation:Optional
+	PausedTime *metav1.Time `json:"pausedTime,omitempty"`
+	// Total time the upgrade has spent paused at this condition since its start time, excluding any current pause.
+	// +kubebuilder:validation:Optional
+	PausedDuration *metav1.Duration `json:"pausedDuration,omitempty"`
 	// (brief) reason for the condition's last transition.
 	// +kubebuilder:validation:Optional
 	Reason string `json:"reason,omitempty"`
@@ -131,10 +481,18 @@ const (
 	CommenceUpgrade UpgradeConditionType = "UpgradeCommenced"
 	// ControlPlaneUpgraded is an UpgradeConditionType
 	ControlPlaneUpgraded UpgradeConditionType = "ControlPlaneUpgraded"
+	// ControlPlaneSoak is an UpgradeConditionType
+	ControlPlaneSoak UpgradeConditionType = "ControlPlaneSoaked"
 	// RemoveControlPlaneMaintWindow is an UpgradeConditionType
 	RemoveControlPlaneMaintWindow UpgradeConditionType = "ControlPlaneMaintenanceWindowRemoved"
 	// WorkersMaintWindow is an UpgradeConditionType
 	WorkersMaintWindow UpgradeConditionType = "WorkersMaintenanceWindowCreated"
+	// CanaryWorkerNodesUpgraded is an UpgradeConditionType
+	CanaryWorkerNodesUpgraded UpgradeConditionType = "CanaryWorkerNodesUpgraded"
+	// CanaryHealthCheck is an UpgradeConditionType
+	CanaryHealthCheck UpgradeConditionType = "CanaryWorkerNodesHealthy"
+	// ReleaseWorkerNodes is an UpgradeConditionType
+	ReleaseWorkerNodes UpgradeConditionType = "WorkerNodesReleased"
 	// AllWorkerNodesUpgraded is an UpgradeConditionType
 	AllWorkerNodesUpgraded UpgradeConditionType = "WorkerNodesUpgraded"
 	// RemoveExtraScaledNodes is an UpgradeConditionType
@@ -193,6 +551,16 @@ func (uc *UpgradeConfig) GetPDBDrainTimeoutDuration() time.Duration {
 	return time.Duration(uc.Spec.PDBForceDrainTimeout) * time.Minute
 }
 
+// IsPaused returns whether the upgrade has been requested to be paused
+func (uc *UpgradeConfig) IsPaused() bool {
+	return uc.Spec.Paused
+}
+
+// IsDryRun returns whether the upgrade has been requested to only be checked and not performed
+func (uc *UpgradeConfig) IsDryRun() bool {
+	return uc.Spec.DryRun
+}
+
 // GetHealthCheckDuration returns the duration to perform HealthCheck in hours
 func (uc *UpgradeConfig) GetHealthCheckDuration() time.Duration {
 	return time.Duration(time.Hour * 2)
@@ -362,6 +730,52 @@ func (histories *UpgradeHistories) SetHistory(history UpgradeHistory) {
 	}
 	*histories = append([]UpgradeHistory{history}, *histories...)
 }
+
+// GetMachineConfigPool returns the history of the named MachineConfigPool, or nil if there is none
+func (history *UpgradeHistory) GetMachineConfigPool(name string) *MachineConfigPoolHistory {
+	for _, pool := range history.MachineConfigPools {
+		if pool.Name == name {
+			return &pool
+		}
+	}
+	return nil
+}
+
+// SetMachineConfigPool adds or replaces the history of a MachineConfigPool, keeping the pools ordered by name
+func (history *UpgradeHistory) SetMachineConfigPool(pool MachineConfigPoolHistory) {
+	for i, p := range history.MachineConfigPools {
+		if p.Name == pool.Name {
+			history.MachineConfigPools[i] = pool
+			return
+		}
+	}
+	history.MachineConfigPools = append(history.MachineConfigPools, pool)
+	sort.Slice(history.MachineConfigPools, func(i, j int) bool {
+		return history.MachineConfigPools[i].Name < history.MachineConfigPools[j].Name
+	})
+}
+
+// GetHealthCheck returns the most recent result of the named health check run by the given upgrade step, or nil if there is none
+func (history *UpgradeHistory) GetHealthCheck(name string, step UpgradeConditionType) *HealthCheckResult {
+	for _, hc := range history.HealthChecks {
+		if hc.Name == name && hc.Step == step {
+			return &hc
+		}
+	}
+	return nil
+}
+
+// SetHealthCheck adds or replaces the result of a health check run by an upgrade step
+func (history *UpgradeHistory) SetHealthCheck(result HealthCheckResult) {
+	for i, hc := range history.HealthChecks {
+		if hc.Name == result.Name && hc.Step == result.Step {
+			history.HealthChecks[i] = result
+			return
+		}
+	}
+	history.HealthChecks = append(history.HealthChecks, result)
+}
+
 func init() {
 	SchemeBuilder.Register(&UpgradeConfig{}, &UpgradeConfigList{})
 }
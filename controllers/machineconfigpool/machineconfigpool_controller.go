@@ -63,17 +63,12 @@ func (r *ReconcileMachineConfigPool) Reconcile(ctx context.Context, request reco
 	if uc.Status.History != nil {
 		history := uc.Status.History.GetHistory(uc.Spec.Desired.Version)
 		if history != nil && history.Phase == upgradev1alpha1.UpgradePhaseUpgrading {
-			if instance.Status.UpdatedMachineCount == 0 {
-				if history.WorkerStartTime == nil {
-					history.WorkerStartTime = &metav1.Time{Time: time.Now()}
-				}
-			}
-
-			if instance.Status.MachineCount == instance.Status.UpdatedMachineCount {
-				if history.WorkerStartTime != nil && history.WorkerCompleteTime == nil {
-					history.WorkerCompleteTime = &metav1.Time{Time: time.Now()}
-				}
+			pools := &machineconfigapi.MachineConfigPoolList{}
+			err = r.Client.List(context.TODO(), pools)
+			if err != nil {
+				return reconcile.Result{}, err
 			}
+			updateWorkerHistory(history, pools.Items)
 			uc.Status.History.SetHistory(*history)
 			err = r.Client.Status().Update(context.TODO(), uc)
 			if err != nil {
@@ -85,6 +80,42 @@ func (r *ReconcileMachineConfigPool) Reconcile(ctx context.Context, request reco
 	return reconcile.Result{}, nil
 }
 
+// updateWorkerHistory records the progress of each worker MachineConfigPool in the
+// history, along with the start and completion of the worker upgrade as a whole
+func updateWorkerHistory(history *upgradev1alpha1.UpgradeHistory, pools []machineconfigapi.MachineConfigPool) {
+	allComplete := true
+	for _, pool := range pools {
+		if !isWorkerPool(pool.Name) {
+			continue
+		}
+		poolHistory := history.GetMachineConfigPool(pool.Name)
+		if poolHistory == nil {
+			poolHistory = &upgradev1alpha1.MachineConfigPoolHistory{Name: pool.Name}
+		}
+		poolHistory.MachineCount = pool.Status.MachineCount
+		poolHistory.UpdatedMachineCount = pool.Status.UpdatedMachineCount
+		if pool.Status.UpdatedMachineCount == 0 && poolHistory.StartTime == nil {
+			poolHistory.StartTime = &metav1.Time{Time: time.Now()}
+		}
+		if pool.Status.MachineCount == pool.Status.UpdatedMachineCount {
+			if poolHistory.StartTime != nil && poolHistory.CompleteTime == nil {
+				poolHistory.CompleteTime = &metav1.Time{Time: time.Now()}
+			}
+		}
+		history.SetMachineConfigPool(*poolHistory)
+
+		if poolHistory.StartTime != nil && history.WorkerStartTime == nil {
+			history.WorkerStartTime = &metav1.Time{Time: time.Now()}
+		}
+		if poolHistory.CompleteTime == nil {
+			allComplete = false
+		}
+	}
+	if allComplete && history.WorkerStartTime != nil && history.WorkerCompleteTime == nil {
+		history.WorkerCompleteTime = &metav1.Time{Time: time.Now()}
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ReconcileMachineConfigPool) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
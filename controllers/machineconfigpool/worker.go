@@ -5,6 +5,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	machineconfigapi "github.com/openshift/api/machineconfiguration/v1"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
 )
 
 func isWorkerPredicate() predicate.Predicate {
@@ -30,5 +31,5 @@ func isWorkerPredicate() predicate.Predicate {
 }
 
 func isWorkerPool(name string) bool {
-	return name == "worker"
+	return machinery.IsWorkerPool(name)
 }
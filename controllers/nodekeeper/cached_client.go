@@ -0,0 +1,51 @@
+package nodekeeper
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// podNodeNameField indexes the pods in the informer cache by the node they are scheduled to, so that
+// the pods of a node can be listed from the cache with a spec.nodeName field selector
+const podNodeNameField = "spec.nodeName"
+
+// cachedClient reads Nodes, Pods and PodDisruptionBudgets from the manager's informer cache, which is
+// kept up to date by watches, rather than from the API server. Every other object is read, and every
+// object is written, through the wrapped client.
+type cachedClient struct {
+	client.Client
+	cache client.Reader
+}
+
+// newCachedClient returns a client reading Nodes, Pods and PodDisruptionBudgets from the cache
+func newCachedClient(c client.Client, cache client.Reader) client.Client {
+	return &cachedClient{Client: c, cache: cache}
+}
+
+func (c *cachedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	switch obj.(type) {
+	case *corev1.Node, *corev1.Pod, *policyv1.PodDisruptionBudget:
+		return c.cache.Get(ctx, key, obj, opts...)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *cachedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	switch list.(type) {
+	case *corev1.NodeList, *corev1.PodList, *policyv1.PodDisruptionBudgetList:
+		return c.cache.List(ctx, list, opts...)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// podNodeName is the indexer of the pods by the node they are scheduled to
+func podNodeName(obj client.Object) []string {
+	p, ok := obj.(*corev1.Pod)
+	if !ok || p.Spec.NodeName == "" {
+		return nil
+	}
+	return []string{p.Spec.NodeName}
+}
@@ -3,6 +3,7 @@ package nodekeeper
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -15,10 +16,13 @@ import (
 	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -37,6 +41,10 @@ type ReconcileNodeKeeper struct {
 	DrainstrategyBuilder        drain.NodeDrainStrategyBuilder
 	UpgradeConfigManagerBuilder upgradeconfigmanager.UpgradeConfigManagerBuilder
 	Scheme                      *runtime.Scheme
+
+	// mutex guards the metrics client, which is built once and shared by every reconcile
+	mutex         sync.Mutex
+	metricsClient metrics.Metrics
 }
 
 // Reconcile Note:
@@ -57,7 +65,7 @@ func (r *ReconcileNodeKeeper) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, err
 	}
 
-	upgradeResult, err := r.Machinery.IsUpgrading(r.Client, "worker")
+	upgradeResult, err := r.Machinery.IsWorkerPoolsUpgrading(r.Client)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -73,13 +81,14 @@ func (r *ReconcileNodeKeeper) Reconcile(ctx context.Context, request reconcile.R
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Node was deleted - reset the metric for this node to prevent stale alerts
-			metricsClient, err := r.MetricsClientBuilder.NewClient(r.Client)
+			metricsClient, err := r.getMetricsClient()
 			if err != nil {
 				reqLogger.Error(err, "failed to create metrics client for resetting NodeDrainFailed", "node", request.Name)
 				return reconcile.Result{}, nil
 			}
 			reqLogger.Info(fmt.Sprintf("Node %s deleted, resetting NodeDrainFailed metric", request.Name))
 			metricsClient.ResetMetricNodeDrainFailed(request.Name)
+			metricsClient.ResetMetricNodeDrainPodsHeld(request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -87,15 +96,22 @@ func (r *ReconcileNodeKeeper) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	result := r.Machinery.IsNodeCordoned(node)
-	metricsClient, err := r.MetricsClientBuilder.NewClient(r.Client)
+	metricsClient, err := r.getMetricsClient()
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 	if !result.IsCordoned {
 		metricsClient.ResetMetricNodeDrainFailed(node.Name)
+		metricsClient.ResetMetricNodeDrainPodsHeld(node.Name)
 		return reconcile.Result{}, nil
 	}
 
+	// Drain strategies are not executed while the upgrade is paused
+	if uc.IsPaused() {
+		reqLogger.Info(fmt.Sprintf("Upgrade is paused, not executing drain strategies for node %s", node.Name))
+		return reconcile.Result{RequeueAfter: time.Minute * 1}, nil
+	}
+
 	target := config.CMTarget{}
 	cmTarget, err := target.NewCMTarget()
 	if err != nil {
@@ -129,6 +145,8 @@ func (r *ReconcileNodeKeeper) Reconcile(ctx context.Context, request reconcile.R
 			return reconcile.Result{}, err
 		}
 		r.NodeDrainResult(node, reqLogger, hasFailed, metricsClient)
+		r.recordNodeDrainStatus(node, reqLogger, uc, result, res, hasFailed)
+		return reconcile.Result{RequeueAfter: drainStrategy.GetRequeueAfter(node)}, nil
 	} else {
 		drainStrategy, err := r.DrainstrategyBuilder.NewDefaultNodeDrainStrategy(r.Client, reqLogger, uc, &cfg.NodeDrain)
 		if err != nil {
@@ -140,19 +158,56 @@ func (r *ReconcileNodeKeeper) Reconcile(ctx context.Context, request reconcile.R
 			return reconcile.Result{}, err
 		}
 		r.NodeDrainResult(node, reqLogger, hasFailed, metricsClient)
+		r.recordNodeDrainStatus(node, reqLogger, uc, result, nil, hasFailed)
+		return reconcile.Result{RequeueAfter: drainStrategy.GetRequeueAfter(node)}, nil
 	}
-
-	return reconcile.Result{RequeueAfter: time.Minute * 1}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager. Nodes, Pods and PodDisruptionBudgets
+// are read from the manager's informer cache, and the nodes being drained are reconciled as their
+// drains progress rather than polled.
 func (r *ReconcileNodeKeeper) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.TODO(), &corev1.Pod{}, podNodeNameField, podNodeName)
+	if err != nil {
+		return err
+	}
+	r.Client = newCachedClient(r.Client, mgr.GetCache())
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Node{}).
-		WithEventFilter(IgnoreMasterPredicate()).
+		For(&corev1.Node{}, builder.WithPredicates(IgnoreMasterPredicate(), NodeChangedPredicate())).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.cordonedNodes),
+			builder.WithPredicates(IgnoreMasterPredicate(), NodeUncordonedPredicate())).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podNode),
+			builder.WithPredicates(PodDrainChangedPredicate())).
+		Watches(&policyv1.PodDisruptionBudget{}, handler.EnqueueRequestsFromMapFunc(r.cordonedNodes),
+			builder.WithPredicates(DisruptionsAllowedPredicate())).
 		Complete(r)
 }
 
+// getMetricsClient returns the metrics client shared by every reconcile, building it on first use
+func (r *ReconcileNodeKeeper) getMetricsClient() (metrics.Metrics, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.metricsClient == nil {
+		metricsClient, err := r.MetricsClientBuilder.NewClient(r.Client)
+		if err != nil {
+			return nil, err
+		}
+		r.metricsClient = metricsClient
+	}
+	return r.metricsClient, nil
+}
+
+// recordNodeDrainStatus persists the progress of the node's drain on the node. A failure to record
+// it is logged rather than holding up the drain.
+func (r *ReconcileNodeKeeper) recordNodeDrainStatus(node *corev1.Node, reqLogger logr.Logger, uc *upgradev1alpha1.UpgradeConfig,
+	cordoned *machinery.IsCordonedResult, res []*drain.DrainStrategyResult, hasFailed bool) {
+	err := drain.RecordNodeDrainStatus(r.Client, node, uc.Spec.Desired.Version, cordoned.AddedAt, res, hasFailed)
+	if err != nil {
+		reqLogger.Error(err, fmt.Sprintf("failed to record the drain status of node %s", node.Name))
+	}
+}
+
 // Check the NodeDrainResult
 func (r *ReconcileNodeKeeper) NodeDrainResult(node *corev1.Node, reqLogger logr.Logger, hasFailed bool, metricsClient metrics.Metrics) {
 
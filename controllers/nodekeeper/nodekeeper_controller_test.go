@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
@@ -71,13 +72,13 @@ var _ = Describe("NodeKeeperController", func() {
 
 	JustBeforeEach(func() {
 		reconciler = &ReconcileNodeKeeper{
-			mockKubeClient,
-			mockConfigManagerBuilder,
-			mockMachineryClient,
-			mockMetricsBuilder,
-			mockDrainStrategyBuilder,
-			mockUpgradeConfigManagerBuilder,
-			runtime.NewScheme(),
+			Client:                      mockKubeClient,
+			ConfigManagerBuilder:        mockConfigManagerBuilder,
+			Machinery:                   mockMachineryClient,
+			MetricsClientBuilder:        mockMetricsBuilder,
+			DrainstrategyBuilder:        mockDrainStrategyBuilder,
+			UpgradeConfigManagerBuilder: mockUpgradeConfigManagerBuilder,
+			Scheme:                      runtime.NewScheme(),
 		}
 	})
 
@@ -96,7 +97,7 @@ var _ = Describe("NodeKeeperController", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockKubeClient.EXPECT().Get(gomock.Any(), testNodeName, gomock.Any()).Times(0),
 				)
 				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
@@ -107,7 +108,7 @@ var _ = Describe("NodeKeeperController", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: false}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: false}}, nil),
 					mockKubeClient.EXPECT().Get(gomock.Any(), testNodeName, gomock.Any()).Times(0),
 				)
 				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
@@ -131,7 +132,7 @@ var _ = Describe("NodeKeeperController", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockKubeClient.EXPECT().Get(gomock.Any(), testNodeName, gomock.Any()).Times(1),
 					mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}}),
 					mockMetricsBuilder.EXPECT().NewClient(gomock.Any()).Return(mockMetricsClient, nil),
@@ -142,10 +143,42 @@ var _ = Describe("NodeKeeperController", func() {
 					mockMachineryClient.EXPECT().IsNodeUpgrading(gomock.Any()).Return(true),
 					mockMetricsClient.EXPECT().UpdateMetricNodeDrainFailed(gomock.Any()).Times(1),
 					mockMetricsClient.EXPECT().ResetMetricNodeDrainFailed(gomock.Any()).Times(0),
+					mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+							status, err := drain.GetNodeDrainStatus(obj.(*corev1.Node))
+							Expect(err).NotTo(HaveOccurred())
+							Expect(status.Version).To(Equal(uc.Spec.Desired.Version))
+							Expect(status.Failed).To(BeTrue())
+							return nil
+						}),
+					mockDrainStrategy.EXPECT().GetRequeueAfter(gomock.Any()).Return(3*time.Minute),
 				)
 				result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(result.RequeueAfter).To(Not(BeNil()))
+				Expect(result.RequeueAfter).To(Equal(3 * time.Minute))
+			})
+		})
+
+		Context("When the upgrade is paused", func() {
+			var uc upgradev1alpha1.UpgradeConfig
+			BeforeEach(func() {
+				uc = *testStructs.NewUpgradeConfigBuilder().WithNamespacedName(upgradeConfigName).WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+				uc.Spec.Paused = true
+			})
+			It("should not execute any drain strategies", func() {
+				gomock.InOrder(
+					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
+					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
+					mockKubeClient.EXPECT().Get(gomock.Any(), testNodeName, gomock.Any()).Times(1),
+					mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}}),
+					mockMetricsBuilder.EXPECT().NewClient(gomock.Any()).Return(mockMetricsClient, nil),
+					mockDrainStrategyBuilder.EXPECT().NewNodeDrainStrategy(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0),
+					mockDrainStrategyBuilder.EXPECT().NewDefaultNodeDrainStrategy(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0),
+				)
+				result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(time.Minute))
 			})
 		})
 
@@ -164,7 +197,7 @@ var _ = Describe("NodeKeeperController", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockKubeClient.EXPECT().Get(gomock.Any(), testNodeName, gomock.Any()).Times(1),
 					mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}}),
 					mockMetricsBuilder.EXPECT().NewClient(gomock.Any()).Return(mockMetricsClient, nil),
@@ -176,20 +209,30 @@ var _ = Describe("NodeKeeperController", func() {
 					mockMachineryClient.EXPECT().IsNodeUpgrading(gomock.Any()).Return(true),
 					mockMetricsClient.EXPECT().UpdateMetricNodeDrainFailed(gomock.Any()).Times(1),
 					mockMetricsClient.EXPECT().ResetMetricNodeDrainFailed(gomock.Any()).Times(0),
+					mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+							status, err := drain.GetNodeDrainStatus(obj.(*corev1.Node))
+							Expect(err).NotTo(HaveOccurred())
+							Expect(status.Version).To(Equal(uc.Spec.Desired.Version))
+							Expect(status.Failed).To(BeTrue())
+							return nil
+						}),
+					mockDrainStrategy.EXPECT().GetRequeueAfter(gomock.Any()).Return(3*time.Minute),
 				)
 				result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(result.RequeueAfter).To(Not(BeNil()))
+				Expect(result.RequeueAfter).To(Equal(3 * time.Minute))
 			})
 			It("should reset any alerts once node is not cordoned", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockKubeClient.EXPECT().Get(gomock.Any(), testNodeName, gomock.Any()).Times(1),
 					mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: false}),
 					mockMetricsBuilder.EXPECT().NewClient(gomock.Any()).Return(mockMetricsClient, nil),
 					mockMetricsClient.EXPECT().ResetMetricNodeDrainFailed(gomock.Any()).Times(1),
+					mockMetricsClient.EXPECT().ResetMetricNodeDrainPodsHeld(gomock.Any()).Times(1),
 					mockMetricsClient.EXPECT().UpdateMetricNodeDrainFailed(gomock.Any()).Times(0),
 				)
 				result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
@@ -218,7 +261,7 @@ var _ = Describe("NodeKeeperController", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(mockKubeClient, "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(mockKubeClient).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockKubeClient.EXPECT().Get(context.TODO(), testNodeName, gomock.Any()).SetArg(2, node),
 					mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}}),
 					mockMetricsBuilder.EXPECT().NewClient(gomock.Any()).Return(mockMetricsClient, nil),
@@ -230,20 +273,23 @@ var _ = Describe("NodeKeeperController", func() {
 					mockMetricsClient.EXPECT().ResetMetricNodeDrainFailed(gomock.Any()).Times(1),
 					mockMachineryClient.EXPECT().IsNodeUpgrading(gomock.Any()).Times(0),
 					mockMetricsClient.EXPECT().UpdateMetricNodeDrainFailed(gomock.Any()).Times(0),
+					mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()),
+					mockDrainStrategy.EXPECT().GetRequeueAfter(gomock.Any()).Return(time.Duration(0)),
 				)
 				result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(result.RequeueAfter).To(Not(BeNil()))
+				Expect(result.RequeueAfter).To(BeZero())
 			})
 			It("should reset NodeDrainFailed metric when node is deleted (NotFound)", func() {
 				notFoundErr := errors.NewNotFound(corev1.Resource("nodes"), testNodeName.Name)
 				gomock.InOrder(
 					mockUpgradeConfigManagerBuilder.EXPECT().NewManager(gomock.Any()).Return(mockUpgradeConfigManager, nil),
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMachineryClient.EXPECT().IsUpgrading(mockKubeClient, "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(mockKubeClient).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockKubeClient.EXPECT().Get(context.TODO(), testNodeName, gomock.Any()).Return(notFoundErr),
 					mockMetricsBuilder.EXPECT().NewClient(gomock.Any()).Return(mockMetricsClient, nil),
 					mockMetricsClient.EXPECT().ResetMetricNodeDrainFailed(testNodeName.Name).Times(1),
+					mockMetricsClient.EXPECT().ResetMetricNodeDrainPodsHeld(testNodeName.Name).Times(1),
 				)
 				result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: testNodeName})
 				Expect(err).NotTo(HaveOccurred())
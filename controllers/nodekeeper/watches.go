@@ -0,0 +1,158 @@
+package nodekeeper
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/drain"
+)
+
+// NodeChangedPredicate ignores the updates of a node which only change its status or the drain status
+// recorded on it by the controller, so that recording the drain status does not trigger a reconcile.
+// Periodic resyncs of the node are let through.
+func NodeChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			if oldNode.ResourceVersion == newNode.ResourceVersion {
+				return true
+			}
+			return !reflect.DeepEqual(oldNode.Spec, newNode.Spec) ||
+				!reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+				!reflect.DeepEqual(withoutDrainStatus(oldNode.Annotations), withoutDrainStatus(newNode.Annotations)) ||
+				!oldNode.DeletionTimestamp.Equal(newNode.DeletionTimestamp)
+		},
+	}
+}
+
+// NodeUncordonedPredicate selects the nodes being uncordoned or deleted, either of which ends the
+// node's drain and so may let another node being drained escalate its drain
+func NodeUncordonedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			return oldNode.Spec.Unschedulable && !newNode.Spec.Unschedulable
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// PodDrainChangedPredicate selects the pods whose drain may have changed: pods newly scheduled to a node,
+// and pods whose annotations, finalizers or deletion have changed. Pre-drain hooks and drain policies are
+// driven by pod annotations.
+func PodDrainChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, ok := e.ObjectOld.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			newPod, ok := e.ObjectNew.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			return !reflect.DeepEqual(oldPod.Annotations, newPod.Annotations) ||
+				!reflect.DeepEqual(oldPod.Finalizers, newPod.Finalizers) ||
+				!oldPod.DeletionTimestamp.Equal(newPod.DeletionTimestamp)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// DisruptionsAllowedPredicate selects the PodDisruptionBudgets which may now allow pods they refused to
+// be evicted: budgets allowing more disruptions than before, and deleted budgets
+func DisruptionsAllowedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPdb, ok := e.ObjectOld.(*policyv1.PodDisruptionBudget)
+			if !ok {
+				return false
+			}
+			newPdb, ok := e.ObjectNew.(*policyv1.PodDisruptionBudget)
+			if !ok {
+				return false
+			}
+			return newPdb.Status.DisruptionsAllowed > oldPdb.Status.DisruptionsAllowed
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// cordonedNodes returns a request for every cordoned worker node
+func (r *ReconcileNodeKeeper) cordonedNodes(ctx context.Context, _ client.Object) []reconcile.Request {
+	nodes := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodes); err != nil {
+		log.Error(err, "failed to list the nodes to reconcile")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable && !hasMasterLabel(node.Labels) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: node.Name}})
+		}
+	}
+	return requests
+}
+
+// podNode returns a request for the node the pod is scheduled to, if the node is cordoned
+func (r *ReconcileNodeKeeper) podNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	p, ok := obj.(*corev1.Pod)
+	if !ok || p.Spec.NodeName == "" {
+		return nil
+	}
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: p.Spec.NodeName}, node); err != nil {
+		return nil
+	}
+	if !node.Spec.Unschedulable || hasMasterLabel(node.Labels) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: node.Name}}}
+}
+
+// withoutDrainStatus returns the annotations other than the drain status annotation
+func withoutDrainStatus(annotations map[string]string) map[string]string {
+	result := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if k != drain.NodeDrainStatusAnnotation {
+			result[k] = v
+		}
+	}
+	return result
+}
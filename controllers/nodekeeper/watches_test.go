@@ -0,0 +1,178 @@
+package nodekeeper
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/drain"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NodeKeeperController watches", func() {
+
+	var (
+		mockCtrl       *gomock.Controller
+		mockKubeClient *mocks.MockClient
+		mockCache      *mocks.MockClient
+		oldNode        *corev1.Node
+		newNode        *corev1.Node
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockCache = mocks.NewMockClient(mockCtrl)
+		oldNode = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", ResourceVersion: "1"}}
+		newNode = oldNode.DeepCopy()
+		newNode.ResourceVersion = "2"
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("NodeChangedPredicate", func() {
+		It("ignores updates which only change the recorded drain status", func() {
+			newNode.Annotations = map[string]string{drain.NodeDrainStatusAnnotation: "{}"}
+			newNode.Status.Phase = corev1.NodeRunning
+			Expect(NodeChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeFalse())
+		})
+		It("allows updates which cordon the node", func() {
+			newNode.Spec.Unschedulable = true
+			Expect(NodeChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeTrue())
+		})
+		It("allows updates of other annotations", func() {
+			newNode.Annotations = map[string]string{machinery.MachineConfigDaemonStateAnnotationKey: machinery.MachineConfigDaemonStateWorking}
+			Expect(NodeChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeTrue())
+		})
+		It("allows periodic resyncs", func() {
+			Expect(NodeChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: oldNode.DeepCopy()})).To(BeTrue())
+		})
+	})
+
+	Context("NodeUncordonedPredicate", func() {
+		It("allows updates which uncordon the node", func() {
+			oldNode.Spec.Unschedulable = true
+			Expect(NodeUncordonedPredicate().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeTrue())
+		})
+		It("ignores updates which cordon the node", func() {
+			newNode.Spec.Unschedulable = true
+			Expect(NodeUncordonedPredicate().Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeFalse())
+		})
+		It("allows deleted nodes", func() {
+			Expect(NodeUncordonedPredicate().Delete(event.DeleteEvent{Object: oldNode})).To(BeTrue())
+		})
+	})
+
+	Context("PodDrainChangedPredicate", func() {
+		var oldPod, newPod *corev1.Pod
+		BeforeEach(func() {
+			oldPod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+			newPod = oldPod.DeepCopy()
+		})
+		It("allows updates of the pod's annotations", func() {
+			newPod.Annotations = map[string]string{drain.DrainPolicyAnnotation: drain.DrainPolicyManual}
+			Expect(PodDrainChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+		})
+		It("allows pods being deleted", func() {
+			newPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			Expect(PodDrainChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+		})
+		It("ignores status updates", func() {
+			newPod.Status.Phase = corev1.PodRunning
+			Expect(PodDrainChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeFalse())
+		})
+		It("ignores deleted pods", func() {
+			Expect(PodDrainChangedPredicate().Delete(event.DeleteEvent{Object: oldPod})).To(BeFalse())
+		})
+	})
+
+	Context("DisruptionsAllowedPredicate", func() {
+		var oldPdb, newPdb *policyv1.PodDisruptionBudget
+		BeforeEach(func() {
+			oldPdb = &policyv1.PodDisruptionBudget{Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0}}
+			newPdb = oldPdb.DeepCopy()
+		})
+		It("allows budgets allowing more disruptions", func() {
+			newPdb.Status.DisruptionsAllowed = 1
+			Expect(DisruptionsAllowedPredicate().Update(event.UpdateEvent{ObjectOld: oldPdb, ObjectNew: newPdb})).To(BeTrue())
+		})
+		It("ignores budgets allowing fewer disruptions", func() {
+			oldPdb.Status.DisruptionsAllowed = 1
+			Expect(DisruptionsAllowedPredicate().Update(event.UpdateEvent{ObjectOld: oldPdb, ObjectNew: newPdb})).To(BeFalse())
+		})
+		It("allows deleted budgets", func() {
+			Expect(DisruptionsAllowedPredicate().Delete(event.DeleteEvent{Object: oldPdb})).To(BeTrue())
+		})
+	})
+
+	Context("Mapping events to the nodes being drained", func() {
+		var reconciler *ReconcileNodeKeeper
+		BeforeEach(func() {
+			reconciler = &ReconcileNodeKeeper{Client: mockKubeClient}
+		})
+		It("requests the cordoned worker nodes", func() {
+			nodes := corev1.NodeList{Items: []corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "schedulable"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "master", Labels: map[string]string{machinery.MasterLabel: ""}}, Spec: corev1.NodeSpec{Unschedulable: true}},
+			}}
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, nodes)
+			requests := reconciler.cordonedNodes(context.TODO(), &policyv1.PodDisruptionBudget{})
+			Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Name: "cordoned"}}}))
+		})
+		It("requests the cordoned node of a pod", func() {
+			p := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "cordoned"}}
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+			mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "cordoned"}, gomock.Any()).SetArg(2, node)
+			Expect(reconciler.podNode(context.TODO(), p)).To(Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Name: "cordoned"}}}))
+		})
+		It("does not request the node of a pod if it is not cordoned", func() {
+			p := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "schedulable"}}
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "schedulable"}}
+			mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "schedulable"}, gomock.Any()).SetArg(2, node)
+			Expect(reconciler.podNode(context.TODO(), p)).To(BeEmpty())
+		})
+		It("does not request a node for an unscheduled pod", func() {
+			Expect(reconciler.podNode(context.TODO(), &corev1.Pod{})).To(BeEmpty())
+		})
+	})
+
+	Context("Reading through the cache", func() {
+		var c client.Client
+		BeforeEach(func() {
+			c = newCachedClient(mockKubeClient, mockCache)
+		})
+		It("reads nodes, pods and PodDisruptionBudgets from the cache", func() {
+			mockCache.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any())
+			mockCache.EXPECT().List(gomock.Any(), gomock.Any())
+			mockCache.EXPECT().List(gomock.Any(), gomock.Any())
+			Expect(c.Get(context.TODO(), types.NamespacedName{Name: "node"}, &corev1.Node{})).To(Succeed())
+			Expect(c.List(context.TODO(), &corev1.PodList{})).To(Succeed())
+			Expect(c.List(context.TODO(), &policyv1.PodDisruptionBudgetList{})).To(Succeed())
+		})
+		It("reads other objects from the API server", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any())
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any())
+			Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "ns", Name: "cm"}, &corev1.ConfigMap{})).To(Succeed())
+			Expect(c.List(context.TODO(), &corev1.SecretList{})).To(Succeed())
+		})
+		It("indexes pods by the node they are scheduled to", func() {
+			Expect(podNodeName(&corev1.Pod{Spec: corev1.PodSpec{NodeName: "node"}})).To(Equal([]string{"node"}))
+			Expect(podNodeName(&corev1.Pod{})).To(BeEmpty())
+		})
+	})
+})
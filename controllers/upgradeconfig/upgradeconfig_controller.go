@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-multierror"
+	configv1 "github.com/openshift/api/config/v1"
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
 	muocfg "github.com/openshift/managed-upgrade-operator/config"
 	"github.com/openshift/managed-upgrade-operator/pkg/clusterversion"
@@ -151,6 +152,12 @@ func (r *ReconcileUpgradeConfig) Reconcile(ctx context.Context, request reconcil
 	status := history.Phase
 	reqLogger.Info("Current cluster status", "status", status)
 
+	// A dry run only checks whether the upgrade could commence, so it applies
+	// until the upgrade has commenced.
+	if instance.IsDryRun() && (status == upgradev1alpha1.UpgradePhaseNew || status == upgradev1alpha1.UpgradePhasePending) {
+		return r.preflight(ctx, upgrader, cfm, instance, clusterVersion, reqLogger)
+	}
+
 	switch status {
 
 	// "New" UpgradePhase is when an upgrade is scheduled.
@@ -175,6 +182,8 @@ func (r *ReconcileUpgradeConfig) Reconcile(ctx context.Context, request reconcil
 			if err != nil || !result {
 				reqLogger.Error(err, "Pre HealthCheck failed on scheduling upgrade")
 			}
+			// The health checks record their results in the history
+			history = instance.Status.History.GetHistory(instance.Spec.Desired.Version)
 		} else {
 			reqLogger.Info("Skipping PreHealthCheck")
 		}
@@ -194,6 +203,11 @@ func (r *ReconcileUpgradeConfig) Reconcile(ctx context.Context, request reconcil
 	// begin or not. When it is ready to begin, it will sync the latest changes from
 	// configmanager (as relevant) and proceed to "Upgrading" phase.
 	case upgradev1alpha1.UpgradePhasePending:
+		if instance.IsPaused() {
+			reqLogger.Info("UpgradeConfig is paused, upgrade will not commence until it is resumed")
+			return reconcile.Result{}, nil
+		}
+
 		reqLogger.Info("Validating UpgradeConfig")
 
 		// Build a Validator
@@ -260,6 +274,10 @@ func (r *ReconcileUpgradeConfig) Reconcile(ctx context.Context, request reconcil
 			return r.upgradeCluster(upgrader, instance, reqLogger)
 		}
 
+		if schedulerResult.IsFrozen {
+			reqLogger.Info("Upgrade start falls within a freeze window and has been deferred", "reason", schedulerResult.FreezeReason, "nextEligibleStart", schedulerResult.NextEligibleStart)
+		}
+
 		history.Phase = upgradev1alpha1.UpgradePhasePending
 		instance.Status.History.SetHistory(*history)
 		err = r.Client.Status().Update(context.TODO(), instance)
@@ -276,6 +294,10 @@ func (r *ReconcileUpgradeConfig) Reconcile(ctx context.Context, request reconcil
 
 		return reconcile.Result{}, nil
 
+	// "Upgrading" UpgradePhase is when an upgrade is in progress.
+	//
+	// If the UpgradeConfig is paused, the upgrader will not run any further
+	// steps until it is resumed.
 	case upgradev1alpha1.UpgradePhaseUpgrading:
 		reqLogger.Info("Cluster detected as already upgrading.")
 		return r.upgradeCluster(upgrader, instance, reqLogger)
@@ -311,6 +333,48 @@ func (r *ReconcileUpgradeConfig) upgradeCluster(upgrader cub.ClusterUpgrader, uc
 	return reconcile.Result{RequeueAfter: 1 * time.Minute}, me.ErrorOrNil()
 }
 
+// preflight validates the UpgradeConfig and runs the pre-upgrade checks without
+// commencing the upgrade, recording the results in the UpgradeConfig's status
+func (r *ReconcileUpgradeConfig) preflight(ctx context.Context, upgrader cub.ClusterUpgrader, cfm configmanager.ConfigManager, uc *upgradev1alpha1.UpgradeConfig, clusterVersion *configv1.ClusterVersion, logger logr.Logger) (reconcile.Result, error) {
+	logger.Info("UpgradeConfig is a dry run, running preflight checks without commencing the upgrade")
+
+	validator, err := r.ValidationBuilder.NewClient(cfm)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	validCheck := upgradev1alpha1.PreflightCheck{
+		Name:   upgradev1alpha1.PreflightCheckUpgradeConfigValid,
+		Result: upgradev1alpha1.PreflightCheckPassed,
+	}
+	validatorResult, err := validator.IsValidUpgradeConfig(r.Client, uc, clusterVersion, logger)
+	switch {
+	case err != nil:
+		validCheck.Result = upgradev1alpha1.PreflightCheckFailed
+		validCheck.Message = err.Error()
+	case !validatorResult.IsValid || !validatorResult.IsAvailableUpdate:
+		validCheck.Result = upgradev1alpha1.PreflightCheckFailed
+		validCheck.Message = validatorResult.Message
+	}
+
+	checks, err := upgrader.Preflight(ctx, uc, logger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	report := upgradev1alpha1.NewPreflightReport(uc.Spec.Desired.Version, append([]upgradev1alpha1.PreflightCheck{validCheck}, checks...)...)
+	logger.Info("Preflight checks completed", "ready", report.Ready)
+
+	uc.Status.Preflight = report
+	err = r.Client.Status().Update(context.TODO(), uc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Status updates don't trigger a reconcile, so requeue to keep the report current
+	return reconcile.Result{RequeueAfter: muocfg.SyncPeriodDefault}, nil
+}
+
 // reportUpgradeMetrics updates prometheus with statistics from the latest upgrade
 func reportUpgradeMetrics(metricsClient metrics.Metrics, name string, precedingVersion string, version string, upgradeStart time.Time, upgradeEnd time.Time) error {
 	upgradeAlerts, err := metricsClient.AlertsFromUpgrade(upgradeStart, upgradeEnd)
@@ -324,6 +388,8 @@ func reportUpgradeMetrics(metricsClient metrics.Metrics, name string, precedingV
 	}
 
 	metricsClient.UpdateMetricUpgradeResult(name, precedingVersion, version, minorUpgrade, upgradeAlerts)
+	// Steps which exceeded their maximum duration no longer matter once the upgrade has completed
+	metricsClient.ResetAllMetricUpgradeStepTimeout()
 	return nil
 }
 
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	muocfg "github.com/openshift/managed-upgrade-operator/config"
 	cvMocks "github.com/openshift/managed-upgrade-operator/pkg/clusterversion/mocks"
 	configMocks "github.com/openshift/managed-upgrade-operator/pkg/configmanager/mocks"
 	dvomocks "github.com/openshift/managed-upgrade-operator/pkg/dvo/mocks"
@@ -892,6 +893,77 @@ var _ = Describe("UpgradeConfigController", func() {
 				})
 			})
 
+			Context("When the UpgradeConfig is a dry run", func() {
+				var updated *upgradev1alpha1.UpgradeConfig
+				BeforeEach(func() {
+					upgradeConfig.Spec.DryRun = true
+					upgradeConfig.Status.History[0].Phase = upgradev1alpha1.UpgradePhasePending
+					updated = nil
+				})
+
+				expectPreflight := func(validatorResult validation.ValidatorResult, checks []upgradev1alpha1.PreflightCheck) {
+					gomock.InOrder(
+						mockEMBuilder.EXPECT().NewManager(gomock.Any()).Return(mockEMClient, nil),
+						mockKubeClient.EXPECT().Get(gomock.Any(), upgradeConfigName, gomock.Any()).SetArg(2, *upgradeConfig),
+						mockCVClientBuilder.EXPECT().New(gomock.Any()).Return(mockCVClient),
+						mockCVClient.EXPECT().GetClusterVersion().Return(testClusterVersion, nil),
+						mockConfigManagerBuilder.EXPECT().New(gomock.Any(), gomock.Any()).Return(mockConfigManager),
+						mockConfigManager.EXPECT().Into(gomock.Any()).SetArg(0, cfg),
+						mockClusterUpgraderBuilder.EXPECT().NewClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), upgradeConfig.Spec.Type).Return(mockClusterUpgrader, nil),
+						mockValidationBuilder.EXPECT().NewClient(mockConfigManager).Return(mockValidator, nil),
+						mockValidator.EXPECT().IsValidUpgradeConfig(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(validatorResult, nil),
+						mockClusterUpgrader.EXPECT().Preflight(gomock.Any(), gomock.Any(), gomock.Any()).Return(checks, nil),
+						mockKubeClient.EXPECT().Status().Return(mockUpdater),
+						mockUpdater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+							func(ctx context.Context, obj *upgradev1alpha1.UpgradeConfig, opts ...interface{}) error {
+								updated = obj
+								return nil
+							}),
+					)
+				}
+
+				It("records the preflight report without commencing the upgrade", func() {
+					expectPreflight(validation.ValidatorResult{IsValid: true, IsAvailableUpdate: true}, []upgradev1alpha1.PreflightCheck{
+						{Name: string(upgradev1alpha1.IsClusterUpgradable), Result: upgradev1alpha1.PreflightCheckPassed},
+						{Name: upgradev1alpha1.PreflightCheckCanScale, Result: upgradev1alpha1.PreflightCheckSkipped},
+					})
+					mockScheduler.EXPECT().IsReadyToUpgrade(gomock.Any(), gomock.Any()).Times(0)
+					mockClusterUpgrader.EXPECT().UpgradeCluster(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+					result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: upgradeConfigName})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(muocfg.SyncPeriodDefault))
+					Expect(updated.Status.History.GetHistory(version).Phase).To(Equal(upgradev1alpha1.UpgradePhasePending))
+					Expect(updated.Status.Preflight).NotTo(BeNil())
+					Expect(updated.Status.Preflight.Version).To(Equal(version))
+					Expect(updated.Status.Preflight.Ready).To(BeTrue())
+					Expect(updated.Status.Preflight.Checks).To(HaveLen(3))
+					Expect(updated.Status.Preflight.Checks[0].Name).To(Equal(upgradev1alpha1.PreflightCheckUpgradeConfigValid))
+				})
+
+				It("reports the upgrade as not ready if the UpgradeConfig is invalid", func() {
+					expectPreflight(validation.ValidatorResult{IsValid: false, Message: "invalid version"}, []upgradev1alpha1.PreflightCheck{
+						{Name: string(upgradev1alpha1.IsClusterUpgradable), Result: upgradev1alpha1.PreflightCheckPassed},
+					})
+					_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: upgradeConfigName})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(updated.Status.Preflight.Ready).To(BeFalse())
+					Expect(updated.Status.Preflight.Checks[0]).To(Equal(upgradev1alpha1.PreflightCheck{
+						Name:    upgradev1alpha1.PreflightCheckUpgradeConfigValid,
+						Result:  upgradev1alpha1.PreflightCheckFailed,
+						Message: "invalid version",
+					}))
+				})
+
+				It("reports the upgrade as not ready if a pre-upgrade check fails", func() {
+					expectPreflight(validation.ValidatorResult{IsValid: true, IsAvailableUpdate: true}, []upgradev1alpha1.PreflightCheck{
+						{Name: string(upgradev1alpha1.UpgradePreHealthCheck), Result: upgradev1alpha1.PreflightCheckFailed, Message: "CriticalAlertsHealthcheckFailed"},
+					})
+					_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: upgradeConfigName})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(updated.Status.Preflight.Ready).To(BeFalse())
+				})
+			})
+
 			Context("When the upgrade phase is Upgrading", func() {
 				BeforeEach(func() {
 					upgradeConfig.Status.History[0].Phase = upgradev1alpha1.UpgradePhaseUpgrading
@@ -976,6 +1048,7 @@ var _ = Describe("UpgradeConfigController", func() {
 						mockClusterUpgraderBuilder.EXPECT().NewClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), upgradeConfig.Spec.Type).Return(mockClusterUpgrader, nil),
 						mockMetricsClient.EXPECT().AlertsFromUpgrade(gomock.Any(), gomock.Any()),
 						mockMetricsClient.EXPECT().UpdateMetricUpgradeResult(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()),
+						mockMetricsClient.EXPECT().ResetAllMetricUpgradeStepTimeout(),
 					)
 					result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: upgradeConfigName})
 					Expect(err).NotTo(HaveOccurred())
@@ -997,6 +1070,7 @@ var _ = Describe("UpgradeConfigController", func() {
 							mockClusterUpgraderBuilder.EXPECT().NewClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), upgradeConfig.Spec.Type).Return(mockClusterUpgrader, nil),
 							mockMetricsClient.EXPECT().AlertsFromUpgrade(gomock.Any(), gomock.Any()),
 							mockMetricsClient.EXPECT().UpdateMetricUpgradeResult(gomock.Any(), "4.14.0", "4.15.0", "y", gomock.Any()),
+							mockMetricsClient.EXPECT().ResetAllMetricUpgradeStepTimeout(),
 						)
 						result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: upgradeConfigName})
 
@@ -1022,6 +1096,7 @@ var _ = Describe("UpgradeConfigController", func() {
 							mockClusterUpgraderBuilder.EXPECT().NewClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), upgradeConfig.Spec.Type).Return(mockClusterUpgrader, nil),
 							mockMetricsClient.EXPECT().AlertsFromUpgrade(gomock.Any(), gomock.Any()),
 							mockMetricsClient.EXPECT().UpdateMetricUpgradeResult(gomock.Any(), "4.15.1", "4.15.2", "z", gomock.Any()),
+							mockMetricsClient.EXPECT().ResetAllMetricUpgradeStepTimeout(),
 						)
 						result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: upgradeConfigName})
 
@@ -30,6 +30,7 @@ import (
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 
@@ -115,6 +116,14 @@ func printVersion() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == simulateDrainCommand {
+		if err := simulateDrain(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
@@ -161,6 +170,17 @@ func main() {
 			DefaultNamespaces: map[string]cache.Config{
 				operatorNS: {},
 			},
+			// The NodeKeeper reads the pods and PodDisruptionBudgets of every namespace from the cache
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Pod{}: {
+					Namespaces: map[string]cache.Config{cache.AllNamespaces: {}},
+					Transform:  cache.TransformStripManagedFields(),
+				},
+				&policyv1.PodDisruptionBudget{}: {
+					Namespaces: map[string]cache.Config{cache.AllNamespaces: {}},
+					Transform:  cache.TransformStripManagedFields(),
+				},
+			},
 			SyncPeriod: &syncPeriod,
 		},
 		Scheme: scheme,
@@ -181,17 +201,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Recorder used for Events on the resources acted on during upgrades and node drains
+	recorder := mgr.GetEventRecorderFor("managed-upgrade-operator")
+
 	// Add UpgradeConfig controller to the manager
 	if err = (&upgradeconfig.ReconcileUpgradeConfig{
 		Client:                 mgr.GetClient(),
 		Scheme:                 mgr.GetScheme(),
 		MetricsClientBuilder:   metrics.NewBuilder(),
-		ClusterUpgraderBuilder: cub.NewBuilder(),
+		ClusterUpgraderBuilder: cub.NewBuilder(recorder),
 		ValidationBuilder:      validation.NewBuilder(),
 		ConfigManagerBuilder:   configmanager.NewBuilder(),
 		Scheduler:              scheduler.NewScheduler(),
 		CvClientBuilder:        cv.NewBuilder(),
-		EventManagerBuilder:    eventmanager.NewBuilder(),
+		EventManagerBuilder:    eventmanager.NewBuilder(recorder),
 		UcMgrBuilder:           upgradeconfigmanager.NewBuilder(),
 		DvoClientBuilder:       dvo.NewBuilder(),
 	}).SetupWithManager(mgr); err != nil {
@@ -206,7 +229,7 @@ func main() {
 		ConfigManagerBuilder:        configmanager.NewBuilder(),
 		Machinery:                   machinery.NewMachinery(),
 		MetricsClientBuilder:        metrics.NewBuilder(),
-		DrainstrategyBuilder:        drain.NewBuilder(),
+		DrainstrategyBuilder:        drain.NewBuilder(recorder),
 		UpgradeConfigManagerBuilder: upgradeconfigmanager.NewBuilder(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeKeeper")
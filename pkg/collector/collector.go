@@ -38,6 +38,12 @@ const (
 	helpPostClusterHealthCheck                 = "Unix Timestamp indicating time of post cluster health check"
 	helpSendCompletedNotificationTimestamp     = "Unix Timestamp indicating time of complete upgrade notification event"
 
+	// .status.history[].machineConfigPools[]
+	helpPoolStartTime           = "Unix Timestamp indicating when the nodes of a worker MachineConfigPool started upgrading"
+	helpPoolCompleteTime        = "Unix Timestamp indicating when all nodes of a worker MachineConfigPool have upgraded"
+	helpPoolMachineCount        = "Int indicating the number of nodes in a worker MachineConfigPool"
+	helpPoolUpdatedMachineCount = "Int indicating the number of nodes in a worker MachineConfigPool that have upgraded"
+
 	// Error handling for failed scrapes
 	helpCollectorFailed = "An error occurred during scape of metrics"
 )
@@ -69,6 +75,12 @@ type ManagedOSMetrics struct {
 	removeMaintWindow         *prometheus.Desc
 	postClusterHealthCheck    *prometheus.Desc
 	sendCompletedNotification *prometheus.Desc
+
+	// .status.history[].machineConfigPools[]
+	poolStartTime           *prometheus.Desc
+	poolCompleteTime        *prometheus.Desc
+	poolMachineCount        *prometheus.Desc
+	poolUpdatedMachineCount *prometheus.Desc
 }
 
 // UpgradeCollector is implementing prometheus.Collector interface.
@@ -255,6 +267,38 @@ func bootstrapMetrics() *ManagedOSMetrics {
 				keyDesiredVersion,
 				keyCondition,
 			}, nil),
+		poolStartTime: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, subSystemPool, "start_timestamp"),
+			helpPoolStartTime,
+			[]string{
+				keyVersion,
+				keyDesiredVersion,
+				keyPool,
+			}, nil),
+		poolCompleteTime: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, subSystemPool, "complete_timestamp"),
+			helpPoolCompleteTime,
+			[]string{
+				keyVersion,
+				keyDesiredVersion,
+				keyPool,
+			}, nil),
+		poolMachineCount: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, subSystemPool, "machines"),
+			helpPoolMachineCount,
+			[]string{
+				keyVersion,
+				keyDesiredVersion,
+				keyPool,
+			}, nil),
+		poolUpdatedMachineCount: prometheus.NewDesc(
+			prometheus.BuildFQName(MetricsNamespace, subSystemPool, "updated_machines"),
+			helpPoolUpdatedMachineCount,
+			[]string{
+				keyVersion,
+				keyDesiredVersion,
+				keyPool,
+			}, nil),
 	}
 }
 
@@ -283,6 +327,12 @@ func (uc *UpgradeCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- uc.managedMetrics.removeMaintWindow
 	ch <- uc.managedMetrics.postClusterHealthCheck
 	ch <- uc.managedMetrics.sendCompletedNotification
+
+	// .status.history[].machineConfigPools[]
+	ch <- uc.managedMetrics.poolStartTime
+	ch <- uc.managedMetrics.poolCompleteTime
+	ch <- uc.managedMetrics.poolMachineCount
+	ch <- uc.managedMetrics.poolUpdatedMachineCount
 }
 
 // Collect is method required to implement the prometheus.Collector(prometheus/client_golang/prometheus/collector.go) interface.
@@ -339,6 +389,11 @@ func (uc *UpgradeCollector) collectUpgradeConditions(ch chan<- prometheus.Metric
 		return err
 	}
 
+	for _, p := range h.MachineConfigPools {
+		p := p
+		uc.collectMachineConfigPool(&p, upgradeConfig, cvVersion, ch)
+	}
+
 	// Collect metrics based on observing available conditions in the target
 	// versions upgrade history.
 	for _, c := range h.Conditions {
@@ -469,3 +524,45 @@ func (uc *UpgradeCollector) collectStatus(ucfg *upgradev1alpha1.UpgradeConfig, c
 	}
 	return nil
 }
+
+func (uc *UpgradeCollector) collectMachineConfigPool(p *upgradev1alpha1.MachineConfigPoolHistory, ucfg *upgradev1alpha1.UpgradeConfig, cvV string, ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(
+		uc.managedMetrics.poolMachineCount,
+		prometheus.GaugeValue,
+		float64(p.MachineCount),
+		cvV,
+		ucfg.Spec.Desired.Version,
+		p.Name,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		uc.managedMetrics.poolUpdatedMachineCount,
+		prometheus.GaugeValue,
+		float64(p.UpdatedMachineCount),
+		cvV,
+		ucfg.Spec.Desired.Version,
+		p.Name,
+	)
+
+	if p.StartTime != nil {
+		ch <- prometheus.MustNewConstMetric(
+			uc.managedMetrics.poolStartTime,
+			prometheus.GaugeValue,
+			float64(p.StartTime.Unix()),
+			cvV,
+			ucfg.Spec.Desired.Version,
+			p.Name,
+		)
+	}
+
+	if p.CompleteTime != nil {
+		ch <- prometheus.MustNewConstMetric(
+			uc.managedMetrics.poolCompleteTime,
+			prometheus.GaugeValue,
+			float64(p.CompleteTime.Unix()),
+			cvV,
+			ucfg.Spec.Desired.Version,
+			p.Name,
+		)
+	}
+}
@@ -133,6 +133,23 @@ var _ = Describe("Upgrade Conditions Collector", func() {
 					Expect(err).To(BeNil())
 					Expect(source_version).To(Equal(TEST_UPGRADE_VERSION))
 				})
+				It("collects metrics for each worker MachineConfigPool", func() {
+					upgradeConfig.Status.History[0].MachineConfigPools = []upgradev1alpha1.MachineConfigPoolHistory{
+						{Name: "infra", MachineCount: 3, UpdatedMachineCount: 3, StartTime: &metav1.Time{Time: testTime}, CompleteTime: &metav1.Time{Time: testTime}},
+						{Name: "worker", MachineCount: 6, UpdatedMachineCount: 2, StartTime: &metav1.Time{Time: testTime}},
+					}
+					gomock.InOrder(
+						mockUpgradeConfigManager.EXPECT().Get().Return(&upgradeConfig, nil),
+						mockCVClient.EXPECT().GetClusterVersion().Return(&cv, nil),
+					)
+					metricCount := promtestutil.CollectAndCount(upgradeCollector,
+						"managed_upgrade_machineconfigpool_machines",
+						"managed_upgrade_machineconfigpool_updated_machines",
+						"managed_upgrade_machineconfigpool_start_timestamp",
+						"managed_upgrade_machineconfigpool_complete_timestamp",
+					)
+					Expect(metricCount).To(Equal(7))
+				})
 			})
 		})
 	})
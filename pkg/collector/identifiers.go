@@ -6,6 +6,7 @@ const (
 	subSystemUpgrade   = "upgrade"
 	subSystemCollector = "collector"
 	subSystemCondition = "condition"
+	subSystemPool      = "machineconfigpool"
 )
 
 // keys for labels
@@ -15,4 +16,5 @@ const (
 	keyVersion           = "version"
 	keyDesiredVersion    = "desired_version"
 	keyCondition         = "condition"
+	keyPool              = "pool"
 )
@@ -0,0 +1,116 @@
+package drain
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+)
+
+// WorkerCapacity describes how many worker nodes may be unavailable at once without the pods scheduled
+// to the worker nodes requesting more resources than the available worker nodes can allocate
+type WorkerCapacity struct {
+	// Unavailable is the number of worker nodes which are unschedulable or not ready
+	Unavailable int
+	// PoolUnavailable is the number of unavailable worker nodes of each worker MachineConfigPool
+	PoolUnavailable map[string]int
+	// Spare is the number of further worker nodes which may become unavailable
+	Spare int
+}
+
+// AllowedPoolUnavailable returns the number of nodes of the worker MachineConfigPool which may be
+// unavailable at once, the spare capacity being shared evenly by the given number of pools upgrading
+func (wc *WorkerCapacity) AllowedPoolUnavailable(pool string, pools int) int {
+	return wc.PoolUnavailable[pool] + wc.Spare/max(pools, 1)
+}
+
+// GetWorkerCapacity returns the capacity of the worker nodes to absorb the pods of nodes being drained.
+// The resources allocatable on the available worker nodes, including the nodes added to reserve capacity
+// for the upgrade, are compared with the resources requested by the pods of every worker node. Each node
+// becoming unavailable is assumed to take as many resources away as the largest available node.
+func GetWorkerCapacity(c client.Client) (*WorkerCapacity, error) {
+	poolNodes, err := machinery.WorkerPoolNodes(c)
+	if err != nil {
+		return nil, err
+	}
+	pods := &corev1.PodList{}
+	err = c.List(context.TODO(), pods)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := &WorkerCapacity{PoolUnavailable: map[string]int{}}
+	workers := map[string]bool{}
+	var allocatableCPU, allocatableMemory, largestCPU, largestMemory int64
+	for pool, nodes := range poolNodes {
+		for _, node := range nodes {
+			workers[node.Name] = true
+			if node.Spec.Unschedulable || !isNodeReady(node) {
+				capacity.Unavailable++
+				capacity.PoolUnavailable[pool]++
+				continue
+			}
+			cpu := node.Status.Allocatable.Cpu().MilliValue()
+			memory := node.Status.Allocatable.Memory().Value()
+			allocatableCPU += cpu
+			allocatableMemory += memory
+			largestCPU = max(largestCPU, cpu)
+			largestMemory = max(largestMemory, memory)
+		}
+	}
+
+	var requestedCPU, requestedMemory int64
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		if !workers[p.Spec.NodeName] || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests := podRequests(p)
+		requestedCPU += requests.Cpu().MilliValue()
+		requestedMemory += requests.Memory().Value()
+	}
+
+	if largestCPU == 0 || largestMemory == 0 {
+		return capacity, nil
+	}
+	spare := min((allocatableCPU-requestedCPU)/largestCPU, (allocatableMemory-requestedMemory)/largestMemory)
+	capacity.Spare = int(max(spare, 0))
+	return capacity, nil
+}
+
+// podRequests returns the resources requested by the pod, which are the greater of the resources
+// requested by its containers and by any one of its init containers
+func podRequests(p *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range p.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	for _, container := range p.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; !ok || quantity.Cmp(total) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	for name, quantity := range p.Spec.Overhead {
+		total := requests[name]
+		total.Add(quantity)
+		requests[name] = total
+	}
+	return requests
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
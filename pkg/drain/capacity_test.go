@@ -0,0 +1,194 @@
+package drain
+
+import (
+	"fmt"
+
+	machineconfigv1 "github.com/openshift/api/machineconfiguration/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Worker Capacity", func() {
+
+	var (
+		mockCtrl       *gomock.Controller
+		mockKubeClient *mocks.MockClient
+		nodeList       *corev1.NodeList
+		podList        *corev1.PodList
+		poolList       *machineconfigv1.MachineConfigPoolList
+	)
+
+	newNode := func(name string, cpu string, memory string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"node-role.kubernetes.io/worker": ""}},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	newPod := func(node string, cpu string, memory string) corev1.Pod {
+		return corev1.Pod{
+			Spec: corev1.PodSpec{
+				NodeName: node,
+				Containers: []corev1.Container{{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		poolList = &machineconfigv1.MachineConfigPoolList{
+			Items: []machineconfigv1.MachineConfigPool{
+				newPool("worker", "node-role.kubernetes.io/worker"),
+				newPool("infra", "node-role.kubernetes.io/infra"),
+			},
+		}
+		nodeList = &corev1.NodeList{
+			Items: []corev1.Node{
+				newNode("n1", "4", "16Gi"),
+				newNode("n2", "4", "16Gi"),
+				newNode("n3", "4", "16Gi"),
+				newNode("n4", "4", "16Gi"),
+				newNode("n5", "4", "16Gi"),
+			},
+		}
+		podList = &corev1.PodList{
+			Items: []corev1.Pod{
+				newPod("n1", "3", "8Gi"),
+				newPod("n2", "2", "8Gi"),
+				newPod("n3", "1", "8Gi"),
+				newPod("master", "8", "32Gi"),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Returns how many more worker nodes may become unavailable", func() {
+		gomock.InOrder(
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *podList),
+		)
+		capacity, err := GetWorkerCapacity(mockKubeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capacity.Unavailable).To(Equal(0))
+		// 14 of 20 CPUs and 56 of 80Gi of memory are spare, each node taking 4 CPUs and 16Gi away
+		Expect(capacity.Spare).To(Equal(3))
+		Expect(capacity.AllowedPoolUnavailable("worker", 1)).To(Equal(3))
+	})
+
+	It("Counts the unschedulable and not ready worker nodes as unavailable", func() {
+		nodeList.Items[0].Spec.Unschedulable = true
+		nodeList.Items[1].Status.Conditions[0].Status = corev1.ConditionFalse
+		gomock.InOrder(
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *podList),
+		)
+		capacity, err := GetWorkerCapacity(mockKubeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capacity.Unavailable).To(Equal(2))
+		// 6 of 12 CPUs are spare
+		Expect(capacity.Spare).To(Equal(1))
+		Expect(capacity.PoolUnavailable).To(Equal(map[string]int{"worker": 2}))
+		Expect(capacity.AllowedPoolUnavailable("worker", 1)).To(Equal(3))
+	})
+
+	It("Reports no spare capacity if the pods request more than is allocatable", func() {
+		podList.Items = append(podList.Items, newPod("n4", "16", "1Gi"))
+		gomock.InOrder(
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *podList),
+		)
+		capacity, err := GetWorkerCapacity(mockKubeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capacity.Spare).To(Equal(0))
+	})
+
+	It("Counts the unavailable nodes of each worker MachineConfigPool and shares the spare capacity between the pools", func() {
+		nodeList.Items[0].Labels["node-role.kubernetes.io/infra"] = ""
+		nodeList.Items[0].Spec.Unschedulable = true
+		nodeList.Items[1].Spec.Unschedulable = true
+		gomock.InOrder(
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *podList),
+		)
+		capacity, err := GetWorkerCapacity(mockKubeClient)
+		Expect(err).NotTo(HaveOccurred())
+		// The infra node is taken to belong to the infra pool only
+		Expect(capacity.PoolUnavailable).To(Equal(map[string]int{"infra": 1, "worker": 1}))
+		// 6 of 12 CPUs are spare
+		Expect(capacity.Spare).To(Equal(1))
+		Expect(capacity.AllowedPoolUnavailable("worker", 2)).To(Equal(1))
+		Expect(capacity.AllowedPoolUnavailable("infra", 2)).To(Equal(1))
+	})
+
+	It("Only counts the nodes of the worker MachineConfigPools", func() {
+		delete(nodeList.Items[4].Labels, "node-role.kubernetes.io/worker")
+		gomock.InOrder(
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *podList),
+		)
+		capacity, err := GetWorkerCapacity(mockKubeClient)
+		Expect(err).NotTo(HaveOccurred())
+		// 10 of 16 CPUs are spare
+		Expect(capacity.Spare).To(Equal(2))
+	})
+
+	It("Returns an error if the nodes cannot be listed", func() {
+		gomock.InOrder(
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+		)
+		_, err := GetWorkerCapacity(mockKubeClient)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Takes the greater of the container and init container requests of a pod", func() {
+		p := newPod("n1", "1", "1Gi")
+		p.Spec.InitContainers = []corev1.Container{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			},
+		}}
+		requests := podRequests(&p)
+		Expect(requests.Cpu().MilliValue()).To(Equal(int64(2000)))
+		Expect(requests.Memory().Value()).To(Equal(int64(1 << 30)))
+	})
+})
+
+// newPool returns a MachineConfigPool selecting the nodes with the label
+func newPool(name string, label string) machineconfigv1.MachineConfigPool {
+	return machineconfigv1.MachineConfigPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: machineconfigv1.MachineConfigPoolSpec{
+			NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{label: ""}},
+		},
+	}
+}
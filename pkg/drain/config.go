@@ -1,6 +1,7 @@
 package drain
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -10,6 +11,52 @@ type NodeDrain struct {
 	Timeout                  int      `yaml:"timeOut"`
 	ExpectedNodeDrainTime    int      `yaml:"expectedNodeDrainTime" default:"8"`
 	IgnoredNamespacePatterns []string `yaml:"ignoredNamespacePatterns"`
+	// Policies override the drain strategies applied to the pods they select
+	Policies []DrainPolicy `yaml:"policies"`
+	// Finalizers restricts the finalizers removed by the finalizer removal drain strategies
+	Finalizers FinalizerRemoval `yaml:"finalizers"`
+	// MaxConcurrentEscalations limits the number of nodes on which forceful drain strategies are executed at once, unlimited if 0
+	MaxConcurrentEscalations int `yaml:"maxConcurrentEscalations"`
+	// PreDrainHooks are the hooks which pods may name in their pre-drain hook annotation
+	PreDrainHooks []PreDrainHook `yaml:"preDrainHooks"`
+	// PreDrainHookTimeout is the time in minutes after the node is cordoned after which failing pre-drain hooks are skipped
+	PreDrainHookTimeout int `yaml:"preDrainHookTimeOut"`
+}
+
+// IsValid returns an error if the drain policies, finalizer patterns, escalation limit or pre-drain hooks are invalid
+func (nd *NodeDrain) IsValid() error {
+	if nd.MaxConcurrentEscalations < 0 {
+		return fmt.Errorf("config nodeDrain maxConcurrentEscalations must not be negative")
+	}
+	if err := nd.Finalizers.IsValid(); err != nil {
+		return fmt.Errorf("config nodeDrain finalizers is invalid: %v", err)
+	}
+	names := map[string]bool{}
+	for i := range nd.Policies {
+		dp := &nd.Policies[i]
+		if err := dp.IsValid(); err != nil {
+			return fmt.Errorf("config nodeDrain policies is invalid: %v", err)
+		}
+		if names[dp.Name] {
+			return fmt.Errorf("config nodeDrain policies is invalid: drain policy %s is defined more than once", dp.Name)
+		}
+		names[dp.Name] = true
+	}
+	if nd.PreDrainHookTimeout < 0 {
+		return fmt.Errorf("config nodeDrain preDrainHookTimeOut must not be negative")
+	}
+	hooks := map[string]bool{}
+	for i := range nd.PreDrainHooks {
+		h := &nd.PreDrainHooks[i]
+		if err := h.IsValid(); err != nil {
+			return fmt.Errorf("config nodeDrain preDrainHooks is invalid: %v", err)
+		}
+		if hooks[h.Name] {
+			return fmt.Errorf("config nodeDrain preDrainHooks is invalid: pre-drain hook %s is defined more than once", h.Name)
+		}
+		hooks[h.Name] = true
+	}
+	return nil
 }
 
 // GetTimeOutDuration returns the timout field from the NodeDrain object
@@ -21,3 +68,12 @@ func (nd *NodeDrain) GetTimeOutDuration() time.Duration {
 func (nd *NodeDrain) GetExpectedDrainDuration() time.Duration {
 	return time.Duration(nd.ExpectedNodeDrainTime) * time.Minute
 }
+
+// GetPreDrainHookDuration returns how long after the node is cordoned failing pre-drain hooks are
+// retried before being skipped, defaulting to 10 minutes
+func (nd *NodeDrain) GetPreDrainHookDuration() time.Duration {
+	if nd.PreDrainHookTimeout == 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(nd.PreDrainHookTimeout) * time.Minute
+}
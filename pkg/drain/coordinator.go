@@ -0,0 +1,90 @@
+package drain
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+)
+
+// ActiveDrain is a worker node which is being drained
+type ActiveDrain struct {
+	Node string
+	// Since is the time the node was cordoned
+	Since metav1.Time
+	// Failed is whether the node was recorded as having failed to drain
+	Failed bool
+}
+
+// ActiveDrains returns the worker nodes being drained, in the order they were cordoned
+func ActiveDrains(c client.Client, m machinery.Machinery) ([]ActiveDrain, error) {
+	nodes, err := machinery.ListWorkerNodes(c)
+	if err != nil {
+		return nil, err
+	}
+
+	drains := []ActiveDrain{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		result := m.IsNodeCordoned(node)
+		if !result.IsCordoned || result.AddedAt == nil {
+			continue
+		}
+		drain := ActiveDrain{Node: node.Name, Since: *result.AddedAt}
+		// An unreadable drain status is treated as the node not having failed to drain
+		if status, err := GetNodeDrainStatus(node); err == nil && status != nil {
+			// The drain status is stored with second precision
+			since := result.AddedAt.Rfc3339Copy()
+			drain.Failed = status.Failed && status.StartTime.Equal(&since)
+		}
+		drains = append(drains, drain)
+	}
+	sort.Slice(drains, func(i, j int) bool {
+		if drains[i].Since.Equal(&drains[j].Since) {
+			return drains[i].Node < drains[j].Node
+		}
+		return drains[i].Since.Before(&drains[j].Since)
+	})
+	return drains, nil
+}
+
+// drainCoordinator coordinates the escalation of the drains of the worker nodes being drained at once.
+// Forceful drain strategies are executed on a limited number of nodes at a time, the nodes being
+// escalated in the order they were cordoned so that no node is starved by nodes cordoned after it.
+type drainCoordinator struct {
+	client                   client.Client
+	machinery                machinery.Machinery
+	maxConcurrentEscalations int
+}
+
+// mayEscalate returns whether forceful drain strategies may be executed on the node. They may be
+// executed on the nodes cordoned first, up to the maximum number of concurrent escalations. Nodes which
+// have failed to drain need manual intervention and so do not take up any of the escalations.
+func (dc *drainCoordinator) mayEscalate(node *corev1.Node) (bool, error) {
+	if dc == nil || dc.maxConcurrentEscalations <= 0 {
+		return true, nil
+	}
+	drains, err := ActiveDrains(dc.client, dc.machinery)
+	if err != nil {
+		return false, err
+	}
+	escalations := 0
+	for _, d := range drains {
+		if d.Node == node.Name {
+			return d.Failed || escalations < dc.maxConcurrentEscalations, nil
+		}
+		if !d.Failed {
+			escalations++
+		}
+	}
+	// A node which is not a worker node being drained is not coordinated
+	return true, nil
+}
+
+// isForceful returns whether the named drain strategy forcefully removes pods rather than evicting them
+func isForceful(strategy string) bool {
+	return strategy != podEvictionName
+}
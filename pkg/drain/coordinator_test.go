@@ -0,0 +1,185 @@
+package drain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	machineconfigv1 "github.com/openshift/api/machineconfiguration/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	mockMachinery "github.com/openshift/managed-upgrade-operator/pkg/machinery/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drain Coordinator", func() {
+
+	var (
+		mockCtrl            *gomock.Controller
+		mockKubeClient      *mocks.MockClient
+		mockMachineryClient *mockMachinery.MockMachinery
+		nodeList            *corev1.NodeList
+		poolList            *machineconfigv1.MachineConfigPoolList
+		cordonedAt          map[string]*metav1.Time
+		coordinator         *drainCoordinator
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockMachineryClient = mockMachinery.NewMockMachinery(mockCtrl)
+		now := time.Now()
+		cordonedAt = map[string]*metav1.Time{
+			"n1": {Time: now.Add(-10 * time.Minute)},
+			"n2": {Time: now.Add(-30 * time.Minute)},
+			"n3": {Time: now.Add(-20 * time.Minute)},
+		}
+		workerLabels := map[string]string{"node-role.kubernetes.io/worker": ""}
+		poolList = &machineconfigv1.MachineConfigPoolList{
+			Items: []machineconfigv1.MachineConfigPool{newPool("worker", "node-role.kubernetes.io/worker")},
+		}
+		nodeList = &corev1.NodeList{
+			Items: []corev1.Node{
+				{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: workerLabels}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "n2", Labels: workerLabels}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "n3", Labels: workerLabels}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "n4", Labels: workerLabels}},
+			},
+		}
+		mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).DoAndReturn(func(node *corev1.Node) *machinery.IsCordonedResult {
+			addedAt, ok := cordonedAt[node.Name]
+			return &machinery.IsCordonedResult{IsCordoned: ok, AddedAt: addedAt}
+		}).AnyTimes()
+		coordinator = &drainCoordinator{
+			client:                   mockKubeClient,
+			machinery:                mockMachineryClient,
+			maxConcurrentEscalations: 1,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When listing the active drains", func() {
+		It("Returns the cordoned worker nodes in the order they were cordoned", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			)
+			drains, err := ActiveDrains(mockKubeClient, mockMachineryClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drains).To(HaveLen(3))
+			Expect(drains[0].Node).To(Equal("n2"))
+			Expect(drains[1].Node).To(Equal("n3"))
+			Expect(drains[2].Node).To(Equal("n1"))
+		})
+
+		It("Reports the nodes which failed their current drain", func() {
+			Expect(setFailedDrainStatus(&nodeList.Items[1], cordonedAt["n2"], true)).To(Succeed())
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			)
+			drains, err := ActiveDrains(mockKubeClient, mockMachineryClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drains[0].Failed).To(BeTrue())
+			Expect(drains[1].Failed).To(BeFalse())
+		})
+
+		It("Returns an error if the nodes cannot be listed", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+			_, err := ActiveDrains(mockKubeClient, mockMachineryClient)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When deciding whether a node may escalate its drain", func() {
+		It("Allows the nodes cordoned first up to the maximum number of concurrent escalations", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			)
+			allowed, err := coordinator.mayEscalate(&nodeList.Items[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+			allowed, err = coordinator.mayEscalate(&nodeList.Items[2])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+		})
+
+		It("Does not count the nodes which failed to drain against the maximum", func() {
+			Expect(setFailedDrainStatus(&nodeList.Items[1], cordonedAt["n2"], true)).To(Succeed())
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			)
+			allowed, err := coordinator.mayEscalate(&nodeList.Items[2])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("Allows every node if the number of concurrent escalations is unlimited", func() {
+			coordinator.maxConcurrentEscalations = 0
+			allowed, err := coordinator.mayEscalate(&nodeList.Items[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+	})
+
+	Context("When executing the drain strategies of a node waiting to escalate", func() {
+		It("Evicts the node's pods but defers the forceful strategies", func() {
+			mockTimedEvict := NewMockTimedDrainStrategy(mockCtrl)
+			mockEvict := NewMockDrainStrategy(mockCtrl)
+			mockTimedDelete := NewMockTimedDrainStrategy(mockCtrl)
+			mockMetricsClient := mockMetrics.NewMockMetrics(mockCtrl)
+			ds := &osdDrainStrategy{
+				client:               mockKubeClient,
+				machinery:            mockMachineryClient,
+				cfg:                  &NodeDrain{},
+				timedDrainStrategies: []TimedDrainStrategy{mockTimedEvict, mockTimedDelete},
+				metricsClient:        mockMetricsClient,
+				coordinator:          coordinator,
+			}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld(gomock.Any(), metrics.NodeDrainPodManual, gomock.Any()),
+				mockTimedEvict.EXPECT().GetName().Return(podEvictionName),
+				mockTimedEvict.EXPECT().GetWaitDuration().Return(time.Duration(0)).Times(2),
+				mockTimedEvict.EXPECT().GetStrategy().Return(mockEvict),
+				mockEvict.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(&DrainStrategyResult{HasExecuted: true}, nil),
+				mockTimedDelete.EXPECT().GetName().Return(defaultPodDeleteName),
+				mockTimedDelete.EXPECT().GetWaitDuration().Return(time.Minute).Times(2),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *poolList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodeList),
+			)
+			res, err := ds.Execute(&nodeList.Items[2], logf.Log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].Strategy).To(Equal(podEvictionName))
+		})
+	})
+})
+
+// setFailedDrainStatus annotates the node with the drain status of a drain commenced at the given time
+func setFailedDrainStatus(node *corev1.Node, startTime *metav1.Time, failed bool) error {
+	st := startTime.Rfc3339Copy()
+	value, err := json.Marshal(&upgradev1alpha1.NodeDrainStatus{StartTime: &st, Failed: failed})
+	if err != nil {
+		return err
+	}
+	node.Annotations = map[string]string{NodeDrainStatusAnnotation: string(value)}
+	return nil
+}
@@ -0,0 +1,71 @@
+package drain
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// PodEvictedReason is the reason of the Events recorded when a drain strategy evicts a pod
+	PodEvictedReason = "DrainPodEvicted"
+	// PodDeletedReason is the reason of the Events recorded when a drain strategy deletes a pod
+	PodDeletedReason = "DrainPodDeleted"
+	// FinalizersRemovedReason is the reason of the Events recorded when a drain strategy removes the finalizers of a pod
+	FinalizersRemovedReason = "DrainFinalizersRemoved"
+	// FinalizersSkippedReason is the reason of the Events recorded when a drain strategy may not remove any finalizer of a pod
+	FinalizersSkippedReason = "DrainFinalizersSkipped"
+)
+
+// strategyReasons explains why each drain strategy acts on the pods it selects
+var strategyReasons = map[string]string{
+	podEvictionName:                "the node is being drained for an upgrade",
+	defaultPodDeleteName:           "the pod was not drained within the node drain timeout",
+	defaultPodFinalizerRemovalName: "the pod's finalizers prevented it from being deleted within the node drain timeout",
+	stuckTerminatingPodName:        "the pod was stuck terminating beyond the node drain timeout",
+	pdbPodDeleteName:               "the pod is covered by a PodDisruptionBudget and was not drained within the PDB drain timeout",
+	pdbPodFinalizerRemovalName:     "the pod is covered by a PodDisruptionBudget and its finalizers prevented it from being deleted within the PDB drain timeout",
+}
+
+// recordDrainEvents records an Event on each of the named pods, on the controller owning it and
+// on the node, stating which drain strategy acted on the pod and why.
+// The pods are identified by their namespaced names among the pods of the list.
+func recordDrainEvents(recorder record.EventRecorder, node *corev1.Node, pl *corev1.PodList, pods []string, eventType string, reason string, strategy string, action string) {
+	if recorder == nil || len(pods) == 0 {
+		return
+	}
+	acted := make(map[string]bool, len(pods))
+	for _, p := range pods {
+		acted[p] = true
+	}
+	for i := range pl.Items {
+		p := &pl.Items[i]
+		if !acted[p.Namespace+"/"+p.Name] {
+			continue
+		}
+		message := fmt.Sprintf("Pod %s/%s %s by drain strategy %s on node %s: %s",
+			p.Namespace, p.Name, action, strategy, node.Name, strategyReasons[strategy])
+		recorder.Event(p, eventType, reason, message)
+		if owner := ownerReference(p); owner != nil {
+			recorder.Event(owner, eventType, reason, message)
+		}
+		recorder.Event(node, eventType, reason, message)
+	}
+}
+
+// ownerReference returns a reference to the controller owning the pod, if any
+func ownerReference(p *corev1.Pod) *corev1.ObjectReference {
+	owner := metav1.GetControllerOf(p)
+	if owner == nil {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Name:       owner.Name,
+		Namespace:  p.Namespace,
+		UID:        owner.UID,
+	}
+}
@@ -0,0 +1,92 @@
+package drain
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drain Events", func() {
+
+	var (
+		recorder *record.FakeRecorder
+		node     *corev1.Node
+		podList  *corev1.PodList
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "n1",
+			},
+		}
+		isController := true
+		podList = &corev1.PodList{
+			Items: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: "ns1",
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs1", UID: "uid1", Controller: &isController},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod2",
+						Namespace: "ns1",
+					},
+				},
+			},
+		}
+	})
+
+	Context("When recording the Events of the pods a drain strategy acted on", func() {
+		It("Records an Event on the pod, its controller and the node", func() {
+			recordDrainEvents(recorder, node, podList, []string{"ns1/pod1"}, corev1.EventTypeWarning, FinalizersRemovedReason, defaultPodFinalizerRemovalName, "had its finalizers removed")
+			Expect(recorder.Events).To(HaveLen(3))
+			for i := 0; i < 3; i++ {
+				Expect(<-recorder.Events).To(Equal("Warning DrainFinalizersRemoved Pod ns1/pod1 had its finalizers removed by drain strategy DEFAULT-FINALIZER on node n1: " +
+					strategyReasons[defaultPodFinalizerRemovalName]))
+			}
+		})
+
+		It("Records Events on the pod and the node only if the pod has no controller", func() {
+			recordDrainEvents(recorder, node, podList, []string{"ns1/pod2"}, corev1.EventTypeWarning, PodDeletedReason, stuckTerminatingPodName, "force deleted")
+			Expect(recorder.Events).To(HaveLen(2))
+		})
+
+		It("Does not record Events for the pods the strategy did not act on", func() {
+			recordDrainEvents(recorder, node, podList, []string{"ns2/pod1"}, corev1.EventTypeWarning, PodDeletedReason, defaultPodDeleteName, "deleted")
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("Does nothing without a recorder", func() {
+			Expect(func() {
+				recordDrainEvents(nil, node, podList, []string{"ns1/pod1"}, corev1.EventTypeWarning, PodDeletedReason, defaultPodDeleteName, "deleted")
+			}).NotTo(Panic())
+		})
+	})
+
+	Context("When referencing the owner of a pod", func() {
+		It("References the controller in the pod's namespace", func() {
+			ref := ownerReference(&podList.Items[0])
+			Expect(ref).To(Equal(&corev1.ObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "rs1",
+				Namespace:  "ns1",
+				UID:        "uid1",
+			}))
+		})
+
+		It("Returns nil if the pod has no controller", func() {
+			Expect(ownerReference(&podList.Items[1])).To(BeNil())
+		})
+	})
+})
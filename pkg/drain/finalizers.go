@@ -0,0 +1,106 @@
+package drain
+
+import (
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+)
+
+// defaultDeniedFinalizerPatterns are the finalizers which are never removed, whatever the config, as
+// removing them risks losing data or leaving resources behind
+var defaultDeniedFinalizerPatterns = []string{
+	`^kubernetes\.io/pvc-protection$`,
+	`csi`,
+	`^foregroundDeletion$`,
+}
+
+// builtInControllerGroups are the API groups of the built-in workload controllers, which leave the
+// finalizers of the pods they own alone
+var builtInControllerGroups = map[string]bool{
+	"":      true,
+	"apps":  true,
+	"batch": true,
+}
+
+// FinalizerRemoval restricts the finalizers removed from pods by the finalizer removal drain strategies
+type FinalizerRemoval struct {
+	// AllowedPatterns lists the regular expressions of the finalizers which may be removed, all finalizers may be removed if empty
+	AllowedPatterns []string `yaml:"allowedPatterns"`
+	// DeniedPatterns lists the regular expressions of the finalizers which are never removed
+	DeniedPatterns []string `yaml:"deniedPatterns"`
+}
+
+// IsValid returns an error if the finalizer patterns are invalid
+func (fr *FinalizerRemoval) IsValid() error {
+	for _, p := range append(append([]string{}, fr.AllowedPatterns...), fr.DeniedPatterns...) {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("finalizer pattern %q is invalid: %v", p, err)
+		}
+	}
+	return nil
+}
+
+// removable returns a predicate matching the finalizers which may be removed from pods
+func (fr *FinalizerRemoval) removable() (pod.FinalizerPredicate, error) {
+	if err := fr.IsValid(); err != nil {
+		return nil, err
+	}
+	allowed := compilePatterns(fr.AllowedPatterns)
+	denied := compilePatterns(append(append([]string{}, defaultDeniedFinalizerPatterns...), fr.DeniedPatterns...))
+	return func(f string) bool {
+		if matchesAny(denied, f) {
+			return false
+		}
+		return len(allowed) == 0 || matchesAny(allowed, f)
+	}, nil
+}
+
+// hasRemovableFinalizers returns a predicate matching the pods with at least one finalizer which may be removed
+func hasRemovableFinalizers(removable pod.FinalizerPredicate) pod.PodPredicate {
+	return func(p corev1.Pod) bool {
+		for _, f := range p.GetFinalizers() {
+			if removable == nil || removable(f) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hasFinalizerManagingOwner returns true if the pod is controlled by other than a built-in workload
+// controller. Such controllers, typically operators, may manage the finalizers of their pods, so the
+// finalizers of these pods are never removed.
+func hasFinalizerManagingOwner(p corev1.Pod) bool {
+	owner := metav1.GetControllerOf(&p)
+	if owner == nil {
+		return false
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	return err != nil || !builtInControllerGroups[gv.Group]
+}
+
+func hasNoFinalizerManagingOwner(p corev1.Pod) bool {
+	return !hasFinalizerManagingOwner(p)
+}
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		compiled = append(compiled, regexp.MustCompile(p))
+	}
+	return compiled
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, rxp := range patterns {
+		if rxp.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package drain
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Finalizer Removal", func() {
+
+	Context("Validating the finalizer patterns", func() {
+		It("Accepts valid patterns", func() {
+			fr := &FinalizerRemoval{AllowedPatterns: []string{"example.com/.+"}, DeniedPatterns: []string{".*csi.*"}}
+			Expect(fr.IsValid()).To(Succeed())
+		})
+
+		It("Rejects an invalid allowed pattern", func() {
+			fr := &FinalizerRemoval{AllowedPatterns: []string{"["}}
+			Expect(fr.IsValid()).NotTo(Succeed())
+		})
+
+		It("Rejects an invalid denied pattern", func() {
+			fr := &FinalizerRemoval{DeniedPatterns: []string{"["}}
+			Expect(fr.IsValid()).NotTo(Succeed())
+			_, err := fr.removable()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Matching the finalizers which may be removed", func() {
+		It("Allows every finalizer without patterns", func() {
+			removable, err := (&FinalizerRemoval{}).removable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removable("example.com/finalizer")).To(BeTrue())
+		})
+
+		It("Only allows the finalizers matching an allowed pattern", func() {
+			removable, err := (&FinalizerRemoval{AllowedPatterns: []string{"^example\\.com/"}}).removable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removable("example.com/finalizer")).To(BeTrue())
+			Expect(removable("other.io/finalizer")).To(BeFalse())
+		})
+
+		It("Never allows the finalizers denied by default", func() {
+			removable, err := (&FinalizerRemoval{}).removable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removable("kubernetes.io/pvc-protection")).To(BeFalse())
+			Expect(removable("example.csi.driver/attached")).To(BeFalse())
+			Expect(removable("foregroundDeletion")).To(BeFalse())
+		})
+
+		It("Never allows the finalizers matching a denied pattern", func() {
+			removable, err := (&FinalizerRemoval{
+				AllowedPatterns: []string{"^example\\.com/"},
+				DeniedPatterns:  []string{"csi"},
+			}).removable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removable("example.com/finalizer")).To(BeTrue())
+			Expect(removable("example.com/csi-finalizer")).To(BeFalse())
+		})
+	})
+
+	Context("Matching the pods with finalizers which may be removed", func() {
+		var p corev1.Pod
+
+		BeforeEach(func() {
+			p = corev1.Pod{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"keep", "remove"}}}
+		})
+
+		It("Matches a pod with a removable finalizer", func() {
+			Expect(hasRemovableFinalizers(func(f string) bool { return f == "remove" })(p)).To(BeTrue())
+		})
+
+		It("Does not match a pod without removable finalizers", func() {
+			Expect(hasRemovableFinalizers(func(f string) bool { return false })(p)).To(BeFalse())
+		})
+
+		It("Identifies pods whose controller may manage their finalizers", func() {
+			controller := true
+			p.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", Controller: &controller}}
+			Expect(hasFinalizerManagingOwner(p)).To(BeFalse())
+			p.OwnerReferences = []metav1.OwnerReference{{APIVersion: "example.com/v1", Kind: "Database", Name: "db", Controller: &controller}}
+			Expect(hasFinalizerManagingOwner(p)).To(BeTrue())
+			Expect(hasNoFinalizerManagingOwner(corev1.Pod{})).To(BeTrue())
+		})
+
+		It("Matches any pod with finalizers without a predicate", func() {
+			Expect(hasRemovableFinalizers(nil)(p)).To(BeTrue())
+			Expect(hasRemovableFinalizers(nil)(corev1.Pod{})).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,223 @@
+package drain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/managed-upgrade-operator/config"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+)
+
+const (
+	// PreDrainHookAnnotation names the pre-drain hook, configured in nodeDrain.preDrainHooks, to call
+	// before any drain strategy is applied to the pod
+	PreDrainHookAnnotation = "upgrade.managed.openshift.io/pre-drain-hook"
+	// PreDrainHookCompletedAnnotation records when the pre-drain hook of the pod succeeded
+	PreDrainHookCompletedAnnotation = "upgrade.managed.openshift.io/pre-drain-hook-completed"
+	// PreDrainHookSkippedAnnotation records when the pre-drain hook of the pod was given up on, after
+	// which the drain strategies are applied to the pod as if it had succeeded
+	PreDrainHookSkippedAnnotation = "upgrade.managed.openshift.io/pre-drain-hook-skipped"
+	// DrainPolicyAnnotation overrides how the pod is drained
+	DrainPolicyAnnotation = "upgrade.managed.openshift.io/drain-policy"
+	// DrainPolicyManual excludes the pod from any drain strategy, it is only reported
+	DrainPolicyManual = "manual"
+
+	preDrainHookTimeout = 10 * time.Second
+)
+
+// preDrainHookClient is the HTTP client shared by every pre-drain hook call
+var preDrainHookClient = &http.Client{Timeout: preDrainHookTimeout}
+
+// PreDrainHook is a hook which pods may name in their pre-drain hook annotation
+type PreDrainHook struct {
+	// Name is the name pods use to refer to the hook
+	Name string `yaml:"name"`
+	// URL is the http or https URL which is sent a POST request for each pod handed over by the hook
+	URL string `yaml:"url"`
+	// NamespacePatterns restricts the hook to the pods in namespaces matching any of the regular expressions
+	NamespacePatterns []string `yaml:"namespacePatterns"`
+}
+
+// IsValid returns an error if the pre-drain hook is invalid
+func (h *PreDrainHook) IsValid() error {
+	if h.Name == "" {
+		return fmt.Errorf("pre-drain hook name is required")
+	}
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("pre-drain hook %s url must be an http or https URL", h.Name)
+	}
+	if len(h.NamespacePatterns) == 0 {
+		return fmt.Errorf("pre-drain hook %s must set namespacePatterns", h.Name)
+	}
+	for _, p := range h.NamespacePatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("pre-drain hook %s namespace pattern %q is invalid: %v", h.Name, p, err)
+		}
+	}
+	return nil
+}
+
+// allows returns true if the pod may use the hook
+func (h *PreDrainHook) allows(p corev1.Pod) bool {
+	return matchesAny(compilePatterns(h.NamespacePatterns), p.Namespace)
+}
+
+// preDrainHookFor returns the configured pre-drain hook named by the pod's annotation, or an
+// error if no hook of that name is configured for the pod's namespace
+func (nd *NodeDrain) preDrainHookFor(p corev1.Pod) (*PreDrainHook, error) {
+	name := strings.TrimSpace(p.Annotations[PreDrainHookAnnotation])
+	for i := range nd.PreDrainHooks {
+		h := &nd.PreDrainHooks[i]
+		if h.Name == name && h.allows(p) {
+			return h, nil
+		}
+	}
+	return nil, fmt.Errorf("no pre-drain hook %q is configured for namespace %s", name, p.Namespace)
+}
+
+// preDrainHookRunner runs the pre-drain hook of a pod
+type preDrainHookRunner interface {
+	Run(ctx context.Context, hook *PreDrainHook, p corev1.Pod, node *corev1.Node) error
+}
+
+// preDrainHookPayload is the body sent to pre-drain hooks
+type preDrainHookPayload struct {
+	Node      string `json:"node"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+}
+
+// hookRunner posts the pod and its node to the URL of the hook
+type hookRunner struct{}
+
+func (hookRunner) Run(ctx context.Context, hook *PreDrainHook, p corev1.Pod, node *corev1.Node) error {
+	payload, err := json.Marshal(preDrainHookPayload{Node: node.Name, Namespace: p.Namespace, Pod: p.Name})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build pre-drain hook request: %v", err)
+	}
+	req.Header.Set("User-Agent", config.SetUserAgent())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := preDrainHookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pre-drain hook request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("pre-drain hook returned unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func hasPendingPreDrainHook(p corev1.Pod) bool {
+	_, hasHook := p.Annotations[PreDrainHookAnnotation]
+	_, completed := p.Annotations[PreDrainHookCompletedAnnotation]
+	_, skipped := p.Annotations[PreDrainHookSkippedAnnotation]
+	return hasHook && !completed && !skipped
+}
+
+func hasNoPendingPreDrainHook(p corev1.Pod) bool {
+	return !hasPendingPreDrainHook(p)
+}
+
+func isManualDrainPod(p corev1.Pod) bool {
+	return p.Annotations[DrainPolicyAnnotation] == DrainPolicyManual
+}
+
+func isNotManualDrainPod(p corev1.Pod) bool {
+	return !isManualDrainPod(p)
+}
+
+// handOverPods reports the pods on the node which must be drained manually and runs the pending
+// pre-drain hooks of the other pods, recording each success on the pod so that the drain strategies
+// may then act on it. Hooks which have not succeeded once nodeDrain.preDrainHookTimeOut has elapsed
+// since the node was cordoned are skipped, and the drain strategies act on their pods regardless.
+func (ds *osdDrainStrategy) handOverPods(node *corev1.Node, cordonedAt *metav1.Time, logger logr.Logger) ([]*DrainStrategyResult, error) {
+	pods, err := pod.GetPodList(ds.client, node, []pod.PodPredicate{isOnNode(node), isNotTerminating, isNotDaemonSet, isAllowedNamespace(ds.cfg.IgnoredNamespacePatterns)})
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*DrainStrategyResult{}
+	manual := []types.NamespacedName{}
+	for _, p := range pod.FilterPods(pods, isManualDrainPod).Items {
+		manual = append(manual, types.NamespacedName{Namespace: p.Namespace, Name: p.Name})
+		res = append(res, &DrainStrategyResult{Message: fmt.Sprintf("Pod %v/%v on node %v has a manual drain policy and must be drained manually", p.Namespace, p.Name, node.Name)})
+	}
+	ds.metricsClient.UpdateMetricNodeDrainPodsHeld(node.Name, metrics.NodeDrainPodManual, manual)
+
+	if ds.hookRunner == nil {
+		return res, nil
+	}
+	pending := pod.FilterPods(pods, isNotManualDrainPod, hasPendingPreDrainHook).Items
+	if len(pending) == 0 {
+		return res, nil
+	}
+
+	// Hooks which are past their deadline are skipped, the others are run concurrently
+	expired := isAfter(cordonedAt, ds.cfg.GetPreDrainHookDuration())
+	errs := make([]error, len(pending))
+	if !expired {
+		var wg sync.WaitGroup
+		for i := range pending {
+			p := pending[i]
+			hook, err := ds.cfg.preDrainHookFor(p)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			logger.Info(fmt.Sprintf("Running pre-drain hook %s for pod %v/%v", hook.Name, p.Namespace, p.Name))
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = ds.hookRunner.Run(context.TODO(), hook, p, node)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	for i := range pending {
+		p := pending[i]
+		annotation := PreDrainHookCompletedAnnotation
+		if expired {
+			annotation = PreDrainHookSkippedAnnotation
+			res = append(res, &DrainStrategyResult{Message: fmt.Sprintf("Pre-drain hook for pod %v/%v has not succeeded within %v, drain strategies will be applied to it", p.Namespace, p.Name, ds.cfg.GetPreDrainHookDuration())})
+		} else if errs[i] != nil {
+			logger.Error(errs[i], fmt.Sprintf("pre-drain hook for pod %v/%v failed", p.Namespace, p.Name))
+			res = append(res, &DrainStrategyResult{Message: fmt.Sprintf("Pre-drain hook for pod %v/%v failed, it will be retried until %v after the node was cordoned: %v", p.Namespace, p.Name, ds.cfg.GetPreDrainHookDuration(), errs[i])})
+			continue
+		} else {
+			res = append(res, &DrainStrategyResult{Message: fmt.Sprintf("Pre-drain hook for pod %v/%v succeeded", p.Namespace, p.Name)})
+		}
+
+		patch := client.MergeFrom(p.DeepCopy())
+		if p.Annotations == nil {
+			p.Annotations = map[string]string{}
+		}
+		p.Annotations[annotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := ds.client.Patch(context.TODO(), &p, patch); err != nil {
+			return nil, fmt.Errorf("failed to record the pre-drain hook outcome of pod %v/%v: %v", p.Namespace, p.Name, err)
+		}
+	}
+	return res, nil
+}
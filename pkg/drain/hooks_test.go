@@ -0,0 +1,292 @@
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeHookRunner records the pods whose pre-drain hooks are run
+type fakeHookRunner struct {
+	mutex sync.Mutex
+	err   error
+	ran   []string
+}
+
+func (f *fakeHookRunner) Run(_ context.Context, _ *PreDrainHook, p corev1.Pod, _ *corev1.Node) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.ran = append(f.ran, p.Name)
+	return f.err
+}
+
+var _ = Describe("Pre-drain hooks", func() {
+
+	var (
+		logger            logr.Logger
+		mockCtrl          *gomock.Controller
+		mockKubeClient    *mocks.MockClient
+		mockMetricsClient *mockMetrics.MockMetrics
+		runner            *fakeHookRunner
+		ds                *osdDrainStrategy
+		node              *corev1.Node
+		podList           corev1.PodList
+		cordonedAt        *metav1.Time
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockMetricsClient = mockMetrics.NewMockMetrics(mockCtrl)
+		logger = logf.Log.WithName("pre-drain hook test logger")
+		runner = &fakeHookRunner{}
+		ds = &osdDrainStrategy{
+			client: mockKubeClient,
+			cfg: &NodeDrain{PreDrainHooks: []PreDrainHook{
+				{Name: "handoff", URL: "https://handoff.test-namespace.svc/handoff", NamespacePatterns: []string{"^test-namespace$"}},
+			}},
+			metricsClient: mockMetricsClient,
+			hookRunner:    runner,
+		}
+		node = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}}
+		cordonedAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		podList = corev1.PodList{
+			Items: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "hooked",
+						Namespace:   "test-namespace",
+						Annotations: map[string]string{PreDrainHookAnnotation: "handoff"},
+					},
+					Spec: corev1.PodSpec{NodeName: "n1"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "handed-over",
+						Namespace: "test-namespace",
+						Annotations: map[string]string{
+							PreDrainHookAnnotation:          "handoff",
+							PreDrainHookCompletedAnnotation: "2024-06-20T00:00:00Z",
+						},
+					},
+					Spec: corev1.PodSpec{NodeName: "n1"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "manual",
+						Namespace:   "test-namespace",
+						Annotations: map[string]string{DrainPolicyAnnotation: DrainPolicyManual, PreDrainHookAnnotation: "handoff"},
+					},
+					Spec: corev1.PodSpec{NodeName: "n1"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "plain",
+						Namespace: "test-namespace",
+					},
+					Spec: corev1.PodSpec{NodeName: "n1"},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	expectManualPodsReported := func() *gomock.Call {
+		return mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld("n1", metrics.NodeDrainPodManual, []types.NamespacedName{{Namespace: "test-namespace", Name: "manual"}})
+	}
+
+	Context("When filtering pods by their annotations", func() {
+		It("only considers pods with an uncompleted hook as pending", func() {
+			Expect(hasPendingPreDrainHook(podList.Items[0])).To(BeTrue())
+			Expect(hasPendingPreDrainHook(podList.Items[1])).To(BeFalse())
+			Expect(hasNoPendingPreDrainHook(podList.Items[3])).To(BeTrue())
+		})
+		It("does not consider pods with a skipped hook as pending", func() {
+			p := podList.Items[0]
+			p.Annotations[PreDrainHookSkippedAnnotation] = "2024-06-20T00:00:00Z"
+			Expect(hasPendingPreDrainHook(p)).To(BeFalse())
+		})
+		It("identifies pods with a manual drain policy", func() {
+			Expect(isManualDrainPod(podList.Items[2])).To(BeTrue())
+			Expect(isNotManualDrainPod(podList.Items[3])).To(BeTrue())
+		})
+	})
+
+	Context("When resolving the pre-drain hook of a pod", func() {
+		It("resolves a hook configured for the pod's namespace", func() {
+			hook, err := ds.cfg.preDrainHookFor(podList.Items[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hook.URL).To(Equal("https://handoff.test-namespace.svc/handoff"))
+		})
+		It("does not resolve a hook for a pod in another namespace", func() {
+			p := podList.Items[0]
+			p.Namespace = "other-namespace"
+			_, err := ds.cfg.preDrainHookFor(p)
+			Expect(err).To(HaveOccurred())
+		})
+		It("does not resolve a URL in the annotation", func() {
+			p := podList.Items[0]
+			p.Annotations[PreDrainHookAnnotation] = "http://169.254.169.254/latest/meta-data"
+			_, err := ds.cfg.preDrainHookFor(p)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When validating pre-drain hooks", func() {
+		It("accepts a valid hook", func() {
+			Expect(ds.cfg.IsValid()).To(Succeed())
+		})
+		It("requires an http or https URL", func() {
+			h := PreDrainHook{Name: "handoff", URL: "file:///etc/passwd", NamespacePatterns: []string{".*"}}
+			Expect(h.IsValid()).NotTo(Succeed())
+		})
+		It("requires the namespaces allowed to use the hook", func() {
+			h := PreDrainHook{Name: "handoff", URL: "https://example.com"}
+			Expect(h.IsValid()).NotTo(Succeed())
+		})
+		It("rejects hooks defined more than once", func() {
+			ds.cfg.PreDrainHooks = append(ds.cfg.PreDrainHooks, ds.cfg.PreDrainHooks[0])
+			Expect(ds.cfg.IsValid()).NotTo(Succeed())
+		})
+		It("defaults the pre-drain hook timeout", func() {
+			Expect(ds.cfg.GetPreDrainHookDuration()).To(Equal(10 * time.Minute))
+		})
+	})
+
+	Context("When handing over the pods on a node", func() {
+		It("runs pending hooks, records their completion and reports manual pods", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				expectManualPodsReported(),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						Expect(obj.GetName()).To(Equal("hooked"))
+						Expect(obj.GetAnnotations()).To(HaveKey(PreDrainHookCompletedAnnotation))
+						return nil
+					}),
+			)
+			res, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.ran).To(Equal([]string{"hooked"}))
+			Expect(res).To(HaveLen(2))
+			Expect(res[0].Message).To(ContainSubstring("test-namespace/manual"))
+			Expect(res[1].Message).To(ContainSubstring("test-namespace/hooked succeeded"))
+		})
+		It("does not record the completion of a failed hook", func() {
+			runner.err = fmt.Errorf("fake error")
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				expectManualPodsReported(),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0),
+			)
+			res, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(HaveLen(2))
+			Expect(res[1].Message).To(ContainSubstring("test-namespace/hooked failed"))
+		})
+		It("does not run a hook which is not configured for the pod", func() {
+			podList.Items[0].Annotations[PreDrainHookAnnotation] = "https://example.com/handoff"
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				expectManualPodsReported(),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0),
+			)
+			res, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.ran).To(BeEmpty())
+			Expect(res[1].Message).To(ContainSubstring("no pre-drain hook"))
+		})
+		It("skips hooks which have not succeeded by the deadline", func() {
+			runner.err = fmt.Errorf("fake error")
+			cordonedAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				expectManualPodsReported(),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						Expect(obj.GetName()).To(Equal("hooked"))
+						Expect(obj.GetAnnotations()).To(HaveKey(PreDrainHookSkippedAnnotation))
+						return nil
+					}),
+			)
+			res, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.ran).To(BeEmpty())
+			Expect(res[1].Message).To(ContainSubstring("drain strategies will be applied to it"))
+		})
+		It("does not hand over DaemonSet pods or pods in ignored namespaces", func() {
+			podList.Items[0].OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds"}}
+			podList.Items[2].Namespace = "openshift-ignored"
+			ds.cfg.IgnoredNamespacePatterns = []string{"^openshift-.*"}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld("n1", metrics.NodeDrainPodManual, []types.NamespacedName{}),
+			)
+			res, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.ran).To(BeEmpty())
+			Expect(res).To(BeEmpty())
+		})
+		It("returns an error if the hook completion cannot be recorded", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				expectManualPodsReported(),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+			)
+			_, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).To(HaveOccurred())
+		})
+		It("returns an error if the pods cannot be listed", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+			_, err := ds.handOverPods(node, cordonedAt, logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When calling a pre-drain hook", func() {
+		It("posts the pod and node and succeeds on a 2xx response", func() {
+			var payload preDrainHookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			hook := &PreDrainHook{Name: "handoff", URL: server.URL}
+			Expect(hookRunner{}.Run(context.TODO(), hook, podList.Items[0], node)).To(Succeed())
+			Expect(payload).To(Equal(preDrainHookPayload{Node: "n1", Namespace: "test-namespace", Pod: "hooked"}))
+		})
+		It("fails on a non-2xx response", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			hook := &PreDrainHook{Name: "handoff", URL: server.URL}
+			Expect(hookRunner{}.Run(context.TODO(), hook, podList.Items[0], node)).NotTo(Succeed())
+		})
+	})
+})
@@ -11,6 +11,7 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	logr "github.com/go-logr/logr"
 	drain "github.com/openshift/managed-upgrade-operator/pkg/drain"
@@ -56,6 +57,20 @@ func (mr *MockNodeDrainStrategyMockRecorder) Execute(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockNodeDrainStrategy)(nil).Execute), arg0, arg1)
 }
 
+// GetRequeueAfter mocks base method.
+func (m *MockNodeDrainStrategy) GetRequeueAfter(arg0 *v1.Node) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequeueAfter", arg0)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetRequeueAfter indicates an expected call of GetRequeueAfter.
+func (mr *MockNodeDrainStrategyMockRecorder) GetRequeueAfter(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequeueAfter", reflect.TypeOf((*MockNodeDrainStrategy)(nil).GetRequeueAfter), arg0)
+}
+
 // HasFailed mocks base method.
 func (m *MockNodeDrainStrategy) HasFailed(arg0 *v1.Node, arg1 logr.Logger) (bool, error) {
 	m.ctrl.T.Helper()
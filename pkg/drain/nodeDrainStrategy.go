@@ -18,6 +18,7 @@ import (
 )
 
 var (
+	podEvictionName                = "EVICT"
 	defaultPodDeleteName           = "DELETE"
 	pdbPodDeleteName               = "PDB-DELETE"
 	defaultPodFinalizerRemovalName = "DEFAULT-FINALIZER"
@@ -26,16 +27,27 @@ var (
 )
 
 // NewNodeDrainStrategy returns a new node drain stategy
-func NewNodeDrainStrategy(c client.Client, cfg *NodeDrain, ts []TimedDrainStrategy, uc *upgradev1alpha1.UpgradeConfig,
-	notifier notifier.Notifier, metricsClient metrics.Metrics) (NodeDrainStrategy, error) {
+func NewNodeDrainStrategy(c client.Client, cfg *NodeDrain, ts []TimedDrainStrategy, policies drainPolicies, pdbs *podDisruptionBudgets, uc *upgradev1alpha1.UpgradeConfig,
+	notifier notifier.Notifier, messages *notifier.Messages, metricsClient metrics.Metrics, ledger notifier.Ledger) (NodeDrainStrategy, error) {
+	m := machinery.NewMachinery()
 	return &osdDrainStrategy{
 		c,
-		machinery.NewMachinery(),
+		m,
 		cfg,
 		ts,
 		uc,
 		notifier,
+		messages,
 		metricsClient,
+		hookRunner{},
+		&drainCoordinator{
+			client:                   c,
+			machinery:                m,
+			maxConcurrentEscalations: cfg.MaxConcurrentEscalations,
+		},
+		ledger,
+		policies,
+		pdbs,
 	}, nil
 }
 
@@ -46,7 +58,13 @@ type osdDrainStrategy struct {
 	timedDrainStrategies []TimedDrainStrategy
 	uc                   *upgradev1alpha1.UpgradeConfig
 	notifier             notifier.Notifier
+	messages             *notifier.Messages
 	metricsClient        metrics.Metrics
+	hookRunner           preDrainHookRunner
+	coordinator          *drainCoordinator
+	ledger               notifier.Ledger
+	policies             drainPolicies
+	pdbs                 *podDisruptionBudgets
 }
 
 func (ds *osdDrainStrategy) Execute(node *corev1.Node, logger logr.Logger) ([]*DrainStrategyResult, error) {
@@ -58,12 +76,31 @@ func (ds *osdDrainStrategy) Execute(node *corev1.Node, logger logr.Logger) ([]*D
 		if result.AddedAt == nil {
 			return nil, fmt.Errorf("cannot determine drain commencement time for node %v", node.Name)
 		}
+		// Pods are handed over by their pre-drain hooks before any drain strategy is applied to them
+		handOverResults, err := ds.handOverPods(node, result.AddedAt, logger)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, handOverResults...)
 		me := &multierror.Error{}
+		// Whether forceful strategies may be executed on the node is only determined once one is due
+		var mayEscalate *bool
 		for _, tds := range ds.timedDrainStrategies {
 			dsName := tds.GetName()
 			expectedTime := result.AddedAt.Add(tds.GetWaitDuration())
 			drainStrategyMsg := fmt.Sprintf("drain strategy %v for node %v, commencing drain at %v, execution expected after %v", dsName, node.Name, result.AddedAt, expectedTime)
 			if isAfter(result.AddedAt, tds.GetWaitDuration()) {
+				if isForceful(dsName) && mayEscalate == nil {
+					allowed, err := ds.coordinator.mayEscalate(node)
+					if err != nil {
+						return nil, err
+					}
+					mayEscalate = &allowed
+				}
+				if isForceful(dsName) && !*mayEscalate {
+					logger.Info(fmt.Sprintf("Deferring %s until the nodes cordoned before it are no longer escalating", drainStrategyMsg))
+					continue
+				}
 				logger.Info(fmt.Sprintf("Executing %s", drainStrategyMsg))
 				r, err := tds.GetStrategy().Execute(node, logger)
 				if err != nil {
@@ -72,18 +109,20 @@ func (ds *osdDrainStrategy) Execute(node *corev1.Node, logger logr.Logger) ([]*D
 				me = multierror.Append(err, me)
 				if r.HasExecuted {
 					if dsName == pdbPodDeleteName {
-						// Check if a notification for it has been sent successfully - if so, nothing to do
-						isNotified, err := ds.metricsClient.IsMetricNotificationEventSentSet(ds.uc.Name,
-							string(notifier.MuoStateDelayed), ds.uc.Spec.Desired.Version)
+						// Check if a notification for it has been sent successfully, or if the upgrade can't
+						// transition to it - if so, nothing to do
+						shouldNotify, err := notifier.ShouldNotify(ds.ledger, ds.uc, notifier.MuoStateDelayed)
 						if err != nil {
 							logger.Error(err, "Failed to send the service log about upgrade delay due to node drain grace period")
-							return nil, fmt.Errorf("can't check cluster metric NotificationSent: %v", err)
+							return nil, fmt.Errorf("can't check notification ledger: %v", err)
 						}
-						if !isNotified {
+						if shouldNotify {
 							logger.Info("Sending upgrade delay message about node drain grace period")
-							msg := "Node drain grace period might be impacting cluster upgrade. " +
-								"Please refer to the article for further details https://access.redhat.com/solutions/7075425"
+							msg := ds.messages.Render(notifier.MessageNodeDrainDelayed, notifier.NewMessageContext(ds.uc))
 							err = ds.notifier.NotifyState(notifier.MuoStateDelayed, msg)
+							if recordErr := ds.ledger.Record(ds.uc, notifier.MuoStateDelayed, err); recordErr != nil {
+								logger.Error(recordErr, "Failed to record the upgrade delay message in the notification ledger")
+							}
 							if err != nil {
 								logger.Error(err, "Failed to send the service log about upgrade delay due to node drain grace period")
 								return nil, err
@@ -93,12 +132,26 @@ func (ds *osdDrainStrategy) Execute(node *corev1.Node, logger logr.Logger) ([]*D
 						}
 
 					}
-					res = append(res, &DrainStrategyResult{Message: fmt.Sprintf("Executed %s . Result: %s", drainStrategyMsg, r.Message)})
+					res = append(res, &DrainStrategyResult{
+						Message:           fmt.Sprintf("Executed %s . Result: %s", drainStrategyMsg, r.Message),
+						HasExecuted:       true,
+						Strategy:          dsName,
+						BlockingPDBs:      r.BlockingPDBs,
+						PodsEvicted:       r.PodsEvicted,
+						PodsDeleted:       r.PodsDeleted,
+						FinalizersRemoved: r.FinalizersRemoved,
+						FinalizersSkipped: r.FinalizersSkipped,
+					})
 				}
 			} else {
 				logger.Info(fmt.Sprintf("Will not yet execute %s", drainStrategyMsg))
 			}
 		}
+		heldResults, err := ds.reportHeldPods(node, result.AddedAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, heldResults...)
 	}
 
 	return res, me.ErrorOrNil()
@@ -114,6 +167,15 @@ func (ds *osdDrainStrategy) HasFailed(node *corev1.Node, logger logr.Logger) (bo
 		return isAfter(result.AddedAt, ds.cfg.GetTimeOutDuration()), nil
 	}
 
+	// A node waiting for its turn to escalate its drain has not failed to drain
+	mayEscalate, err := ds.coordinator.mayEscalate(node)
+	if err != nil {
+		return false, err
+	}
+	if !mayEscalate {
+		return false, nil
+	}
+
 	sortedStrategies := sortDuration(ds.timedDrainStrategies)
 	var executedStrategies []TimedDrainStrategy
 	currentStrategyIndex := 0
@@ -150,6 +212,28 @@ func (ds *osdDrainStrategy) HasFailed(node *corev1.Node, logger logr.Logger) (bo
 	return isAfter(result.AddedAt, ds.cfg.GetTimeOutDuration()), nil
 }
 
+// GetRequeueAfter returns how long until the next drain strategy of the node is due to execute or
+// the node's drain is due to time out, or 0 if neither is still to come
+func (ds *osdDrainStrategy) GetRequeueAfter(node *corev1.Node) time.Duration {
+	result := ds.machinery.IsNodeCordoned(node)
+	if !result.IsCordoned || result.AddedAt == nil {
+		return 0
+	}
+
+	due := []time.Duration{ds.cfg.GetTimeOutDuration()}
+	for _, tds := range ds.timedDrainStrategies {
+		due = append(due, tds.GetWaitDuration(), tds.GetWaitDuration()+ds.cfg.GetExpectedDrainDuration())
+	}
+	elapsed := metav1.Now().Sub(result.AddedAt.Time)
+	var next time.Duration
+	for _, d := range due {
+		if remaining := d - elapsed; remaining > 0 && (next == 0 || remaining < next) {
+			next = remaining
+		}
+	}
+	return next
+}
+
 type timedStrategy struct {
 	name         string
 	description  string
@@ -181,8 +265,8 @@ func sortDuration(ts []TimedDrainStrategy) []TimedDrainStrategy {
 	sortedSlice := []TimedDrainStrategy{}
 	sortedSlice = append(sortedSlice, ts...)
 	sort.Slice(sortedSlice, func(i, j int) bool {
-		iWait := ts[i].GetWaitDuration()
-		jWait := ts[j].GetWaitDuration()
+		iWait := sortedSlice[i].GetWaitDuration()
+		jWait := sortedSlice[j].GetWaitDuration()
 		return iWait < jWait
 	})
 
@@ -15,6 +15,7 @@ import (
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
 	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
 	mockMachinery "github.com/openshift/managed-upgrade-operator/pkg/machinery/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
 	mockNotifier "github.com/openshift/managed-upgrade-operator/pkg/notifier/mocks"
 	"github.com/openshift/managed-upgrade-operator/pkg/pod"
@@ -64,6 +65,7 @@ var _ = Describe("OSD Drain Strategy", func() {
 			logger = logf.Log.WithName("drain strategy test logger")
 			mockNotifierClient = mockNotifier.NewMockNotifier(mockCtrl)
 			mockMetricsClient = mockMetrics.NewMockMetrics(mockCtrl)
+			mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld(gomock.Any(), metrics.NodeDrainPodManual, gomock.Any()).AnyTimes()
 			mockUpgradeConfig = &upgradev1alpha1.UpgradeConfig{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      TEST_UPGRADECONFIG_CR,
@@ -91,11 +93,18 @@ var _ = Describe("OSD Drain Strategy", func() {
 				[]TimedDrainStrategy{},
 				mockUpgradeConfig,
 				mockNotifierClient,
+				nil,
 				mockMetricsClient,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 			fiveMinsAgo := &metav1.Time{Time: time.Now().Add(-5 * time.Minute)}
 			gomock.InOrder(
 				mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: fiveMinsAgo}),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
 			)
 			result, err := osdDrain.Execute(&corev1.Node{}, logger)
 			Expect(result).To(Not(BeNil()))
@@ -110,11 +119,18 @@ var _ = Describe("OSD Drain Strategy", func() {
 				[]TimedDrainStrategy{mockTimedDrainOne},
 				mockUpgradeConfig,
 				mockNotifierClient,
+				nil,
 				mockMetricsClient,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 			fortyFiveMinsAgo := &metav1.Time{Time: time.Now().Add(-45 * time.Minute)}
 			gomock.InOrder(
 				mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: fortyFiveMinsAgo}),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
 				mockTimedDrainOne.EXPECT().GetName().Return("test strategy"),
 				mockTimedDrainOne.EXPECT().GetWaitDuration().Return(time.Minute*30).Times(2),
 				mockTimedDrainOne.EXPECT().GetStrategy().Return(mockStrategyOne),
@@ -133,11 +149,18 @@ var _ = Describe("OSD Drain Strategy", func() {
 				[]TimedDrainStrategy{mockTimedDrainOne},
 				mockUpgradeConfig,
 				mockNotifierClient,
+				nil,
 				mockMetricsClient,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 			fortyFiveMinsAgo := &metav1.Time{Time: time.Now().Add(-45 * time.Minute)}
 			gomock.InOrder(
 				mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: fortyFiveMinsAgo}),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
 				mockTimedDrainOne.EXPECT().GetWaitDuration().Return(time.Minute*60).Times(2),
 				mockStrategyOne.EXPECT().Execute(gomock.Any(), gomock.Any()).Times(0),
 				mockTimedDrainOne.EXPECT().GetDescription().Times(0).Return("Drain one"),
@@ -156,11 +179,18 @@ var _ = Describe("OSD Drain Strategy", func() {
 				[]TimedDrainStrategy{mockTimedDrainOne, mockTimedDrainTwo},
 				mockUpgradeConfig,
 				mockNotifierClient,
+				nil,
 				mockMetricsClient,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 			fortyFiveMinsAgo := &metav1.Time{Time: time.Now().Add(-45 * time.Minute)}
 			gomock.InOrder(
 				mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: fortyFiveMinsAgo}),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
 				mockTimedDrainOne.EXPECT().GetName().Return("test strategy"),
 				mockTimedDrainOne.EXPECT().GetWaitDuration().Return(time.Minute*30).Times(2),
 				mockTimedDrainOne.EXPECT().GetStrategy().Return(mockStrategyOne),
@@ -182,7 +212,13 @@ var _ = Describe("OSD Drain Strategy", func() {
 				[]TimedDrainStrategy{mockTimedDrainOne},
 				mockUpgradeConfig,
 				mockNotifierClient,
+				nil,
 				mockMetricsClient,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 			gomock.InOrder(
 				mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Times(1).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: nil}),
@@ -198,11 +234,18 @@ var _ = Describe("OSD Drain Strategy", func() {
 				[]TimedDrainStrategy{mockTimedDrainOne},
 				mockUpgradeConfig,
 				mockNotifierClient,
+				nil,
 				mockMetricsClient,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 			fortyFiveMinsAgo := &metav1.Time{Time: time.Now().Add(-45 * time.Minute)}
 			gomock.InOrder(
 				mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: fortyFiveMinsAgo}),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
 				mockTimedDrainOne.EXPECT().GetName().Return("test strategy"),
 				mockTimedDrainOne.EXPECT().GetWaitDuration().Return(time.Minute*30).Times(2),
 				mockTimedDrainOne.EXPECT().GetStrategy().Return(mockStrategyOne),
@@ -229,7 +272,13 @@ var _ = Describe("OSD Drain Strategy", func() {
 					[]TimedDrainStrategy{},
 					mockUpgradeConfig,
 					mockNotifierClient,
+					nil,
 					mockMetricsClient,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
 				}
 			})
 			AfterEach(func() {
@@ -272,7 +321,13 @@ var _ = Describe("OSD Drain Strategy", func() {
 					[]TimedDrainStrategy{mockTimedDrainTwo, mockTimedDrainOne},
 					mockUpgradeConfig,
 					mockNotifierClient,
+					nil,
 					mockMetricsClient,
+					nil,
+					nil,
+					nil,
+					nil,
+					nil,
 				}
 			})
 			AfterEach(func() {
@@ -335,6 +390,50 @@ var _ = Describe("OSD Drain Strategy", func() {
 		})
 	})
 
+	Context("Node drain requeue", func() {
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockMachineryClient = mockMachinery.NewMockMachinery(mockCtrl)
+			mockTimedDrainOne = NewMockTimedDrainStrategy(mockCtrl)
+			mockTimedDrainTwo = NewMockTimedDrainStrategy(mockCtrl)
+			nodeDrainConfig = &NodeDrain{
+				ExpectedNodeDrainTime: 8,
+				Timeout:               45,
+			}
+			osdDrain = &osdDrainStrategy{
+				machinery:            mockMachineryClient,
+				cfg:                  nodeDrainConfig,
+				timedDrainStrategies: []TimedDrainStrategy{mockTimedDrainOne, mockTimedDrainTwo},
+			}
+			mockTimedDrainOne.EXPECT().GetWaitDuration().Return(45 * time.Minute).AnyTimes()
+			mockTimedDrainTwo.EXPECT().GetWaitDuration().Return(68 * time.Minute).AnyTimes()
+		})
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+		It("should requeue when the next strategy is due", func() {
+			cordoned := &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+			mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: cordoned})
+			requeueAfter := osdDrain.GetRequeueAfter(&corev1.Node{})
+			Expect(requeueAfter).To(BeNumerically("~", 35*time.Minute, time.Second))
+		})
+		It("should requeue when the drain is due to time out after the last strategy", func() {
+			cordoned := &metav1.Time{Time: time.Now().Add(-70 * time.Minute)}
+			mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: cordoned})
+			requeueAfter := osdDrain.GetRequeueAfter(&corev1.Node{})
+			Expect(requeueAfter).To(BeNumerically("~", 6*time.Minute, time.Second))
+		})
+		It("should not requeue once every strategy and timeout has passed", func() {
+			cordoned := &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: true, AddedAt: cordoned})
+			Expect(osdDrain.GetRequeueAfter(&corev1.Node{})).To(BeZero())
+		})
+		It("should not requeue a node which is not cordoned", func() {
+			mockMachineryClient.EXPECT().IsNodeCordoned(gomock.Any()).Return(&machinery.IsCordonedResult{IsCordoned: false})
+			Expect(osdDrain.GetRequeueAfter(&corev1.Node{})).To(BeZero())
+		})
+	})
+
 	Context("Pod Predicates", func() {
 		var (
 			podList *corev1.PodList
@@ -401,12 +500,12 @@ var _ = Describe("OSD Drain Strategy", func() {
 				}
 			})
 			It("should return pods that have an associated PodDisruptionBudget", func() {
-				filteredPods := pod.FilterPods(podList, isPdbPod(pdbList))
+				filteredPods := pod.FilterPods(podList, isPdbPod(newPodDisruptionBudgets(pdbList, logf.Log)))
 				Expect(len(filteredPods.Items)).To(Equal(1))
 				Expect(filteredPods.Items[0].Name).To(Equal(pdbPodName))
 			})
 			It("should return pods that do not have an associated PodDisruptionBudget", func() {
-				filteredPods := pod.FilterPods(podList, isNotPdbPod(pdbList))
+				filteredPods := pod.FilterPods(podList, isNotPdbPod(newPodDisruptionBudgets(pdbList, logf.Log)))
 				Expect(len(filteredPods.Items)).To(Equal(2))
 				Expect(filteredPods.Items[0].Name).To(Not(Equal(pdbPodName)))
 				Expect(filteredPods.Items[1].Name).To(Not(Equal(pdbPodName)))
@@ -543,4 +642,21 @@ var _ = Describe("OSD Drain Strategy", func() {
 		})
 
 	})
+
+	Context("Sorting timed drain strategies", func() {
+		It("should sort the strategies by their wait duration", func() {
+			ts := []TimedDrainStrategy{
+				newTimedStrategy("late", "", 60*time.Minute, nil),
+				newTimedStrategy("immediate", "", 0, nil),
+				newTimedStrategy("soon", "", 30*time.Minute, nil),
+			}
+			sorted := sortDuration(ts)
+			names := []string{}
+			for _, s := range sorted {
+				names = append(names, s.GetName())
+			}
+			Expect(names).To(Equal([]string{"immediate", "soon", "late"}))
+			Expect(ts[0].GetName()).To(Equal("late"))
+		})
+	})
 })
@@ -3,14 +3,20 @@ package drain
 import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/managed-upgrade-operator/pkg/pod"
 )
 
 type podDeletionStrategy struct {
-	client  client.Client
-	filters []pod.PodPredicate
+	// name is the name of the timed drain strategy, recorded in the Events of the pods it acts on
+	name     string
+	client   client.Client
+	recorder record.EventRecorder
+	filters  []pod.PodPredicate
+	// pdbs identifies the PodDisruptionBudgets covering the pods the strategy acts on, if any
+	pdbs *podDisruptionBudgets
 }
 
 func (pds *podDeletionStrategy) Execute(node *corev1.Node, logger logr.Logger) (*DrainStrategyResult, error) {
@@ -19,15 +25,18 @@ func (pds *podDeletionStrategy) Execute(node *corev1.Node, logger logr.Logger) (
 	if err != nil {
 		return nil, err
 	}
+	logCoveringPDBs(pds.pdbs, podsToDelete, logger)
 
 	gp := int64(0)
 	res, err := pod.DeletePods(pds.client, logger, podsToDelete, true, &client.DeleteOptions{GracePeriodSeconds: &gp})
+	recordDrainEvents(pds.recorder, node, podsToDelete, res.Pods, corev1.EventTypeWarning, PodDeletedReason, pds.name, "deleted")
 	if err != nil {
 		return nil, err
 	}
 
 	return &DrainStrategyResult{
 		Message:     res.Message,
+		PodsDeleted: res.Pods,
 		HasExecuted: res.NumMarkedForDeletion > 0,
 	}, nil
 }
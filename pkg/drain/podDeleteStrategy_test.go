@@ -9,6 +9,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/openshift/managed-upgrade-operator/pkg/pod"
@@ -94,6 +95,28 @@ var _ = Describe("Pod Delete Strategy", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("Records Events on the deleted pods, their controller and the node", func() {
+			recorder := record.NewFakeRecorder(10)
+			pds.name = pdbPodDeleteName
+			pds.recorder = recorder
+			isController := true
+			podList.Items[0].OwnerReferences = []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs1", Controller: &isController},
+			}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				mockKubeClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()),
+			)
+			_, err := pds.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(recorder.Events).To(HaveLen(3))
+			for i := 0; i < 3; i++ {
+				event := <-recorder.Events
+				Expect(event).To(HavePrefix("Warning " + PodDeletedReason))
+				Expect(event).To(ContainSubstring("Pod test-namespace/pod1 deleted by drain strategy PDB-DELETE on node n1"))
+			}
+		})
+
 		It("Does nothing if pod already has deletion time stamp", func() {
 			noDeletePods := corev1.PodList{
 				Items: []corev1.Pod{
@@ -0,0 +1,81 @@
+package drain
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podDisruptionBudgets associates pods with the PodDisruptionBudgets covering them
+type podDisruptionBudgets struct {
+	budgets []podDisruptionBudget
+}
+
+type podDisruptionBudget struct {
+	pdb      policyv1.PodDisruptionBudget
+	selector labels.Selector
+}
+
+// newPodDisruptionBudgets evaluates the selectors of the given PodDisruptionBudgets.
+// A PodDisruptionBudget with a selector which cannot be evaluated is logged and ignored.
+func newPodDisruptionBudgets(pdbList *policyv1.PodDisruptionBudgetList, logger logr.Logger) *podDisruptionBudgets {
+	pdbs := &podDisruptionBudgets{}
+	for _, pdb := range pdbList.Items {
+		// A nil selector matches no pods, an empty selector matches every pod in the namespace
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("unable to evaluate the selector of PodDisruptionBudget %s/%s", pdb.Namespace, pdb.Name))
+			continue
+		}
+		pdbs.budgets = append(pdbs.budgets, podDisruptionBudget{pdb: pdb, selector: selector})
+	}
+	return pdbs
+}
+
+// coveringPDB returns the PodDisruptionBudget covering the pod, or nil if the pod is not covered
+func (p *podDisruptionBudgets) coveringPDB(pod corev1.Pod) *policyv1.PodDisruptionBudget {
+	for i := range p.budgets {
+		b := &p.budgets[i]
+		if b.pdb.Namespace != pod.Namespace {
+			continue
+		}
+		if b.selector.Matches(labels.Set(pod.Labels)) {
+			return &b.pdb
+		}
+	}
+	return nil
+}
+
+// coveredPod is a pod together with the PodDisruptionBudget covering it, if any
+type coveredPod struct {
+	pod corev1.Pod
+	pdb *policyv1.PodDisruptionBudget
+}
+
+// annotate pairs each pod of the list with the PodDisruptionBudget covering it
+func (p *podDisruptionBudgets) annotate(pl *corev1.PodList) []coveredPod {
+	covered := make([]coveredPod, 0, len(pl.Items))
+	for _, pod := range pl.Items {
+		covered = append(covered, coveredPod{pod: pod, pdb: p.coveringPDB(pod)})
+	}
+	return covered
+}
+
+// logCoveringPDBs logs the PodDisruptionBudget covering each pod a strategy is about to act on
+func logCoveringPDBs(pdbs *podDisruptionBudgets, pl *corev1.PodList, logger logr.Logger) {
+	if pdbs == nil {
+		return
+	}
+	for _, cp := range pdbs.annotate(pl) {
+		if cp.pdb != nil {
+			logger.Info(fmt.Sprintf("Pod %s/%s is covered by PodDisruptionBudget %s/%s", cp.pod.Namespace, cp.pod.Name, cp.pdb.Namespace, cp.pdb.Name))
+		}
+	}
+}
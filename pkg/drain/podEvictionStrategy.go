@@ -0,0 +1,116 @@
+package drain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+)
+
+// podEvictionStrategy evicts pods through the Eviction API so that PodDisruptionBudgets are respected
+type podEvictionStrategy struct {
+	// name is the name of the timed drain strategy, recorded in the Events of the pods it acts on
+	name     string
+	client   client.Client
+	recorder record.EventRecorder
+	filters  []pod.PodPredicate
+	// pdbs identifies the PodDisruptionBudgets covering the pods the strategy acts on, if any
+	pdbs *podDisruptionBudgets
+}
+
+func (pes *podEvictionStrategy) Execute(node *corev1.Node, logger logr.Logger) (*DrainStrategyResult, error) {
+	filters := append([]pod.PodPredicate{isOnNode(node), isNotTerminating}, pes.filters...)
+	podsToEvict, err := pod.GetPodList(pes.client, node, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	me := &multierror.Error{}
+	var evicted []string
+	var podsEvicted []string
+	var blockingPDBs []string
+	// Budgets which have refused an eviction are backed off for the remainder of this execution,
+	// the other pods they cover would be refused too
+	blocked := map[string]bool{}
+	throttled := false
+	for _, p := range podsToEvict.Items {
+		p := p
+		if throttled {
+			break
+		}
+		pdbName := ""
+		if pes.pdbs != nil {
+			if pdb := pes.pdbs.coveringPDB(p); pdb != nil {
+				pdbName = pdb.Namespace + "/" + pdb.Name
+			}
+		}
+		if pdbName != "" && blocked[pdbName] {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Applying pod eviction drain strategy to pod %v/%v", p.Namespace, p.Name))
+		err := pes.client.SubResource("eviction").Create(context.TODO(), &p, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      p.Name,
+				Namespace: p.Namespace,
+			},
+		})
+		switch {
+		case err == nil:
+			evicted = append(evicted, p.Name)
+			podsEvicted = append(podsEvicted, p.Namespace+"/"+p.Name)
+		case apierrors.IsNotFound(err):
+			logger.Info(fmt.Sprintf("Ignoring evicting pod %v/%v because it no longer exists", p.Namespace, p.Name))
+		case apierrors.IsTooManyRequests(err):
+			if pdbName == "" {
+				// The API server is throttling evictions, back off until the next execution
+				logger.Info(fmt.Sprintf("Eviction of pod %v/%v was throttled, backing off", p.Namespace, p.Name))
+				throttled = true
+				break
+			}
+			logger.Info(fmt.Sprintf("Eviction of pod %v/%v is blocked by PodDisruptionBudget %s, backing off", p.Namespace, p.Name, pdbName))
+			blocked[pdbName] = true
+			blockingPDBs = append(blockingPDBs, pdbName)
+		default:
+			logger.Error(err, fmt.Sprintf("failed to evict the pod %v/%v", p.Namespace, p.Name))
+			me = multierror.Append(err, me)
+		}
+	}
+
+	recordDrainEvents(pes.recorder, node, podsToEvict, podsEvicted, corev1.EventTypeNormal, PodEvictedReason, pes.name, "evicted")
+	if err := me.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	msg := fmt.Sprintf("Pod(s) %s have been evicted", strings.Join(evicted, ","))
+	if len(blockingPDBs) > 0 {
+		msg = fmt.Sprintf("%s. Eviction blocked by PodDisruptionBudget(s) %s", msg, strings.Join(blockingPDBs, ","))
+	}
+
+	return &DrainStrategyResult{
+		Message:      msg,
+		HasExecuted:  len(evicted) > 0 || len(blockingPDBs) > 0,
+		BlockingPDBs: blockingPDBs,
+		PodsEvicted:  podsEvicted,
+	}, nil
+}
+
+func (pes *podEvictionStrategy) IsValid(node *corev1.Node, logger logr.Logger) (bool, error) {
+	filters := append([]pod.PodPredicate{isOnNode(node), isNotTerminating}, pes.filters...)
+	targetPods, err := pod.GetPodList(pes.client, node, filters)
+	if err != nil {
+		return false, err
+	}
+
+	return len(targetPods.Items) > 0, nil
+}
@@ -0,0 +1,227 @@
+package drain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/mock/gomock"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pod Eviction Strategy", func() {
+
+	const (
+		POD_NAMESPACE = "test-namespace"
+	)
+
+	var (
+		logger            logr.Logger
+		mockCtrl          *gomock.Controller
+		mockKubeClient    *mocks.MockClient
+		mockSubResource   *mocks.MockSubResourceClient
+		pes               *podEvictionStrategy
+		node              *corev1.Node
+		podList           corev1.PodList
+		tooManyRequestErr error
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockSubResource = mocks.NewMockSubResourceClient(mockCtrl)
+		logger = logf.Log.WithName("pod eviction strategy test logger")
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "n1",
+			},
+		}
+		pes = &podEvictionStrategy{
+			client:  mockKubeClient,
+			filters: []pod.PodPredicate{isOnNode(node)},
+			pdbs: newPodDisruptionBudgets(&policyv1.PodDisruptionBudgetList{
+				Items: []policyv1.PodDisruptionBudget{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-pdb", Namespace: POD_NAMESPACE},
+						Spec: policyv1.PodDisruptionBudgetSpec{
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "pdb"}},
+						},
+					},
+				},
+			}, logf.Log),
+		}
+		tooManyRequestErr = apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+
+		podList = corev1.PodList{
+			Items: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod1",
+						Namespace: POD_NAMESPACE,
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "n1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pod2",
+						Namespace:         POD_NAMESPACE,
+						DeletionTimestamp: &metav1.Time{Time: time.Now()},
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "n1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod3",
+						Namespace: POD_NAMESPACE,
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "n2",
+					},
+				},
+			},
+		}
+	})
+
+	Context("Execute pod eviction strategy on a node", func() {
+
+		It("Successfully evicts pods on a node which are not terminating", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				mockKubeClient.EXPECT().SubResource("eviction").Return(mockSubResource),
+				mockSubResource.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, obj *corev1.Pod, sub *policyv1.Eviction, _ ...interface{}) error {
+						Expect(obj.Name).To(Equal("pod1"))
+						Expect(sub.Name).To(Equal("pod1"))
+						Expect(sub.Namespace).To(Equal(POD_NAMESPACE))
+						return nil
+					}),
+			)
+			result, err := pes.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(result.HasExecuted).To(BeTrue())
+			Expect(result.BlockingPDBs).To(BeEmpty())
+		})
+
+		It("Records the PDB blocking an eviction and backs off the other pods it covers", func() {
+			pdbPods := corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "pdb-pod1", Namespace: POD_NAMESPACE, Labels: map[string]string{"app": "pdb"}},
+						Spec:       corev1.PodSpec{NodeName: "n1"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "pdb-pod2", Namespace: POD_NAMESPACE, Labels: map[string]string{"app": "pdb"}},
+						Spec:       corev1.PodSpec{NodeName: "n1"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "other-pod", Namespace: POD_NAMESPACE},
+						Spec:       corev1.PodSpec{NodeName: "n1"},
+					},
+				},
+			}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, pdbPods),
+				mockKubeClient.EXPECT().SubResource("eviction").Return(mockSubResource),
+				mockSubResource.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(tooManyRequestErr),
+				mockKubeClient.EXPECT().SubResource("eviction").Return(mockSubResource),
+				mockSubResource.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+			)
+			result, err := pes.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(result.HasExecuted).To(BeTrue())
+			Expect(result.BlockingPDBs).To(Equal([]string{POD_NAMESPACE + "/test-pdb"}))
+			Expect(result.Message).To(ContainSubstring("other-pod"))
+			Expect(result.Message).To(ContainSubstring("blocked by PodDisruptionBudget(s) " + POD_NAMESPACE + "/test-pdb"))
+		})
+
+		It("Stops evicting when the API server throttles evictions", func() {
+			unprotectedPods := corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "p1", Namespace: POD_NAMESPACE},
+						Spec:       corev1.PodSpec{NodeName: "n1"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "p2", Namespace: POD_NAMESPACE},
+						Spec:       corev1.PodSpec{NodeName: "n1"},
+					},
+				},
+			}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, unprotectedPods),
+				mockKubeClient.EXPECT().SubResource("eviction").Return(mockSubResource),
+				mockSubResource.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(tooManyRequestErr),
+			)
+			result, err := pes.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(result.HasExecuted).To(BeFalse())
+			Expect(result.BlockingPDBs).To(BeEmpty())
+		})
+
+		It("Ignores pods which no longer exist", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				mockKubeClient.EXPECT().SubResource("eviction").Return(mockSubResource),
+				mockSubResource.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "pod1")),
+			)
+			result, err := pes.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(result.HasExecuted).To(BeFalse())
+		})
+
+		It("Returns error if fails to return a list of pods", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList).Return(fmt.Errorf("fake error")),
+			)
+			_, err := pes.Execute(node, logger)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Returns error if failed to evict pod", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+				mockKubeClient.EXPECT().SubResource("eviction").Return(mockSubResource),
+				mockSubResource.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+			)
+			_, err := pes.Execute(node, logger)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Check if it's still valid to evict a pod", func() {
+		It("Returns true if there are target pods to be evicted", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+			)
+			valid, err := pes.IsValid(node, logger)
+			Expect(valid).To(BeTrue())
+			Expect(err).To(BeNil())
+		})
+
+		It("Returns false if the only pods on the node are terminating", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, corev1.PodList{Items: podList.Items[1:]}),
+			)
+			valid, err := pes.IsValid(node, logger)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -3,19 +3,18 @@ package drain
 import (
 	"github.com/openshift/managed-upgrade-operator/pkg/pod"
 	corev1 "k8s.io/api/core/v1"
-	policyv1 "k8s.io/api/policy/v1"
 	"regexp"
 )
 
-func isPdbPod(pdbList *policyv1.PodDisruptionBudgetList) pod.PodPredicate {
+func isPdbPod(pdbs *podDisruptionBudgets) pod.PodPredicate {
 	return func(p corev1.Pod) bool {
-		return containsMatchLabel(p, pdbList)
+		return pdbs.coveringPDB(p) != nil
 	}
 }
 
-func isNotPdbPod(pdbList *policyv1.PodDisruptionBudgetList) pod.PodPredicate {
+func isNotPdbPod(pdbs *podDisruptionBudgets) pod.PodPredicate {
 	return func(p corev1.Pod) bool {
-		return !containsMatchLabel(p, pdbList)
+		return pdbs.coveringPDB(p) == nil
 	}
 }
 
@@ -42,24 +41,6 @@ func isNotDaemonSet(pod corev1.Pod) bool {
 	return !isDaemonSet(pod)
 }
 
-func containsMatchLabel(p corev1.Pod, pdbList *policyv1.PodDisruptionBudgetList) bool {
-	isPdbPod := false
-	for _, pdb := range pdbList.Items {
-		if pdb.Spec.Selector == nil {
-			continue
-		}
-		for mlKey, mlValue := range pdb.Spec.Selector.MatchLabels {
-			lValue, ok := p.Labels[mlKey]
-			if ok && lValue == mlValue {
-				isPdbPod = true
-				break
-			}
-		}
-	}
-
-	return isPdbPod
-}
-
 func hasFinalizers(p corev1.Pod) bool {
 	return len(p.GetFinalizers()) > 0
 }
@@ -72,6 +53,10 @@ func isTerminating(p corev1.Pod) bool {
 	return p.DeletionTimestamp != nil
 }
 
+func isNotTerminating(p corev1.Pod) bool {
+	return !isTerminating(p)
+}
+
 func isAllowedNamespace(ignoredNamespacePatterns []string) pod.PodPredicate {
 	return func(p corev1.Pod) bool {
 		return containsIgnoredNamespace(p, ignoredNamespacePatterns)
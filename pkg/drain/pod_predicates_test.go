@@ -4,6 +4,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -25,54 +26,97 @@ var _ = Describe("Pod Predicates", func() {
 				Spec: corev1.PodSpec{},
 			}
 		})
-		Context("testing containsMatchLabel", func() {
-			It("does not panic when a PDB has a nil selector", func() {
-				pdbList := &policyv1.PodDisruptionBudgetList{
-					Items: []policyv1.PodDisruptionBudget{
-						{
-							Spec: policyv1.PodDisruptionBudgetSpec{
-								Selector: nil,
-							},
-						},
-					},
+		Context("testing PodDisruptionBudget coverage", func() {
+			newPdbs := func(pdbs ...policyv1.PodDisruptionBudget) *podDisruptionBudgets {
+				return newPodDisruptionBudgets(&policyv1.PodDisruptionBudgetList{Items: pdbs}, logf.Log)
+			}
+			newPdb := func(name, namespace string, selector *metav1.LabelSelector) policyv1.PodDisruptionBudget {
+				return policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+					Spec:       policyv1.PodDisruptionBudgetSpec{Selector: selector},
 				}
+			}
+			It("does not panic when a PDB has a nil selector", func() {
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", nil))
 				Expect(func() {
-					containsMatchLabel(pod, pdbList)
+					pdbs.coveringPDB(pod)
 				}).ShouldNot(Panic())
-				r := containsMatchLabel(pod, pdbList)
-				Expect(r).To(BeFalse())
+				Expect(pdbs.coveringPDB(pod)).To(BeNil())
+				Expect(isNotPdbPod(pdbs)(pod)).To(BeTrue())
 			})
-			It("returns true when pod labels match a PDB selector", func() {
+			It("returns the PDB when pod labels match a PDB selector", func() {
 				pod.Labels = map[string]string{"app": "test"}
-				pdbList := &policyv1.PodDisruptionBudgetList{
-					Items: []policyv1.PodDisruptionBudget{
-						{
-							Spec: policyv1.PodDisruptionBudgetSpec{
-								Selector: &metav1.LabelSelector{
-									MatchLabels: map[string]string{"app": "test"},
-								},
-							},
-						},
-					},
-				}
-				r := containsMatchLabel(pod, pdbList)
-				Expect(r).To(BeTrue())
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				}))
+				pdb := pdbs.coveringPDB(pod)
+				Expect(pdb).NotTo(BeNil())
+				Expect(pdb.Name).To(Equal("test-pdb"))
+				Expect(isPdbPod(pdbs)(pod)).To(BeTrue())
 			})
-			It("returns false when pod labels do not match any PDB selector", func() {
+			It("returns nil when pod labels do not match any PDB selector", func() {
 				pod.Labels = map[string]string{"app": "other"}
-				pdbList := &policyv1.PodDisruptionBudgetList{
-					Items: []policyv1.PodDisruptionBudget{
-						{
-							Spec: policyv1.PodDisruptionBudgetSpec{
-								Selector: &metav1.LabelSelector{
-									MatchLabels: map[string]string{"app": "test"},
-								},
-							},
-						},
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				}))
+				Expect(pdbs.coveringPDB(pod)).To(BeNil())
+				Expect(isPdbPod(pdbs)(pod)).To(BeFalse())
+			})
+			It("requires every label of the selector to match", func() {
+				pod.Labels = map[string]string{"app": "test"}
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test", "tier": "backend"},
+				}))
+				Expect(pdbs.coveringPDB(pod)).To(BeNil())
+			})
+			It("ignores PDBs in other namespaces", func() {
+				pod.Labels = map[string]string{"app": "test"}
+				pdbs := newPdbs(newPdb("test-pdb", "other-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				}))
+				Expect(pdbs.coveringPDB(pod)).To(BeNil())
+			})
+			It("evaluates selector match expressions", func() {
+				pod.Labels = map[string]string{"app": "test"}
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"test", "other"}},
 					},
-				}
-				r := containsMatchLabel(pod, pdbList)
-				Expect(r).To(BeFalse())
+				}))
+				Expect(pdbs.coveringPDB(pod)).NotTo(BeNil())
+
+				pdbs = newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"test"}},
+					},
+				}))
+				Expect(pdbs.coveringPDB(pod)).To(BeNil())
+			})
+			It("covers every pod in the namespace with an empty selector", func() {
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{}))
+				Expect(pdbs.coveringPDB(pod)).NotTo(BeNil())
+			})
+			It("ignores PDBs with a selector that cannot be evaluated", func() {
+				pod.Labels = map[string]string{"app": "test"}
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: "Invalid", Values: []string{"test"}},
+					},
+				}))
+				Expect(pdbs.coveringPDB(pod)).To(BeNil())
+			})
+			It("annotates a pod list with the covering PDBs", func() {
+				covered := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "covered", Namespace: "test-namespace", Labels: map[string]string{"app": "test"}}}
+				uncovered := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "uncovered", Namespace: "test-namespace"}}
+				pdbs := newPdbs(newPdb("test-pdb", "test-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				}))
+				result := pdbs.annotate(&corev1.PodList{Items: []corev1.Pod{covered, uncovered}})
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].pod.Name).To(Equal("covered"))
+				Expect(result[0].pdb.Name).To(Equal("test-pdb"))
+				Expect(result[1].pod.Name).To(Equal("uncovered"))
+				Expect(result[1].pdb).To(BeNil())
 			})
 		})
 		Context("testing if pod namespace is allowed", func() {
@@ -0,0 +1,194 @@
+package drain
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+)
+
+// DrainPolicy overrides the drain strategies applied to the pods it selects
+type DrainPolicy struct {
+	// Name identifies the policy
+	Name string `yaml:"name"`
+	// NamespacePatterns selects pods in namespaces matching any of the regular expressions
+	NamespacePatterns []string `yaml:"namespacePatterns"`
+	// PodSelector selects pods by a label selector, e.g. "app in (postgres,mysql)"
+	PodSelector string `yaml:"podSelector"`
+	// Strategies lists the drain strategies allowed for the selected pods, all strategies are allowed if empty
+	Strategies []string `yaml:"strategies"`
+	// Timeout overrides the time in minutes to wait before forcing the drain of the selected pods
+	Timeout *int `yaml:"timeOut"`
+	// NeverForce prevents the selected pods from being deleted or having their finalizers removed
+	NeverForce bool `yaml:"neverForce"`
+}
+
+// forcefulStrategies are the drain strategies which delete pods or remove their finalizers
+var forcefulStrategies = map[string]bool{
+	defaultPodDeleteName:           true,
+	pdbPodDeleteName:               true,
+	defaultPodFinalizerRemovalName: true,
+	pdbPodFinalizerRemovalName:     true,
+	stuckTerminatingPodName:        true,
+}
+
+// IsValid returns an error if the drain policy is invalid
+func (dp *DrainPolicy) IsValid() error {
+	if dp.Name == "" {
+		return fmt.Errorf("drain policy name is required")
+	}
+	if len(dp.NamespacePatterns) == 0 && dp.PodSelector == "" {
+		return fmt.Errorf("drain policy %s must set namespacePatterns or podSelector", dp.Name)
+	}
+	for _, p := range dp.NamespacePatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("drain policy %s namespace pattern %q is invalid: %v", dp.Name, p, err)
+		}
+	}
+	if _, err := labels.Parse(dp.PodSelector); err != nil {
+		return fmt.Errorf("drain policy %s podSelector is invalid: %v", dp.Name, err)
+	}
+	for _, s := range dp.Strategies {
+		if s != podEvictionName && !forcefulStrategies[s] {
+			return fmt.Errorf("drain policy %s strategy %s is unknown", dp.Name, s)
+		}
+		if dp.NeverForce && forcefulStrategies[s] {
+			return fmt.Errorf("drain policy %s strategy %s is not allowed with neverForce", dp.Name, s)
+		}
+	}
+	if dp.Timeout != nil && *dp.Timeout < 0 {
+		return fmt.Errorf("drain policy %s timeOut is invalid", dp.Name)
+	}
+	return nil
+}
+
+// allows returns true if the policy allows the named drain strategy
+func (dp *DrainPolicy) allows(strategy string) bool {
+	if dp.NeverForce && forcefulStrategies[strategy] {
+		return false
+	}
+	if len(dp.Strategies) == 0 {
+		return true
+	}
+	for _, s := range dp.Strategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// drainPolicy is a DrainPolicy with its selectors evaluated
+type drainPolicy struct {
+	*DrainPolicy
+	namespaces []*regexp.Regexp
+	selector   labels.Selector
+}
+
+func (dp *drainPolicy) selects(p corev1.Pod) bool {
+	if len(dp.namespaces) > 0 {
+		matched := false
+		for _, rxp := range dp.namespaces {
+			if rxp.MatchString(p.Namespace) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return dp.selector.Matches(labels.Set(p.Labels))
+}
+
+// drainPolicies assigns pods to the first drain policy selecting them
+type drainPolicies []*drainPolicy
+
+func newDrainPolicies(policies []DrainPolicy) (drainPolicies, error) {
+	dps := drainPolicies{}
+	for i := range policies {
+		dp := &policies[i]
+		if err := dp.IsValid(); err != nil {
+			return nil, err
+		}
+		selector, err := labels.Parse(dp.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		compiled := &drainPolicy{DrainPolicy: dp, selector: selector}
+		for _, p := range dp.NamespacePatterns {
+			compiled.namespaces = append(compiled.namespaces, regexp.MustCompile(p))
+		}
+		dps = append(dps, compiled)
+	}
+	return dps, nil
+}
+
+// policyFor returns the drain policy governing the pod, or nil if no policy selects it
+func (dps drainPolicies) policyFor(p corev1.Pod) *drainPolicy {
+	for _, dp := range dps {
+		if dp.selects(p) {
+			return dp
+		}
+	}
+	return nil
+}
+
+func isGovernedBy(dps drainPolicies, dp *drainPolicy) pod.PodPredicate {
+	return func(p corev1.Pod) bool {
+		return dps.policyFor(p) == dp
+	}
+}
+
+// durations returns the wait durations of the default and PDB strategies for the policy's pods
+func (dp *drainPolicy) durations(defaultDuration, pdbDuration time.Duration) (time.Duration, time.Duration) {
+	if dp.Timeout == nil {
+		return defaultDuration, pdbDuration
+	}
+	d := time.Duration(*dp.Timeout) * time.Minute
+	return d, d
+}
+
+// reportHeldPods reports the pods on the node which a neverForce drain policy holds back from the
+// forceful drain strategies, once those strategies would otherwise have been applied to them.
+// Pods covered by a PodDisruptionBudget are held back once the PDB strategies would have applied.
+func (ds *osdDrainStrategy) reportHeldPods(node *corev1.Node, cordonedAt *metav1.Time) ([]*DrainStrategyResult, error) {
+	neverForce := false
+	for _, dp := range ds.policies {
+		neverForce = neverForce || dp.NeverForce
+	}
+	if !neverForce {
+		return nil, nil
+	}
+
+	pods, err := pod.GetPodList(ds.client, node, []pod.PodPredicate{isOnNode(node), isNotDaemonSet, isAllowedNamespace(ds.cfg.IgnoredNamespacePatterns)})
+	if err != nil {
+		return nil, err
+	}
+	res := []*DrainStrategyResult{}
+	held := []types.NamespacedName{}
+	for _, p := range pods.Items {
+		dp := ds.policies.policyFor(p)
+		if dp == nil || !dp.NeverForce {
+			continue
+		}
+		forceAfter, pdbForceAfter := dp.durations(ds.cfg.GetTimeOutDuration(), ds.uc.GetPDBDrainTimeoutDuration()+ds.cfg.GetExpectedDrainDuration())
+		if ds.pdbs != nil && ds.pdbs.coveringPDB(p) != nil {
+			forceAfter = pdbForceAfter
+		}
+		if !isAfter(cordonedAt, forceAfter) {
+			continue
+		}
+		held = append(held, types.NamespacedName{Namespace: p.Namespace, Name: p.Name})
+		res = append(res, &DrainStrategyResult{Message: fmt.Sprintf("Pod %v/%v on node %v is held back from being forcefully drained by drain policy %s", p.Namespace, p.Name, node.Name, dp.Name)})
+	}
+	ds.metricsClient.UpdateMetricNodeDrainPodsHeld(node.Name, metrics.NodeDrainPodNeverForced, held)
+	return res, nil
+}
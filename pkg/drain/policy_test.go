@@ -0,0 +1,227 @@
+package drain
+
+import (
+	"time"
+
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drain Policies", func() {
+
+	intPtr := func(i int) *int { return &i }
+
+	Context("When validating drain policies", func() {
+		It("accepts a valid policy", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{
+				{Name: "databases", PodSelector: "app in (postgres,mysql)", NeverForce: true},
+				{Name: "batch", NamespacePatterns: []string{"batch-.+"}, Timeout: intPtr(0)},
+			}}
+			Expect(nd.IsValid()).To(Succeed())
+		})
+		It("rejects a policy without a name", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{PodSelector: "app=db"}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects a policy without a selector", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{Name: "all"}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects duplicate policy names", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{
+				{Name: "db", PodSelector: "app=db"},
+				{Name: "db", PodSelector: "app=cache"},
+			}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects an invalid namespace pattern", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{Name: "bad", NamespacePatterns: []string{"("}}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects an invalid pod selector", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{Name: "bad", PodSelector: "app in ("}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects an unknown strategy", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{Name: "bad", PodSelector: "app=db", Strategies: []string{"UNKNOWN"}}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects a forceful strategy on a policy which never forces", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{Name: "bad", PodSelector: "app=db", NeverForce: true, Strategies: []string{podEvictionName, pdbPodDeleteName}}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+		It("rejects a negative timeout", func() {
+			nd := NodeDrain{Policies: []DrainPolicy{{Name: "bad", PodSelector: "app=db", Timeout: intPtr(-1)}}}
+			Expect(nd.IsValid()).NotTo(Succeed())
+		})
+	})
+
+	Context("When assigning pods to drain policies", func() {
+		var policies drainPolicies
+
+		BeforeEach(func() {
+			var err error
+			policies, err = newDrainPolicies([]DrainPolicy{
+				{Name: "databases", PodSelector: "app in (postgres,mysql)"},
+				{Name: "batch", NamespacePatterns: []string{"batch-.+"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("assigns a pod to the first policy selecting it", func() {
+			p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "batch-jobs", Labels: map[string]string{"app": "postgres"}}}
+			Expect(policies.policyFor(p).Name).To(Equal("databases"))
+		})
+		It("selects pods by namespace pattern", func() {
+			p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "batch-jobs"}}
+			Expect(policies.policyFor(p).Name).To(Equal("batch"))
+			Expect(isGovernedBy(policies, policies[1])(p)).To(BeTrue())
+			Expect(isGovernedBy(policies, nil)(p)).To(BeFalse())
+		})
+		It("leaves pods which no policy selects to the default strategies", func() {
+			p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Labels: map[string]string{"app": "frontend"}}}
+			Expect(policies.policyFor(p)).To(BeNil())
+			Expect(isGovernedBy(policies, nil)(p)).To(BeTrue())
+		})
+	})
+
+	Context("When composing timed drain strategies", func() {
+		var (
+			defaultDuration = 60 * time.Minute
+			pdbDuration     = 120 * time.Minute
+			pdbs            = &podDisruptionBudgets{}
+		)
+
+		strategiesByDescription := func(ts []TimedDrainStrategy) map[string]time.Duration {
+			result := map[string]time.Duration{}
+			for _, t := range ts {
+				result[t.GetDescription()] = t.GetWaitDuration()
+			}
+			return result
+		}
+
+		It("composes only the default strategies without policies", func() {
+			ts := composeTimedStrategies(nil, nil, pdbs, nil, drainPolicies{}, []pod.PodPredicate{isNotDaemonSet}, defaultDuration, pdbDuration)
+			Expect(ts).To(HaveLen(6))
+		})
+		It("only evicts the pods of a policy which never forces", func() {
+			policies, err := newDrainPolicies([]DrainPolicy{{Name: "databases", PodSelector: "app=postgres", NeverForce: true}})
+			Expect(err).NotTo(HaveOccurred())
+			ts := composeTimedStrategies(nil, nil, pdbs, nil, policies, []pod.PodPredicate{isNotDaemonSet}, defaultDuration, pdbDuration)
+			Expect(ts).To(HaveLen(7))
+			byDescription := strategiesByDescription(ts)
+			Expect(byDescription).To(HaveKeyWithValue("Pod eviction for drain policy databases", time.Duration(0)))
+			Expect(byDescription).NotTo(HaveKey("PDB pod deletion for drain policy databases"))
+		})
+		It("applies the timeout and allowed strategies of a policy", func() {
+			policies, err := newDrainPolicies([]DrainPolicy{{Name: "batch", NamespacePatterns: []string{"batch-.+"}, Timeout: intPtr(0),
+				Strategies: []string{defaultPodDeleteName, pdbPodDeleteName}}})
+			Expect(err).NotTo(HaveOccurred())
+			ts := composeTimedStrategies(nil, nil, pdbs, nil, policies, []pod.PodPredicate{isNotDaemonSet}, defaultDuration, pdbDuration)
+			Expect(ts).To(HaveLen(8))
+			byDescription := strategiesByDescription(ts)
+			Expect(byDescription).To(HaveKeyWithValue("Default pod deletion for drain policy batch", time.Duration(0)))
+			Expect(byDescription).To(HaveKeyWithValue("PDB pod deletion for drain policy batch", time.Duration(0)))
+			Expect(byDescription).To(HaveKeyWithValue("PDB pod deletion", pdbDuration))
+			Expect(byDescription).NotTo(HaveKey("Pod eviction for drain policy batch"))
+		})
+		It("keeps the default timers for a policy without a timeout", func() {
+			policies, err := newDrainPolicies([]DrainPolicy{{Name: "web", PodSelector: "tier=web"}})
+			Expect(err).NotTo(HaveOccurred())
+			ts := composeTimedStrategies(nil, nil, pdbs, nil, policies, []pod.PodPredicate{isNotDaemonSet}, defaultDuration, pdbDuration)
+			byDescription := strategiesByDescription(ts)
+			Expect(byDescription).To(HaveKeyWithValue("Default pod deletion for drain policy web", defaultDuration))
+			Expect(byDescription).To(HaveKeyWithValue("PDB pod deletion for drain policy web", pdbDuration))
+		})
+	})
+
+	Context("When reporting the pods held back by drain policies", func() {
+		var (
+			mockCtrl          *gomock.Controller
+			mockKubeClient    *mocks.MockClient
+			mockMetricsClient *mockMetrics.MockMetrics
+			ds                *osdDrainStrategy
+			node              *corev1.Node
+			podList           *corev1.PodList
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockKubeClient = mocks.NewMockClient(mockCtrl)
+			mockMetricsClient = mockMetrics.NewMockMetrics(mockCtrl)
+			node = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}}
+			policies, err := newDrainPolicies([]DrainPolicy{{Name: "databases", PodSelector: "app=postgres", NeverForce: true}})
+			Expect(err).NotTo(HaveOccurred())
+			ds = &osdDrainStrategy{
+				client:        mockKubeClient,
+				cfg:           &NodeDrain{Timeout: 30},
+				policies:      policies,
+				pdbs:          &podDisruptionBudgets{},
+				uc:            &upgradev1alpha1.UpgradeConfig{Spec: upgradev1alpha1.UpgradeConfigSpec{PDBForceDrainTimeout: 60}},
+				metricsClient: mockMetricsClient,
+			}
+			podList = &corev1.PodList{Items: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "db", Labels: map[string]string{"app": "postgres"}}, Spec: corev1.PodSpec{NodeName: "n1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "web", Labels: map[string]string{"app": "frontend"}}, Spec: corev1.PodSpec{NodeName: "n1"}},
+			}}
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("reports the pods of a policy which never forces once their forceful strategies are due", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *podList),
+				mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld("n1", metrics.NodeDrainPodNeverForced, []types.NamespacedName{{Namespace: "db", Name: "postgres"}}),
+			)
+			res, err := ds.reportHeldPods(node, &metav1.Time{Time: time.Now().Add(-time.Hour)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(HaveLen(1))
+			Expect(res[0].Message).To(ContainSubstring("db/postgres"))
+		})
+		It("does not report the pods before their forceful strategies are due", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *podList),
+				mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld("n1", metrics.NodeDrainPodNeverForced, []types.NamespacedName{}),
+			)
+			res, err := ds.reportHeldPods(node, &metav1.Time{Time: time.Now().Add(-10 * time.Minute)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEmpty())
+		})
+		It("does not report the pods covered by a PodDisruptionBudget before the PDB strategies are due", func() {
+			ds.pdbs = newPodDisruptionBudgets(&policyv1.PodDisruptionBudgetList{Items: []policyv1.PodDisruptionBudget{
+				{ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "db"}, Spec: policyv1.PodDisruptionBudgetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "postgres"}}}},
+			}}, logf.Log)
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *podList),
+				mockMetricsClient.EXPECT().UpdateMetricNodeDrainPodsHeld("n1", metrics.NodeDrainPodNeverForced, []types.NamespacedName{}),
+			)
+			res, err := ds.reportHeldPods(node, &metav1.Time{Time: time.Now().Add(-45 * time.Minute)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEmpty())
+		})
+		It("does nothing without a policy which never forces", func() {
+			policies, err := newDrainPolicies([]DrainPolicy{{Name: "databases", PodSelector: "app=postgres"}})
+			Expect(err).NotTo(HaveOccurred())
+			ds.policies = policies
+			res, err := ds.reportHeldPods(node, &metav1.Time{Time: time.Now().Add(-time.Hour)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEmpty())
+		})
+	})
+})
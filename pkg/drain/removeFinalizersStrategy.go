@@ -1,16 +1,26 @@
 package drain
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/managed-upgrade-operator/pkg/pod"
 )
 
 type removeFinalizersStrategy struct {
-	client  client.Client
-	filters []pod.PodPredicate
+	// name is the name of the timed drain strategy, recorded in the Events of the pods it acts on
+	name     string
+	client   client.Client
+	recorder record.EventRecorder
+	filters  []pod.PodPredicate
+	// pdbs identifies the PodDisruptionBudgets covering the pods the strategy acts on, if any
+	pdbs *podDisruptionBudgets
+	// removable matches the finalizers which may be removed, all finalizers may be removed if nil
+	removable pod.FinalizerPredicate
 }
 
 func (rfs *removeFinalizersStrategy) Execute(node *corev1.Node, logger logr.Logger) (*DrainStrategyResult, error) {
@@ -19,20 +29,30 @@ func (rfs *removeFinalizersStrategy) Execute(node *corev1.Node, logger logr.Logg
 	if err != nil {
 		return nil, err
 	}
+	logCoveringPDBs(rfs.pdbs, podsWithFinalizers, logger)
 
-	res, err := pod.RemoveFinalizersFromPod(rfs.client, logger, podsWithFinalizers)
+	// The finalizers of pods whose controller may manage them are left to the controller
+	res, err := pod.RemoveFinalizersFromPod(rfs.client, logger, pod.FilterPods(podsWithFinalizers, hasNoFinalizerManagingOwner), rfs.removable)
+	for _, p := range pod.FilterPods(podsWithFinalizers, hasFinalizerManagingOwner).Items {
+		logger.Info(fmt.Sprintf("Skipping remove finalizer strategy for pod %v/%v as its controller may manage its finalizers", p.Namespace, p.Name))
+		res.Skipped = append(res.Skipped, p.Namespace+"/"+p.Name)
+	}
+	recordDrainEvents(rfs.recorder, node, podsWithFinalizers, res.Pods, corev1.EventTypeWarning, FinalizersRemovedReason, rfs.name, "had its finalizers removed")
+	recordDrainEvents(rfs.recorder, node, podsWithFinalizers, res.Skipped, corev1.EventTypeWarning, FinalizersSkippedReason, rfs.name, "was not allowed to have any of its finalizers removed")
 	if err != nil {
 		return nil, err
 	}
 
 	return &DrainStrategyResult{
-		Message:     res.Message,
-		HasExecuted: res.NumRemoved > 0,
+		Message:           res.Message,
+		FinalizersRemoved: res.Pods,
+		FinalizersSkipped: res.Skipped,
+		HasExecuted:       res.NumRemoved > 0,
 	}, nil
 }
 
 func (rfs *removeFinalizersStrategy) IsValid(node *corev1.Node, logger logr.Logger) (bool, error) {
-	filters := append([]pod.PodPredicate{isOnNode(node), hasFinalizers}, rfs.filters...)
+	filters := append([]pod.PodPredicate{isOnNode(node), hasRemovableFinalizers(rfs.removable), hasNoFinalizerManagingOwner}, rfs.filters...)
 	targetPods, err := pod.GetPodList(rfs.client, node, filters)
 
 	if err != nil {
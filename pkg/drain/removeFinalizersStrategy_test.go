@@ -9,6 +9,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -96,8 +97,8 @@ var _ = Describe("Remove Finalizer Strategy", func() {
 		It("Successfully removes finalizers from pod with finalizer", func() {
 			gomock.InOrder(
 				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
-				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-					func(ctx context.Context, pod *corev1.Pod, uo ...client.UpdateOption) error {
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, pod *corev1.Pod, patch client.Patch, po ...client.PatchOption) error {
 						Expect(len(pod.ObjectMeta.Finalizers)).To(Equal(0))
 						return nil
 					}),
@@ -107,6 +108,33 @@ var _ = Describe("Remove Finalizer Strategy", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("Skips pods none of whose finalizers may be removed", func() {
+			recorder := record.NewFakeRecorder(10)
+			rfs.recorder = recorder
+			rfs.removable = func(f string) bool { return f != "finalizer1" }
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+			)
+			result, err := rfs.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(result.HasExecuted).To(BeFalse())
+			Expect(result.FinalizersSkipped).To(Equal([]string{POD_NAMESPACE + "/pod1"}))
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(<-recorder.Events).To(HavePrefix("Warning " + FinalizersSkippedReason))
+		})
+
+		It("Skips pods whose controller may manage their finalizers", func() {
+			controller := true
+			podList.Items[0].OwnerReferences = []metav1.OwnerReference{{APIVersion: "example.com/v1", Kind: "Database", Name: "db", Controller: &controller}}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+			)
+			result, err := rfs.Execute(node, logger)
+			Expect(err).To(BeNil())
+			Expect(result.HasExecuted).To(BeFalse())
+			Expect(result.FinalizersSkipped).To(Equal([]string{POD_NAMESPACE + "/pod1"}))
+		})
+
 		It("Does nothing if no pod found with finalizer", func() {
 			noFinalizerPods := corev1.PodList{
 				Items: []corev1.Pod{
@@ -140,7 +168,7 @@ var _ = Describe("Remove Finalizer Strategy", func() {
 		It("Returns error if failed to remove finalizer from the pod", func() {
 			gomock.InOrder(
 				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
-				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
 			)
 			_, err := rfs.Execute(node, logger)
 			Expect(err).To(HaveOccurred())
@@ -158,6 +186,27 @@ var _ = Describe("Remove Finalizer Strategy", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("Returns false if none of the finalizers of the target pods may be removed", func() {
+			rfs.removable = func(f string) bool { return f != "finalizer1" }
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+			)
+			valid, err := rfs.IsValid(node, logger)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(BeNil())
+		})
+
+		It("Returns false if the controllers of the target pods may manage their finalizers", func() {
+			controller := true
+			podList.Items[0].OwnerReferences = []metav1.OwnerReference{{APIVersion: "example.com/v1", Kind: "Database", Name: "db", Controller: &controller}}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList),
+			)
+			valid, err := rfs.IsValid(node, logger)
+			Expect(valid).To(BeFalse())
+			Expect(err).To(BeNil())
+		})
+
 		It("Returns false if there are any errors while getting list of pods", func() {
 			gomock.InOrder(
 				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, podList).Return(fmt.Errorf("fake error")),
@@ -0,0 +1,157 @@
+package drain
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/pod"
+)
+
+// PodDrainOutcome describes how a pod is expected to leave a node being drained
+type PodDrainOutcome string
+
+const (
+	// PodDrainEvicted pods are expected to be evicted as soon as the node is drained
+	PodDrainEvicted PodDrainOutcome = "Evicted"
+	// PodDrainForced pods are expected to be refused eviction by a PodDisruptionBudget until a drain strategy forcefully removes them
+	PodDrainForced PodDrainOutcome = "Forced"
+	// PodDrainBlocked pods are expected to be refused eviction by a PodDisruptionBudget and never to be forcefully removed
+	PodDrainBlocked PodDrainOutcome = "Blocked"
+	// PodDrainManual pods are expected to be drained manually
+	PodDrainManual PodDrainOutcome = "Manual"
+)
+
+// PodDrainPlan predicts how a pod leaves a node being drained
+type PodDrainPlan struct {
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Outcome   PodDrainOutcome `json:"outcome"`
+	// Strategy is the name of the drain strategy expected to remove the pod, if any
+	Strategy string `json:"strategy,omitempty"`
+	// After is the time after the node is cordoned at which the strategy is expected to remove the pod
+	After *metav1.Duration `json:"after,omitempty"`
+	// PDB is the namespaced name of the PodDisruptionBudget covering the pod, if any
+	PDB string `json:"pdb,omitempty"`
+	// Policy is the name of the drain policy governing the pod, if any
+	Policy string `json:"policy,omitempty"`
+}
+
+// NodeDrainPlan predicts how the pods of a node leave it when the node is drained
+type NodeDrainPlan struct {
+	Node string         `json:"node"`
+	Pods []PodDrainPlan `json:"pods,omitempty"`
+}
+
+// BlockingPDBs returns the namespaced names of the PodDisruptionBudgets expected to refuse the eviction of pods of the node
+func (ndp *NodeDrainPlan) BlockingPDBs() []string {
+	var blocking []string
+	for _, p := range ndp.Pods {
+		if p.Outcome == PodDrainForced || p.Outcome == PodDrainBlocked {
+			blocking = union(blocking, []string{p.PDB})
+		}
+	}
+	return blocking
+}
+
+// SimulateNodeDrains predicts how the pods of each node would leave it if the node were drained,
+// without cordoning or draining any node. The prediction applies the same pod selection, drain
+// policies and timeouts as the drain strategies, and the disruptions currently allowed by each
+// PodDisruptionBudget. The UpgradeConfig may be nil if no upgrade is configured.
+func SimulateNodeDrains(c client.Client, nodes []corev1.Node, uc *upgradev1alpha1.UpgradeConfig, cfg *NodeDrain, logger logr.Logger) ([]NodeDrainPlan, error) {
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	err := c.List(context.TODO(), pdbList)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := newDrainPolicies(cfg.Policies)
+	if err != nil {
+		return nil, err
+	}
+	pdbs := newPodDisruptionBudgets(pdbList, logger)
+	filters := []pod.PodPredicate{isNotDaemonSet, isAllowedNamespace(cfg.IgnoredNamespacePatterns), isNotTerminating}
+	defaultDuration := cfg.GetTimeOutDuration()
+	pdbDuration := cfg.GetExpectedDrainDuration()
+	if uc != nil {
+		pdbDuration += uc.GetPDBDrainTimeoutDuration()
+	}
+
+	plans := []NodeDrainPlan{}
+	for i := range nodes {
+		node := &nodes[i]
+		pl, err := pod.GetPodList(c, node, filters)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, simulateNodeDrain(node, pl, pdbs, policies, defaultDuration, pdbDuration))
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Node < plans[j].Node
+	})
+	return plans, nil
+}
+
+// simulateNodeDrain predicts how the pods of the node leave it. Each pod covered by a PodDisruptionBudget
+// which allows no further disruption is expected to be refused eviction until it is forcefully removed.
+func simulateNodeDrain(node *corev1.Node, pl *corev1.PodList, pdbs *podDisruptionBudgets, policies drainPolicies, defaultDuration time.Duration, pdbDuration time.Duration) NodeDrainPlan {
+	plan := NodeDrainPlan{Node: node.Name}
+	// disruptions holds the disruptions each PodDisruptionBudget still allows as the pods of the node are evicted
+	disruptions := map[string]int32{}
+	for _, p := range pl.Items {
+		pp := PodDrainPlan{
+			Namespace: p.Namespace,
+			Name:      p.Name,
+		}
+		dp := policies.policyFor(p)
+		policyPdbDuration := pdbDuration
+		if dp != nil {
+			pp.Policy = dp.Name
+			_, policyPdbDuration = dp.durations(defaultDuration, pdbDuration)
+		}
+		if isManualDrainPod(p) {
+			pp.Outcome = PodDrainManual
+			plan.Pods = append(plan.Pods, pp)
+			continue
+		}
+
+		var pdb *policyv1.PodDisruptionBudget
+		if pdbs != nil {
+			pdb = pdbs.coveringPDB(p)
+		}
+		if pdb != nil {
+			pp.PDB = pdb.Namespace + "/" + pdb.Name
+			if _, ok := disruptions[pp.PDB]; !ok {
+				disruptions[pp.PDB] = pdb.Status.DisruptionsAllowed
+			}
+		}
+		if pdb == nil || disruptions[pp.PDB] > 0 {
+			if pdb != nil {
+				disruptions[pp.PDB]--
+			}
+			pp.Outcome = PodDrainEvicted
+			pp.Strategy = podEvictionName
+			pp.After = &metav1.Duration{}
+			plan.Pods = append(plan.Pods, pp)
+			continue
+		}
+
+		pp.Outcome = PodDrainBlocked
+		for _, s := range []string{pdbPodDeleteName, pdbPodFinalizerRemovalName} {
+			if dp == nil || dp.allows(s) {
+				pp.Outcome = PodDrainForced
+				pp.Strategy = s
+				pp.After = &metav1.Duration{Duration: policyPdbDuration}
+				break
+			}
+		}
+		plan.Pods = append(plan.Pods, pp)
+	}
+	return plan
+}
@@ -0,0 +1,122 @@
+package drain
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drain Simulation", func() {
+
+	var (
+		mockCtrl       *gomock.Controller
+		mockKubeClient *mocks.MockClient
+		node           corev1.Node
+		pdbList        *policyv1.PodDisruptionBudgetList
+		podList        *corev1.PodList
+		cfg            *NodeDrain
+	)
+
+	newPod := func(namespace, name, app string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels:    map[string]string{"app": app},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "n1",
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		node = corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}}
+		pdbList = &policyv1.PodDisruptionBudgetList{
+			Items: []policyv1.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pdb1"},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					},
+					Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+				},
+			},
+		}
+		podList = &corev1.PodList{
+			Items: []corev1.Pod{
+				newPod("ns1", "web", "web"),
+				newPod("ns1", "db-0", "db"),
+				newPod("ns1", "db-1", "db"),
+			},
+		}
+		cfg = &NodeDrain{Timeout: 45, ExpectedNodeDrainTime: 8}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When simulating the drain of the nodes", func() {
+		It("Predicts the eviction of pods until their PodDisruptionBudget allows no further disruption", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *pdbList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *podList),
+			)
+			plans, err := SimulateNodeDrains(mockKubeClient, []corev1.Node{node}, nil, cfg, logf.Log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plans).To(HaveLen(1))
+			Expect(plans[0].Node).To(Equal("n1"))
+			Expect(plans[0].Pods).To(Equal([]PodDrainPlan{
+				{Namespace: "ns1", Name: "web", Outcome: PodDrainEvicted, Strategy: podEvictionName, After: &metav1.Duration{}},
+				{Namespace: "ns1", Name: "db-0", Outcome: PodDrainEvicted, Strategy: podEvictionName, After: &metav1.Duration{}, PDB: "ns1/pdb1"},
+				{Namespace: "ns1", Name: "db-1", Outcome: PodDrainForced, Strategy: pdbPodDeleteName, After: &metav1.Duration{Duration: 8 * time.Minute}, PDB: "ns1/pdb1"},
+			}))
+			Expect(plans[0].BlockingPDBs()).To(Equal([]string{"ns1/pdb1"}))
+		})
+
+		It("Predicts pods are never forced if their drain policy never forces them", func() {
+			cfg.Policies = []DrainPolicy{{Name: "databases", PodSelector: "app=db", NeverForce: true}}
+			pdbList.Items[0].Status.DisruptionsAllowed = 0
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *pdbList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *podList),
+			)
+			plans, err := SimulateNodeDrains(mockKubeClient, []corev1.Node{node}, nil, cfg, logf.Log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plans[0].Pods[1].Outcome).To(Equal(PodDrainBlocked))
+			Expect(plans[0].Pods[1].Policy).To(Equal("databases"))
+			Expect(plans[0].Pods[1].Strategy).To(BeEmpty())
+		})
+
+		It("Predicts pods annotated for a manual drain are drained manually", func() {
+			podList.Items[0].Annotations = map[string]string{DrainPolicyAnnotation: DrainPolicyManual}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *pdbList),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *podList),
+			)
+			plans, err := SimulateNodeDrains(mockKubeClient, []corev1.Node{node}, nil, cfg, logf.Log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plans[0].Pods[0].Outcome).To(Equal(PodDrainManual))
+		})
+
+		It("Returns an error if the PodDisruptionBudgets cannot be listed", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+			_, err := SimulateNodeDrains(mockKubeClient, []corev1.Node{node}, nil, cfg, logf.Log)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,128 @@
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+// NodeDrainStatusAnnotation holds the JSON encoded NodeDrainStatus of the node's most recent drain
+const NodeDrainStatusAnnotation = "upgrade.managed.openshift.io/drain-status"
+
+// GetNodeDrainStatus returns the drain status recorded on the node, or nil if none is recorded
+func GetNodeDrainStatus(node *corev1.Node) (*upgradev1alpha1.NodeDrainStatus, error) {
+	value, ok := node.Annotations[NodeDrainStatusAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	status := &upgradev1alpha1.NodeDrainStatus{}
+	if err := json.Unmarshal([]byte(value), status); err != nil {
+		return nil, fmt.Errorf("failed to parse the drain status of node %s: %v", node.Name, err)
+	}
+	return status, nil
+}
+
+// RecordNodeDrainStatus records the results of the drain strategies executed on the node, and whether
+// the node has failed to drain, in the node's drain status annotation. A drain status recorded for an
+// earlier upgrade, or an earlier drain of the node, is replaced.
+func RecordNodeDrainStatus(c client.Client, node *corev1.Node, version string, startTime *metav1.Time, results []*DrainStrategyResult, failed bool) error {
+	existing, err := GetNodeDrainStatus(node)
+	if err != nil {
+		// An unreadable drain status is replaced rather than blocking the drain
+		existing = nil
+	}
+	if startTime != nil {
+		// The drain status is stored with second precision
+		st := startTime.Rfc3339Copy()
+		startTime = &st
+	}
+	status := &upgradev1alpha1.NodeDrainStatus{Version: version, StartTime: startTime}
+	if existing != nil && existing.Version == version && existing.StartTime.Equal(startTime) {
+		status = existing.DeepCopy()
+	}
+
+	now := metav1.Now()
+	for _, r := range results {
+		if r.Strategy == "" {
+			continue
+		}
+		execution := getStrategyExecution(status, r.Strategy)
+		if execution == nil {
+			status.Strategies = append(status.Strategies, upgradev1alpha1.DrainStrategyExecution{
+				Name:               r.Strategy,
+				FirstExecutionTime: now,
+			})
+			execution = &status.Strategies[len(status.Strategies)-1]
+		}
+		execution.LastExecutionTime = now
+		execution.PodsEvicted = union(execution.PodsEvicted, r.PodsEvicted)
+		execution.PodsDeleted = union(execution.PodsDeleted, r.PodsDeleted)
+		execution.FinalizersRemoved = union(execution.FinalizersRemoved, r.FinalizersRemoved)
+		execution.FinalizersSkipped = union(execution.FinalizersSkipped, r.FinalizersSkipped)
+		status.BlockingPDBs = union(status.BlockingPDBs, r.BlockingPDBs)
+	}
+	status.Failed = failed
+
+	if existing != nil && reflect.DeepEqual(existing, status) {
+		return nil
+	}
+	value, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[NodeDrainStatusAnnotation] = string(value)
+	return c.Patch(context.TODO(), node, patch)
+}
+
+// SummarizeNodeDrain summarises the drain status of the named node
+func SummarizeNodeDrain(node string, status *upgradev1alpha1.NodeDrainStatus) upgradev1alpha1.NodeDrainSummary {
+	summary := upgradev1alpha1.NodeDrainSummary{
+		Node:         node,
+		StartTime:    status.StartTime,
+		BlockingPDBs: status.BlockingPDBs,
+		Failed:       status.Failed,
+	}
+	for _, s := range status.Strategies {
+		summary.Strategies = append(summary.Strategies, s.Name)
+		summary.PodsEvicted += len(s.PodsEvicted)
+		summary.PodsDeleted += len(s.PodsDeleted)
+		summary.FinalizersRemoved += len(s.FinalizersRemoved)
+		summary.FinalizersSkipped = union(summary.FinalizersSkipped, s.FinalizersSkipped)
+	}
+	return summary
+}
+
+func getStrategyExecution(status *upgradev1alpha1.NodeDrainStatus, name string) *upgradev1alpha1.DrainStrategyExecution {
+	for i := range status.Strategies {
+		if status.Strategies[i].Name == name {
+			return &status.Strategies[i]
+		}
+	}
+	return nil
+}
+
+// union returns the items of a followed by the items of b not already in a
+func union(a []string, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, item := range a {
+		seen[item] = true
+	}
+	for _, item := range b {
+		if !seen[item] {
+			seen[item] = true
+			a = append(a, item)
+		}
+	}
+	return a
+}
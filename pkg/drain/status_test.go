@@ -0,0 +1,130 @@
+package drain
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node Drain Status", func() {
+
+	var (
+		mockCtrl       *gomock.Controller
+		mockKubeClient *mocks.MockClient
+		node           *corev1.Node
+		startTime      *metav1.Time
+		patched        *upgradev1alpha1.NodeDrainStatus
+	)
+
+	expectPatch := func() {
+		mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				var err error
+				patched, err = GetNodeDrainStatus(obj.(*corev1.Node))
+				Expect(err).NotTo(HaveOccurred())
+				return nil
+			})
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		node = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}}
+		startTime = &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+		patched = nil
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("records the executed strategies and blocking PDBs on the node", func() {
+		expectPatch()
+		results := []*DrainStrategyResult{
+			{Message: "Pre-drain hook for pod ns/a succeeded"},
+			{Strategy: podEvictionName, PodsEvicted: []string{"ns/a"}, BlockingPDBs: []string{"ns/pdb"}},
+		}
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime, results, false)).To(Succeed())
+		Expect(patched.Version).To(Equal("4.14.14"))
+		Expect(patched.StartTime.Unix()).To(Equal(startTime.Unix()))
+		Expect(patched.Strategies).To(HaveLen(1))
+		Expect(patched.Strategies[0].Name).To(Equal(podEvictionName))
+		Expect(patched.Strategies[0].PodsEvicted).To(Equal([]string{"ns/a"}))
+		Expect(patched.BlockingPDBs).To(Equal([]string{"ns/pdb"}))
+		Expect(patched.Failed).To(BeFalse())
+	})
+
+	It("records the pods whose finalizers a strategy was not allowed to remove", func() {
+		expectPatch()
+		results := []*DrainStrategyResult{
+			{Strategy: defaultPodFinalizerRemovalName, FinalizersRemoved: []string{"ns/a"}, FinalizersSkipped: []string{"ns/b"}},
+		}
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime, results, false)).To(Succeed())
+		Expect(patched.Strategies).To(HaveLen(1))
+		Expect(patched.Strategies[0].FinalizersRemoved).To(Equal([]string{"ns/a"}))
+		Expect(patched.Strategies[0].FinalizersSkipped).To(Equal([]string{"ns/b"}))
+	})
+
+	It("merges further executions of a strategy into the recorded status", func() {
+		expectPatch()
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime,
+			[]*DrainStrategyResult{{Strategy: pdbPodDeleteName, PodsDeleted: []string{"ns/a"}}}, false)).To(Succeed())
+		first := patched.Strategies[0].FirstExecutionTime
+
+		expectPatch()
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime,
+			[]*DrainStrategyResult{{Strategy: pdbPodDeleteName, PodsDeleted: []string{"ns/a", "ns/b"}}}, true)).To(Succeed())
+		Expect(patched.Strategies).To(HaveLen(1))
+		Expect(patched.Strategies[0].FirstExecutionTime.Unix()).To(Equal(first.Unix()))
+		Expect(patched.Strategies[0].PodsDeleted).To(Equal([]string{"ns/a", "ns/b"}))
+		Expect(patched.Failed).To(BeTrue())
+	})
+
+	It("does not patch the node if the status is unchanged", func() {
+		expectPatch()
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime, nil, false)).To(Succeed())
+
+		mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime, nil, false)).To(Succeed())
+	})
+
+	It("replaces a status recorded for an earlier upgrade", func() {
+		node.Annotations = map[string]string{
+			NodeDrainStatusAnnotation: `{"version":"4.14.13","strategies":[{"name":"DELETE","firstExecutionTime":"2024-06-20T00:00:00Z","lastExecutionTime":"2024-06-20T00:00:00Z"}]}`,
+		}
+		expectPatch()
+		Expect(RecordNodeDrainStatus(mockKubeClient, node, "4.14.14", startTime, nil, false)).To(Succeed())
+		Expect(patched.Version).To(Equal("4.14.14"))
+		Expect(patched.Strategies).To(BeEmpty())
+	})
+
+	It("summarises a drain status", func() {
+		status := &upgradev1alpha1.NodeDrainStatus{
+			Version: "4.14.14",
+			Strategies: []upgradev1alpha1.DrainStrategyExecution{
+				{Name: podEvictionName, PodsEvicted: []string{"ns/a", "ns/b"}},
+				{Name: pdbPodFinalizerRemovalName, FinalizersRemoved: []string{"ns/b"}, FinalizersSkipped: []string{"ns/c"}},
+				{Name: defaultPodFinalizerRemovalName, FinalizersSkipped: []string{"ns/c", "ns/d"}},
+			},
+			BlockingPDBs: []string{"ns/pdb"},
+		}
+		Expect(SummarizeNodeDrain("n1", status)).To(Equal(upgradev1alpha1.NodeDrainSummary{
+			Node:              "n1",
+			Strategies:        []string{podEvictionName, pdbPodFinalizerRemovalName, defaultPodFinalizerRemovalName},
+			PodsEvicted:       2,
+			FinalizersRemoved: 1,
+			FinalizersSkipped: []string{"ns/c", "ns/d"},
+			BlockingPDBs:      []string{"ns/pdb"},
+		}))
+	})
+})
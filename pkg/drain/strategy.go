@@ -2,16 +2,20 @@ package drain
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/go-logr/logr"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/config"
 	"github.com/openshift/managed-upgrade-operator/pkg/configmanager"
 	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
@@ -33,6 +37,7 @@ type NodeDrainStrategyBuilder interface {
 type NodeDrainStrategy interface {
 	Execute(*corev1.Node, logr.Logger) ([]*DrainStrategyResult, error)
 	HasFailed(*corev1.Node, logr.Logger) (bool, error)
+	GetRequeueAfter(*corev1.Node) time.Duration
 }
 
 // DrainStrategy enables implementation for a DrainStrategy
@@ -53,12 +58,81 @@ type TimedDrainStrategy interface {
 	GetStrategy() DrainStrategy
 }
 
-// NewBuilder returns a drainStrategyBuilder
-func NewBuilder() NodeDrainStrategyBuilder {
-	return &drainStrategyBuilder{}
+// NewBuilder returns a drainStrategyBuilder whose strategies record Events with the given recorder
+func NewBuilder(recorder record.EventRecorder) NodeDrainStrategyBuilder {
+	return &drainStrategyBuilder{
+		recorder: recorder,
+	}
 }
 
-type drainStrategyBuilder struct{}
+type drainStrategyBuilder struct {
+	// recorder records the Events of the pods acted on by the drain strategies, if set
+	recorder record.EventRecorder
+
+	// mutex guards the notifier, messages and metrics client shared by the drain strategies, and the
+	// resourceVersion of the operator's ConfigMap they were built from
+	mutex         sync.Mutex
+	notifier      notifier.Notifier
+	messages      *notifier.Messages
+	metricsClient metrics.Metrics
+	configVersion string
+}
+
+// clients returns the notifier, notification messages and metrics client shared by the drain strategies
+// built by the builder. They are built on first use rather than for every drain strategy, as building
+// them reads the operator's config and makes requests to the API server and OCM. They are built again
+// once the operator's ConfigMap has changed.
+func (dsb *drainStrategyBuilder) clients(c client.Client) (notifier.Notifier, *notifier.Messages, metrics.Metrics, error) {
+	dsb.mutex.Lock()
+	defer dsb.mutex.Unlock()
+	err := dsb.resetOnConfigChange(c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dsb.metricsClient == nil {
+		m, err := metrics.NewBuilder().NewClient(c)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		dsb.metricsClient = m
+	}
+	if dsb.notifier == nil {
+		cmBuilder := configmanager.NewBuilder()
+		n, err := notifier.NewBuilder(dsb.recorder).New(c, cmBuilder, upgradeconfigmanager.NewBuilder(), dsb.metricsClient)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		messages, err := notifier.ReadMessages(c, cmBuilder)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		dsb.notifier = n
+		dsb.messages = messages
+	}
+	return dsb.notifier, dsb.messages, dsb.metricsClient, nil
+}
+
+// resetOnConfigChange drops the clients built from an earlier version of the operator's ConfigMap, so that
+// changes to the notifier config and notification messages are picked up. The caller must hold the mutex.
+func (dsb *drainStrategyBuilder) resetOnConfigChange(c client.Client) error {
+	target := config.CMTarget{}
+	cmTarget, err := target.NewCMTarget()
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{}
+	err = c.Get(context.TODO(), client.ObjectKey{Name: cmTarget.Name, Namespace: cmTarget.Namespace}, cm)
+	if err != nil {
+		return err
+	}
+	if cm.ResourceVersion != dsb.configVersion {
+		dsb.notifier = nil
+		dsb.messages = nil
+		dsb.metricsClient = nil
+		dsb.configVersion = cm.ResourceVersion
+	}
+	return nil
+}
 
 func newTimedStrategy(name string, description string, waitDuration time.Duration, strategy DrainStrategy) TimedDrainStrategy {
 	return &timedStrategy{
@@ -80,73 +154,132 @@ func (dsb *drainStrategyBuilder) NewNodeDrainStrategy(c client.Client, logger lo
 			return nil, err
 		}
 	}
-	cmBuilder := configmanager.NewBuilder()
-	ucb := upgradeconfigmanager.NewBuilder()
-	// Notification Client Build
-	notifier, err := notifier.NewBuilder().New(c, cmBuilder, ucb)
+	n, messages, metricsClient, err := dsb.clients(c)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := newDrainPolicies(cfg.Policies)
 	if err != nil {
 		return nil, err
 	}
-	// Metrics Client Build
-	metricsClient, err := metrics.NewBuilder().NewClient(c)
+	removable, err := cfg.Finalizers.removable()
 	if err != nil {
 		return nil, err
 	}
-	defaultOsdPodPredicates := []pod.PodPredicate{isNotDaemonSet}
-	isNotPdbPod := isNotPdbPod(pdbList)
-	isPdbPod := isPdbPod(pdbList)
-	isAllowedNamespace := isAllowedNamespace(cfg.IgnoredNamespacePatterns)
+	pdbs := newPodDisruptionBudgets(pdbList, logger)
+	defaultOsdPodPredicates := []pod.PodPredicate{isNotDaemonSet, isAllowedNamespace(cfg.IgnoredNamespacePatterns), isNotManualDrainPod, hasNoPendingPreDrainHook}
 	defaultDuration := cfg.GetTimeOutDuration()
 	pdbDuration := uc.GetPDBDrainTimeoutDuration() + cfg.GetExpectedDrainDuration()
-	ts := []TimedDrainStrategy{
-		newTimedStrategy(defaultPodDeleteName, "Default pod deletion", defaultDuration, &podDeletionStrategy{
-			client:  c,
-			filters: append(defaultOsdPodPredicates, isNotPdbPod, isAllowedNamespace),
+
+	ts := composeTimedStrategies(c, dsb.recorder, pdbs, removable, policies, defaultOsdPodPredicates, defaultDuration, pdbDuration)
+
+	return NewNodeDrainStrategy(c, cfg, ts, policies, pdbs, uc, n, messages, metricsClient, notifier.NewLedger(c))
+}
+
+// composeTimedStrategies returns the timed drain strategies for every pod matching the filters.
+// Pods selected by a drain policy are drained by the strategies the policy allows, on the policy's timers.
+func composeTimedStrategies(c client.Client, recorder record.EventRecorder, pdbs *podDisruptionBudgets, removable pod.FinalizerPredicate, policies drainPolicies, filters []pod.PodPredicate, defaultDuration time.Duration, pdbDuration time.Duration) []TimedDrainStrategy {
+	ts := newTimedStrategies(c, recorder, pdbs, removable, withPredicates(filters, isGovernedBy(policies, nil)), defaultDuration, pdbDuration, "")
+	for _, dp := range policies {
+		policyDefaultDuration, policyPdbDuration := dp.durations(defaultDuration, pdbDuration)
+		for _, t := range newTimedStrategies(c, recorder, pdbs, removable, withPredicates(filters, isGovernedBy(policies, dp)),
+			policyDefaultDuration, policyPdbDuration, fmt.Sprintf(" for drain policy %s", dp.Name)) {
+			if dp.allows(t.GetName()) {
+				ts = append(ts, t)
+			}
+		}
+	}
+	return ts
+}
+
+// newTimedStrategies returns the timed drain strategies applied to the pods matching the filters
+func newTimedStrategies(c client.Client, recorder record.EventRecorder, pdbs *podDisruptionBudgets, removable pod.FinalizerPredicate, filters []pod.PodPredicate, defaultDuration time.Duration, pdbDuration time.Duration, descriptionSuffix string) []TimedDrainStrategy {
+	isNotPdbPod := isNotPdbPod(pdbs)
+	isPdbPod := isPdbPod(pdbs)
+	return []TimedDrainStrategy{
+		newTimedStrategy(podEvictionName, "Pod eviction"+descriptionSuffix, 0, &podEvictionStrategy{
+			name:     podEvictionName,
+			client:   c,
+			recorder: recorder,
+			filters:  withPredicates(filters),
+			pdbs:     pdbs,
+		}),
+		newTimedStrategy(defaultPodDeleteName, "Default pod deletion"+descriptionSuffix, defaultDuration, &podDeletionStrategy{
+			name:     defaultPodDeleteName,
+			client:   c,
+			recorder: recorder,
+			filters:  withPredicates(filters, isNotPdbPod),
 		}),
-		newTimedStrategy(defaultPodFinalizerRemovalName, "Default pod finalizer removal", defaultDuration, &removeFinalizersStrategy{
-			client:  c,
-			filters: append(defaultOsdPodPredicates, isNotPdbPod, isAllowedNamespace),
+		newTimedStrategy(defaultPodFinalizerRemovalName, "Default pod finalizer removal"+descriptionSuffix, defaultDuration, &removeFinalizersStrategy{
+			name:      defaultPodFinalizerRemovalName,
+			client:    c,
+			recorder:  recorder,
+			filters:   withPredicates(filters, isNotPdbPod),
+			removable: removable,
 		}),
-		newTimedStrategy(stuckTerminatingPodName, "Pod stuck terminating removal", defaultDuration, &stuckTerminatingStrategy{
-			client:  c,
-			filters: append(defaultOsdPodPredicates, isNotPdbPod, isAllowedNamespace),
+		newTimedStrategy(stuckTerminatingPodName, "Pod stuck terminating removal"+descriptionSuffix, defaultDuration, &stuckTerminatingStrategy{
+			name:     stuckTerminatingPodName,
+			client:   c,
+			recorder: recorder,
+			filters:  withPredicates(filters, isNotPdbPod),
 		}),
-		newTimedStrategy(pdbPodDeleteName, "PDB pod deletion", pdbDuration, &podDeletionStrategy{
-			client:  c,
-			filters: append(defaultOsdPodPredicates, isPdbPod, isAllowedNamespace),
+		newTimedStrategy(pdbPodDeleteName, "PDB pod deletion"+descriptionSuffix, pdbDuration, &podDeletionStrategy{
+			name:     pdbPodDeleteName,
+			client:   c,
+			recorder: recorder,
+			filters:  withPredicates(filters, isPdbPod),
+			pdbs:     pdbs,
 		}),
-		newTimedStrategy(pdbPodFinalizerRemovalName, "PDB Pod finalizer removal", pdbDuration, &removeFinalizersStrategy{
-			client:  c,
-			filters: append(defaultOsdPodPredicates, isPdbPod, isAllowedNamespace),
+		newTimedStrategy(pdbPodFinalizerRemovalName, "PDB Pod finalizer removal"+descriptionSuffix, pdbDuration, &removeFinalizersStrategy{
+			name:      pdbPodFinalizerRemovalName,
+			client:    c,
+			recorder:  recorder,
+			filters:   withPredicates(filters, isPdbPod),
+			pdbs:      pdbs,
+			removable: removable,
 		}),
 	}
+}
 
-	return NewNodeDrainStrategy(c, cfg, ts, uc, notifier, metricsClient)
+// withPredicates returns a new slice holding the filters followed by the additional predicates
+func withPredicates(filters []pod.PodPredicate, predicates ...pod.PodPredicate) []pod.PodPredicate {
+	result := make([]pod.PodPredicate, 0, len(filters)+len(predicates))
+	result = append(result, filters...)
+	return append(result, predicates...)
 }
 
 // NewDefaultNodeDrainStrategy returns a NodeDrainStrategy without any timed strategy
 func (dsb *drainStrategyBuilder) NewDefaultNodeDrainStrategy(c client.Client, logger logr.Logger, uc *upgradev1alpha1.UpgradeConfig, cfg *NodeDrain) (NodeDrainStrategy, error) {
 
-	cmBuilder := configmanager.NewBuilder()
-	ucb := upgradeconfigmanager.NewBuilder()
-	// Notification Client Build
-	notifier, err := notifier.NewBuilder().New(c, cmBuilder, ucb)
+	n, messages, metricsClient, err := dsb.clients(c)
 	if err != nil {
 		return nil, err
 	}
-	// Metrics Client Build
-	metricsClient, err := metrics.NewBuilder().NewClient(c)
+
+	policies, err := newDrainPolicies(cfg.Policies)
 	if err != nil {
 		return nil, err
 	}
 
 	ts := []TimedDrainStrategy{}
 
-	return NewNodeDrainStrategy(c, cfg, ts, uc, notifier, metricsClient)
+	return NewNodeDrainStrategy(c, cfg, ts, policies, nil, uc, n, messages, metricsClient, notifier.NewLedger(c))
 }
 
 // DrainStrategyResult holds fields illustrating a drain strategies result
 type DrainStrategyResult struct {
 	Message     string
 	HasExecuted bool
+	// Strategy is the name of the timed drain strategy which produced the result, if any
+	Strategy string
+	// BlockingPDBs holds the namespaced names of the PodDisruptionBudgets which refused an eviction
+	BlockingPDBs []string
+	// PodsEvicted holds the namespaced names of the pods evicted
+	PodsEvicted []string
+	// PodsDeleted holds the namespaced names of the pods deleted
+	PodsDeleted []string
+	// FinalizersRemoved holds the namespaced names of the pods whose finalizers were removed
+	FinalizersRemoved []string
+	// FinalizersSkipped holds the namespaced names of the pods none of whose finalizers may be removed
+	FinalizersSkipped []string
 }
@@ -0,0 +1,74 @@
+package drain
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/managed-upgrade-operator/config"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
+	mockNotifier "github.com/openshift/managed-upgrade-operator/pkg/notifier/mocks"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drain strategy builder", func() {
+
+	var (
+		mockCtrl       *gomock.Controller
+		mockKubeClient *mocks.MockClient
+		builder        *drainStrategyBuilder
+		configMap      *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		Expect(os.Setenv("OPERATOR_NAMESPACE", TEST_OPERATOR_NAMESPACE)).To(Succeed())
+		configMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: config.ConfigMapName, Namespace: TEST_OPERATOR_NAMESPACE, ResourceVersion: "1"}}
+		builder = &drainStrategyBuilder{
+			notifier:      mockNotifier.NewMockNotifier(mockCtrl),
+			messages:      &notifier.Messages{},
+			metricsClient: mockMetrics.NewMockMetrics(mockCtrl),
+			configVersion: "1",
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("OPERATOR_NAMESPACE")).To(Succeed())
+		mockCtrl.Finish()
+	})
+
+	Context("When the clients of the drain strategies have been built", func() {
+		It("keeps them while the operator's ConfigMap is unchanged", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), client.ObjectKey{Name: config.ConfigMapName, Namespace: TEST_OPERATOR_NAMESPACE}, gomock.Any()).SetArg(2, *configMap)
+			Expect(builder.resetOnConfigChange(mockKubeClient)).To(Succeed())
+			Expect(builder.notifier).NotTo(BeNil())
+			Expect(builder.messages).NotTo(BeNil())
+			Expect(builder.metricsClient).NotTo(BeNil())
+		})
+
+		It("drops them once the operator's ConfigMap has changed", func() {
+			configMap.ResourceVersion = "2"
+			mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *configMap)
+			Expect(builder.resetOnConfigChange(mockKubeClient)).To(Succeed())
+			Expect(builder.notifier).To(BeNil())
+			Expect(builder.messages).To(BeNil())
+			Expect(builder.metricsClient).To(BeNil())
+			Expect(builder.configVersion).To(Equal("2"))
+		})
+
+		It("returns an error if the operator's ConfigMap cannot be read", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+			Expect(builder.resetOnConfigChange(mockKubeClient)).NotTo(Succeed())
+			Expect(builder.notifier).NotTo(BeNil())
+		})
+	})
+})
@@ -3,14 +3,18 @@ package drain
 import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/managed-upgrade-operator/pkg/pod"
 )
 
 type stuckTerminatingStrategy struct {
-	client  client.Client
-	filters []pod.PodPredicate
+	// name is the name of the timed drain strategy, recorded in the Events of the pods it acts on
+	name     string
+	client   client.Client
+	recorder record.EventRecorder
+	filters  []pod.PodPredicate
 }
 
 func (sts *stuckTerminatingStrategy) Execute(node *corev1.Node, logger logr.Logger) (*DrainStrategyResult, error) {
@@ -23,12 +27,14 @@ func (sts *stuckTerminatingStrategy) Execute(node *corev1.Node, logger logr.Logg
 
 	gp := int64(0)
 	res, err := pod.DeletePods(sts.client, logger, podsStuckTerminating, false, &client.DeleteOptions{GracePeriodSeconds: &gp})
+	recordDrainEvents(sts.recorder, node, podsStuckTerminating, res.Pods, corev1.EventTypeWarning, PodDeletedReason, sts.name, "force deleted")
 	if err != nil {
 		return nil, err
 	}
 
 	return &DrainStrategyResult{
 		Message:     res.Message,
+		PodsDeleted: res.Pods,
 		HasExecuted: res.NumMarkedForDeletion > 0,
 	}, nil
 }
@@ -8,49 +8,12 @@ import (
 	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
 	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-const (
-	// Failed and Skipped descriptions
-
-	// UPGRADE_PRECHECK_FAILED_DESC describes the upgrade pre check failure
-	UPGRADE_PRECHECK_FAILED_DESC = "Cluster upgrade to version %s was cancelled as the cluster did not pass its pre-upgrade verification checks. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled"
-	// UPGRADE_PREHEALTHCHECK_FAILED_DESC describes the upgrade pre health check failure
-	UPGRADE_PREHEALTHCHECK_FAILED_DESC = "Cluster upgrade to version %s was cancelled during the Pre-Health Check step. Health alerts are firing in the cluster which could impact the upgrade's operation, so the upgrade did not proceed. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled"
-	// UPGRADE_EXTDEPCHECK_FAILED_DESC describes the upgrade external dependency check failure
-	UPGRADE_EXTDEPCHECK_FAILED_DESC = "Cluster upgrade to version %s was cancelled during the External Dependency Availability Check step. A required external dependency of the upgrade was unavailable, so the upgrade did not proceed. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled"
-	// UPGRADE_SCALE_FAILED_DESC describes the upgrade scaling failed
-	UPGRADE_SCALE_FAILED_DESC = "Cluster upgrade to version %s was cancelled during the Scale-Up Worker Node step. A temporary additional worker node was unable to be created to temporarily house workloads, so the upgrade did not proceed. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled"
-	// UPGRADE_SCALE_SKIP_DESC describes the upgrade scaling skipped
-	UPGRADE_SCALE_SKIP_DESC = "Cluster upgrade to version %s has skipped Scale-Up additional Worker Node step for compute capacity reservation. This is an informational notification and no action is required by you"
-
-	// Delayed descriptions
-
-	// UPGRADE_DEFAULT_DELAY_DESC describes the upgrade default delay
-	UPGRADE_DEFAULT_DELAY_DESC = "Cluster upgrade to version %s is experiencing a delay whilst it performs necessary pre-upgrade procedures. The upgrade will continue to retry. This is an informational notification and no action is required"
-	// UPGRADE_PREHEALTHCHECK_DELAY_DESC describes the upgrade pre health check delay
-	UPGRADE_PREHEALTHCHECK_DELAY_DESC = "Cluster upgrade to version %s is experiencing a delay as health alerts are firing in the cluster which could impact the upgrade's operation. The upgrade will continue to retry. This is an informational notification and no action is required by you"
-	// UPGRADE_EXTDEPCHECK_DELAY_DESC describes the upgrade external dependency check delay
-	UPGRADE_EXTDEPCHECK_DELAY_DESC = "Cluster upgrade to version %s is experiencing a delay as an external dependency of the upgrade is currently unavailable. The upgrade will continue to retry. This is an informational notification and no action is required by you"
-	// UPGRADE_SCALE_DELAY_DESC describes the upgrade scaling delayed
-	UPGRADE_SCALE_DELAY_DESC = "Cluster upgrade to version %s is experiencing a delay attempting to scale up an additional worker node. The upgrade will continue to retry. This is an informational notification and no action is required by you"
-	// UPGRADE_SCALE_DELAY_SKIP_DESC describes the upgrade scaling skipped after delay
-	UPGRADE_SCALE_DELAY_SKIP_DESC = "Cluster upgrade to version %s has experienced an issue during capacity reservation efforts. This could be caused by cloud service provider quota limitations or temporary connectivity issues to/from the new worker node. The upgrade will continue without extra compute. This is an informational notification and no action is required by you"
-
-	// ServiceLog descriptions
-
-	// UPGRADE_HEALTHCHECK_DELAY_DESC describes the upgrade pre health check delay
-	UPGRADE_HEALTHCHECK_DELAY_DESC = "Cluster upgrade to version %s may experience a delay as following healthcheck(s): %s are failing for the cluster which could impact the upgrade's operation."
-	// UPGRADE_HEALTHCHECK_DELAY_DESC describes the upgrade pre health check delay
-	UPGRADE_PREHEALTHCHECK_WARNING_DESC = "Cluster upgrade to version %s has been scheduled for more than 2 hours, cluster pre-upgrade health check has identified the following points which may impact the upgrade process: %s. Please take actions to review and fix the issues before the upgrade begins to have seamless upgrade experience"
-	// UPGRADE_CONTROL_PLANE_STARTED_DESC describes the control plane upgrade started
-	UPGRADE_CONTROL_PLANE_STARTED_DESC = "Cluster upgrade to version %s is starting with control and worker plane upgrade. This is an informational notification and no action is required"
-	// UPGRADE_CONTROL_PLANE_FINISHED_DESC describes the control plane upgrade finished
-	UPGRADE_CONTROL_PLANE_FINISHED_DESC = "Cluster upgrade to version %s has finished control plane upgrade. This is an informational notification and no action is required"
-	// UPGRADE_WORKER_PLANE_FINISHED_DESC describes the worker plane upgrade finished
-	UPGRADE_WORKER_PLANE_FINISHED_DESC = "Cluster upgrade to version %s has finished worker plane upgrade. This is an informational notification and no action is required."
-)
+var log = logf.Log.WithName("event-manager")
 
 // EventManager enables implementation of an EventManager
 //
@@ -67,16 +30,20 @@ type EventManagerBuilder interface {
 	NewManager(client.Client) (EventManager, error)
 }
 
-// NewBuilder returns an eventManagerBuilder
-func NewBuilder() EventManagerBuilder {
-	return &eventManagerBuilder{}
+// NewBuilder returns an eventManagerBuilder. The recorder records the Events of the EVENTS notifier.
+func NewBuilder(recorder record.EventRecorder) EventManagerBuilder {
+	return &eventManagerBuilder{recorder: recorder}
 }
 
-type eventManagerBuilder struct{}
+type eventManagerBuilder struct {
+	recorder record.EventRecorder
+}
 
 type eventManager struct {
 	client               client.Client
 	notifier             notifier.Notifier
+	ledger               notifier.Ledger
+	messages             *notifier.Messages
 	metrics              metrics.Metrics
 	upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager
 	configManagerBuilder configmanager.ConfigManagerBuilder
@@ -93,7 +60,11 @@ func (emb *eventManagerBuilder) NewManager(client client.Client) (EventManager,
 	if err != nil {
 		return nil, err
 	}
-	notifier, err := notifier.NewBuilder().New(client, cmBuilder, ucb)
+	n, err := notifier.NewBuilder(emb.recorder).New(client, cmBuilder, ucb, metricsClient)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := notifier.ReadMessages(client, cmBuilder)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +73,9 @@ func (emb *eventManagerBuilder) NewManager(client client.Client) (EventManager,
 		client:               client,
 		upgradeConfigManager: ucm,
 		metrics:              metricsClient,
-		notifier:             notifier,
+		notifier:             n,
+		ledger:               notifier.NewLedger(client),
+		messages:             messages,
 		configManagerBuilder: cmBuilder,
 	}, nil
 }
@@ -116,43 +89,48 @@ func (s *eventManager) Notify(state notifier.MuoState) error {
 		}
 		return fmt.Errorf("unable to find UpgradeConfig: %v", err)
 	}
+	s.retrySinks(uc)
 
-	// Check if a notification for it has been sent successfully - if so, nothing to do
-	isNotified, err := s.metrics.IsMetricNotificationEventSentSet(uc.Name, string(state), uc.Spec.Desired.Version)
+	// Check if a notification for it has been sent successfully, or if the upgrade can't transition
+	// to it - if so, nothing to do
+	shouldNotify, err := notifier.ShouldNotify(s.ledger, uc, state)
 	if err != nil {
-		return fmt.Errorf("can't check cluster metric NotificationSent: %v", err)
+		return fmt.Errorf("can't check notification ledger: %v", err)
 	}
-	if isNotified {
+	if !shouldNotify {
 		return nil
 	}
 
 	// Customize the state description
-	var description string
+	ctx := notifier.NewMessageContext(uc)
+	var message notifier.MessageID
 	switch state {
 	case notifier.MuoStateStarted:
-		description = fmt.Sprintf("Cluster is currently being upgraded to version %s", uc.Spec.Desired.Version)
+		message = notifier.MessageUpgradeStarted
 	case notifier.MuoStateScaleSkipped:
-		description = fmt.Sprintf(UPGRADE_SCALE_SKIP_DESC, uc.Spec.Desired.Version)
+		message = notifier.MessageUpgradeScaleSkipped
 	case notifier.MuoStateDelayed:
-		description = createDelayedDescription(uc)
+		message = delayedMessage(ctx)
 	case notifier.MuoStateSkipped:
-		description = fmt.Sprintf(UPGRADE_SCALE_DELAY_SKIP_DESC, uc.Spec.Desired.Version)
+		message = skippedMessage(ctx)
 	case notifier.MuoStateCompleted:
-		description = fmt.Sprintf("Cluster has been successfully upgraded to version %s", uc.Spec.Desired.Version)
+		message = notifier.MessageUpgradeCompleted
 	case notifier.MuoStateFailed:
-		description = createFailureDescription(uc)
+		message = failureMessage(ctx)
 	case notifier.MuoStateControlPlaneUpgradeStartedSL:
-		description = fmt.Sprintf(UPGRADE_CONTROL_PLANE_STARTED_DESC, uc.Spec.Desired.Version)
+		message = notifier.MessageControlPlaneStarted
 	case notifier.MuoStateControlPlaneUpgradeFinishedSL:
-		description = fmt.Sprintf(UPGRADE_CONTROL_PLANE_FINISHED_DESC, uc.Spec.Desired.Version)
+		message = notifier.MessageControlPlaneFinished
 	case notifier.MuoStateWorkerPlaneUpgradeFinishedSL:
-		description = fmt.Sprintf(UPGRADE_WORKER_PLANE_FINISHED_DESC, uc.Spec.Desired.Version)
+		message = notifier.MessageWorkerPlaneFinished
 	default:
 		return fmt.Errorf("state %v not yet implemented", state)
 	}
+	description := s.messages.Render(message, ctx)
 
 	// Send the notification
 	err = s.notifier.NotifyState(state, description)
+	s.record(uc, state, err)
 	if err != nil {
 		s.metrics.UpdatemetricUpgradeNotificationFailed(uc.Name, string(state))
 		return fmt.Errorf("can't send notification '%s': %v", state, err)
@@ -172,29 +150,37 @@ func (s *eventManager) NotifyResult(state notifier.MuoState, result string) erro
 		}
 		return fmt.Errorf("unable to find UpgradeConfig: %v", err)
 	}
+	s.retrySinks(uc)
 
-	// Check if a notification for it has been sent successfully - if so, nothing to do
-	isNotified, err := s.metrics.IsMetricNotificationEventSentSet(uc.Name, string(state), uc.Spec.Desired.Version)
+	// Check if a notification for it has been sent successfully, or if the upgrade can't transition
+	// to it - if so, nothing to do
+	shouldNotify, err := notifier.ShouldNotify(s.ledger, uc, state)
 	if err != nil {
-		return fmt.Errorf("can't check cluster metric NotificationSent: %v", err)
+		return fmt.Errorf("can't check notification ledger: %v", err)
 	}
-	if isNotified {
+	if !shouldNotify {
 		return nil
 	}
 
 	// Customize the state description
-	var description string
+	ctx := notifier.NewMessageContext(uc)
+	ctx.HealthCheckResults = result
+	var message notifier.MessageID
 	switch state {
 	case notifier.MuoStateHealthCheckSL:
-		description = fmt.Sprintf(UPGRADE_HEALTHCHECK_DELAY_DESC, uc.Spec.Desired.Version, result)
+		message = notifier.MessageHealthCheckDelayed
 	case notifier.MuoStatePreHealthCheckSL:
-		description = fmt.Sprintf(UPGRADE_PREHEALTHCHECK_WARNING_DESC, uc.Spec.Desired.Version, result)
+		message = notifier.MessagePreHealthCheckWarning
+	case notifier.MuoStateCanaryHealthCheckSL:
+		message = notifier.MessageCanaryHealthCheckFailed
 	default:
 		return fmt.Errorf("state %v not yet implemented", state)
 	}
+	description := s.messages.Render(message, ctx)
 
 	// Send the notification
 	err = s.notifier.NotifyState(state, description)
+	s.record(uc, state, err)
 	if err != nil {
 		return fmt.Errorf("can't send notification '%s': %v", state, err)
 	}
@@ -203,92 +189,64 @@ func (s *eventManager) NotifyResult(state notifier.MuoState, result string) erro
 	return nil
 }
 
-// Generates a Failure notification description based on the UpgradeConfig's last failed state
-func createFailureDescription(uc *v1alpha1.UpgradeConfig) string {
-	// Default failure message
-	var description = fmt.Sprintf(UPGRADE_PRECHECK_FAILED_DESC, uc.Spec.Desired.Version)
-
-	history := uc.Status.History.GetHistory(uc.Spec.Desired.Version)
-	// Handle a missing history
-	if history == nil {
-		return description
-	}
-	// Handle no conditions available
-	if len(history.Conditions) == 0 {
-		return description
+// retrySinks retries the failed deliveries of the notifications of the upgrade to the secondary sinks
+// of the notifier, if it has any
+func (s *eventManager) retrySinks(uc *v1alpha1.UpgradeConfig) {
+	if r, ok := s.notifier.(notifier.SinkRetrier); ok {
+		r.RetrySinks(uc)
 	}
+}
 
-	// Find the condition which will describe what step the upgrade got to
-	var failedCondition v1alpha1.UpgradeCondition
-	foundFailedCondition := false
-	for _, condition := range history.Conditions {
-		// Find the first incomplete condition (should only be one)
-		if condition.IsFalse() {
-			failedCondition = condition
-			foundFailedCondition = true
-			break
-		}
+// record records the result of sending the notification in the ledger. A notification which was sent
+// but could not be recorded is not reported as failed, as that would send it again.
+func (s *eventManager) record(uc *v1alpha1.UpgradeConfig, state notifier.MuoState, sendErr error) {
+	if err := s.ledger.Record(uc, state, sendErr); err != nil {
+		log.Error(err, fmt.Sprintf("failed to record notification '%s' in the notification ledger", state))
 	}
+}
 
+// Selects the Failure notification message based on the UpgradeConfig's last failed state
+func failureMessage(ctx notifier.MessageContext) notifier.MessageID {
 	// No incomplete condition? Just return default
-	if !foundFailedCondition {
-		return description
+	if ctx.FailedCondition == nil {
+		return notifier.MessageUpgradePrecheckFailed
 	}
 
-	switch failedCondition.Type {
+	switch ctx.FailedCondition.Type {
 	case v1alpha1.IsClusterUpgradable:
-		description = failedCondition.Message
+		return notifier.MessageUpgradeNotUpgradable
 	case v1alpha1.UpgradePreHealthCheck:
-		description = fmt.Sprintf(UPGRADE_PREHEALTHCHECK_FAILED_DESC, uc.Spec.Desired.Version)
+		return notifier.MessageUpgradePreHealthCheckFailed
 	case v1alpha1.ExtDepAvailabilityCheck:
-		description = fmt.Sprintf(UPGRADE_EXTDEPCHECK_FAILED_DESC, uc.Spec.Desired.Version)
+		return notifier.MessageUpgradeExtDepCheckFailed
 	case v1alpha1.UpgradeScaleUpExtraNodes:
-		description = fmt.Sprintf(UPGRADE_SCALE_FAILED_DESC, uc.Spec.Desired.Version)
+		return notifier.MessageUpgradeScaleFailed
 	}
-
-	return description
+	return notifier.MessageUpgradePrecheckFailed
 }
 
-// Generates a Delayed notification description based on the UpgradeConfig's last state
-func createDelayedDescription(uc *v1alpha1.UpgradeConfig) string {
-	// Default delayed message
-	var description = fmt.Sprintf(UPGRADE_DEFAULT_DELAY_DESC, uc.Spec.Desired.Version)
-
-	history := uc.Status.History.GetHistory(uc.Spec.Desired.Version)
-	// Handle a missing history
-	if history == nil {
-		return description
-	}
-	// Handle no conditions available
-	if len(history.Conditions) == 0 {
-		return description
-	}
-
-	// Find the condition which will describe what step the upgrade got to
-	var delayedCondition v1alpha1.UpgradeCondition
-	foundDelayedCondition := false
-	for _, condition := range history.Conditions {
-		// Find the first incomplete condition (should only be one)
-		if condition.IsFalse() {
-			delayedCondition = condition
-			foundDelayedCondition = true
-			break
-		}
-	}
-
+// Selects the Delayed notification message based on the UpgradeConfig's last state
+func delayedMessage(ctx notifier.MessageContext) notifier.MessageID {
 	// No incomplete condition? Just return default
-	if !foundDelayedCondition {
-		return description
+	if ctx.FailedCondition == nil {
+		return notifier.MessageUpgradeDefaultDelayed
 	}
 
-	switch delayedCondition.Type {
+	switch ctx.FailedCondition.Type {
 	case v1alpha1.UpgradePreHealthCheck:
-		description = fmt.Sprintf(UPGRADE_PREHEALTHCHECK_DELAY_DESC, uc.Spec.Desired.Version)
+		return notifier.MessageUpgradePreHealthCheckDelayed
 	case v1alpha1.ExtDepAvailabilityCheck:
-		description = fmt.Sprintf(UPGRADE_EXTDEPCHECK_DELAY_DESC, uc.Spec.Desired.Version)
+		return notifier.MessageUpgradeExtDepCheckDelayed
 	case v1alpha1.UpgradeScaleUpExtraNodes:
-		description = fmt.Sprintf(UPGRADE_SCALE_DELAY_DESC, uc.Spec.Desired.Version)
+		return notifier.MessageUpgradeScaleDelayed
 	}
+	return notifier.MessageUpgradeDefaultDelayed
+}
 
-	return description
+// Selects the Skipped notification message based on the UpgradeConfig's last state
+func skippedMessage(ctx notifier.MessageContext) notifier.MessageID {
+	if ctx.FailedCondition != nil && ctx.FailedCondition.Type == v1alpha1.UpgradeScaleUpExtraNodes {
+		return notifier.MessageUpgradeScaleDelaySkipped
+	}
+	return notifier.MessageUpgradeStepSkipped
 }
@@ -35,6 +35,7 @@ var _ = Describe("OCM Notifier", func() {
 		mockConfigManagerBuilder *configMock.MockConfigManagerBuilder
 		mockNotifier             *notifierMock.MockNotifier
 		mockMetricsClient        *metricsMock.MockMetrics
+		mockLedger               *notifierMock.MockLedger
 		manager                  *eventManager
 		upgradeConfigName        types.NamespacedName
 	)
@@ -47,6 +48,7 @@ var _ = Describe("OCM Notifier", func() {
 		mockConfigManagerBuilder = configMock.NewMockConfigManagerBuilder(mockCtrl)
 		mockNotifier = notifierMock.NewMockNotifier(mockCtrl)
 		mockMetricsClient = metricsMock.NewMockMetrics(mockCtrl)
+		mockLedger = notifierMock.NewMockLedger(mockCtrl)
 	})
 
 	JustBeforeEach(func() {
@@ -54,6 +56,7 @@ var _ = Describe("OCM Notifier", func() {
 			client:               mockKubeClient,
 			upgradeConfigManager: mockUpgradeConfigManager,
 			notifier:             mockNotifier,
+			ledger:               mockLedger,
 			metrics:              mockMetricsClient,
 			configManagerBuilder: mockConfigManagerBuilder,
 		}
@@ -81,7 +84,18 @@ var _ = Describe("OCM Notifier", func() {
 			It("does no action", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(true, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(true, nil),
+				)
+				err := manager.Notify(testState)
+				Expect(err).To(BeNil())
+			})
+		})
+		Context("when the upgrade can't transition to the state", func() {
+			It("does no action", func() {
+				gomock.InOrder(
+					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateFailed, nil),
 				)
 				err := manager.Notify(testState)
 				Expect(err).To(BeNil())
@@ -91,8 +105,10 @@ var _ = Describe("OCM Notifier", func() {
 			It("sends a correct notification", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, gomock.Any()),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -105,8 +121,10 @@ var _ = Describe("OCM Notifier", func() {
 			It("returns an error", func() {
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, gomock.Any()).Return(fakeError),
+					mockLedger.EXPECT().Record(&uc, testState, fakeError),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationFailed(TEST_UPGRADECONFIG_CR, string(testState)),
 				)
 				err := manager.Notify(testState)
@@ -140,11 +158,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "There are 2 critical alerts",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_PREHEALTHCHECK_FAILED_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradePreHealthCheckFailed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -163,11 +183,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "An external dependency is down.",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_EXTDEPCHECK_FAILED_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeExtDepCheckFailed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -186,11 +208,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "Cannot scale nodes.",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_SCALE_FAILED_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeScaleFailed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -209,11 +233,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "in your neighbourhood",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_PRECHECK_FAILED_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradePrecheckFailed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -222,6 +248,36 @@ var _ = Describe("OCM Notifier", func() {
 			})
 		})
 
+		Context("when the messages are configured", func() {
+			It("sends the configured description of the locale", func() {
+				uc.Status.History[0].PrecedingVersion = "4.4.3"
+				uc.Status.History[0].Conditions = []upgradev1alpha1.UpgradeCondition{
+					{
+						Type:    upgradev1alpha1.UpgradePreHealthCheck,
+						Status:  "False",
+						Reason:  "PreHealthCheck not done",
+						Message: "There are 2 critical alerts",
+					},
+				}
+				messages, err := notifier.NewMessages("fr", map[string]map[string]string{
+					"fr": {string(notifier.MessageUpgradePreHealthCheckFailed): "{{.PrecedingVersion}} -> {{.Version}}: {{.FailedCondition.Message}}"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				manager.messages = messages
+				gomock.InOrder(
+					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
+					mockNotifier.EXPECT().NotifyState(testState, "4.4.3 -> 4.4.4: There are 2 critical alerts"),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
+					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
+					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
+				)
+				err = manager.Notify(testState)
+				Expect(err).To(BeNil())
+			})
+		})
+
 	})
 
 	Context("When notifying a delayed state", func() {
@@ -248,11 +304,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "There are 2 critical alerts",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_PREHEALTHCHECK_DELAY_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradePreHealthCheckDelayed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -271,11 +329,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "An external dependency is down.",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_EXTDEPCHECK_DELAY_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeExtDepCheckDelayed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -294,11 +354,13 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "Cannot scale nodes.",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_SCALE_DELAY_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeScaleDelayed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -317,11 +379,52 @@ var _ = Describe("OCM Notifier", func() {
 						Message: "in your neighbourhood",
 					},
 				}
-				expectedDescription := fmt.Sprintf(UPGRADE_DEFAULT_DELAY_DESC, uc.Spec.Desired.Version)
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeDefaultDelayed, notifier.MessageContext{Version: uc.Spec.Desired.Version})
+				gomock.InOrder(
+					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateStarted, nil),
+					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
+					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
+					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
+				)
+				err := manager.Notify(testState)
+				Expect(err).To(BeNil())
+			})
+		})
+
+	})
+
+	Context("When notifying a skipped state", func() {
+		var uc upgradev1alpha1.UpgradeConfig
+		var testState = notifier.MuoStateSkipped
+		BeforeEach(func() {
+			upgradeConfigName = types.NamespacedName{
+				Name:      TEST_UPGRADECONFIG_CR,
+				Namespace: TEST_OPERATOR_NAMESPACE,
+			}
+			uc = *testStructs.NewUpgradeConfigBuilder().WithNamespacedName(upgradeConfigName).WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			uc.Spec.Desired.Version = TEST_UPGRADE_VERSION
+			uc.Status.History[0].Version = TEST_UPGRADE_VERSION
+			uc.Spec.UpgradeAt = TEST_UPGRADE_TIME
+		})
+
+		Context("when the scale up is skipped", func() {
+			It("sends a correct notification and description", func() {
+				uc.Status.History[0].Conditions = []upgradev1alpha1.UpgradeCondition{
+					{
+						Type:   upgradev1alpha1.UpgradeScaleUpExtraNodes,
+						Status: "False",
+					},
+				}
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeScaleDelaySkipped, notifier.MessageContext{Version: uc.Spec.Desired.Version})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateDelayed, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
@@ -330,6 +433,28 @@ var _ = Describe("OCM Notifier", func() {
 			})
 		})
 
+		Context("when another step is skipped", func() {
+			It("sends a correct default notification and description", func() {
+				uc.Status.History[0].Conditions = []upgradev1alpha1.UpgradeCondition{
+					{
+						Type:   upgradev1alpha1.ExtDepAvailabilityCheck,
+						Status: "False",
+					},
+				}
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageUpgradeStepSkipped, notifier.MessageContext{Version: uc.Spec.Desired.Version})
+				gomock.InOrder(
+					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
+					mockLedger.EXPECT().LastState(&uc).Return(notifier.MuoStateDelayed, nil),
+					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
+					mockMetricsClient.EXPECT().UpdatemetricUpgradeNotificationSucceeded(TEST_UPGRADECONFIG_CR, string(testState)),
+					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
+				)
+				err := manager.Notify(testState)
+				Expect(err).To(BeNil())
+			})
+		})
 	})
 
 	Context("When notifying a MuoStateHealthCheck state", func() {
@@ -347,11 +472,12 @@ var _ = Describe("OCM Notifier", func() {
 		})
 		Context("when the upgrade is Alerts Health Check Failed", func() {
 			It("sends a correct notification and description", func() {
-				expectedDescription := fmt.Sprintf(UPGRADE_HEALTHCHECK_DELAY_DESC, uc.Spec.Desired.Version, gomock.Any().String())
+				expectedDescription := notifier.DefaultMessages().Render(notifier.MessageHealthCheckDelayed, notifier.MessageContext{Version: uc.Spec.Desired.Version, HealthCheckResults: gomock.Any().String()})
 				gomock.InOrder(
 					mockUpgradeConfigManager.EXPECT().Get().Return(&uc, nil),
-					mockMetricsClient.EXPECT().IsMetricNotificationEventSentSet(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION).Return(false, nil),
+					mockLedger.EXPECT().IsNotified(&uc, testState).Return(false, nil),
 					mockNotifier.EXPECT().NotifyState(testState, expectedDescription),
+					mockLedger.EXPECT().Record(&uc, testState, nil),
 					mockMetricsClient.EXPECT().UpdateMetricNotificationEventSent(TEST_UPGRADECONFIG_CR, string(testState), TEST_UPGRADE_VERSION),
 				)
 				err := manager.NotifyResult(testState, gomock.Any().String())
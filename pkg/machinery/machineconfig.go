@@ -2,12 +2,33 @@ package machinery
 
 import (
 	"context"
+	"sort"
 
 	machineconfigv1 "github.com/openshift/api/machineconfiguration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// MasterPool is the name of the MachineConfigPool of the control plane nodes
+	MasterPool = "master"
+	// WorkerPool is the name of the default MachineConfigPool of the worker nodes
+	WorkerPool = "worker"
+
+	// PausedByAnnotationKey marks a MachineConfigPool as having been paused by managed-upgrade-operator,
+	// so that only pools paused by the operator are unpaused by it.
+	PausedByAnnotationKey = "upgrade.managed.openshift.io/paused"
+
+	// OriginalMaxUnavailableAnnotationKey records the maxUnavailable of a MachineConfigPool before it was
+	// tuned by managed-upgrade-operator, so that it can be restored. An empty value records that the pool
+	// had no maxUnavailable set.
+	OriginalMaxUnavailableAnnotationKey = "upgrade.managed.openshift.io/original-max-unavailable"
+)
+
 // UpgradingResult provides a struct to illustrate the upgrading result
 type UpgradingResult struct {
 	IsUpgrading  bool
@@ -30,3 +51,229 @@ func (m *machinery) IsUpgrading(c client.Client, nodeType string) (*UpgradingRes
 		MachineCount: configPool.Status.MachineCount,
 	}, nil
 }
+
+// PoolUpgradingResult provides the upgrading result of a named MachineConfigPool,
+// and whether it is paused and if so whether it was paused by managed-upgrade-operator
+type PoolUpgradingResult struct {
+	Name             string
+	Paused           bool
+	PausedByOperator bool
+	UpgradingResult
+}
+
+// WorkerPoolsUpgradingResult provides the upgrading result of all worker
+// MachineConfigPools combined, and of each of the pools ordered by name
+type WorkerPoolsUpgradingResult struct {
+	UpgradingResult
+	Pools []PoolUpgradingResult
+}
+
+// IsWorkerPool returns whether the named MachineConfigPool holds worker nodes,
+// which is the case for every pool other than the master pool
+func IsWorkerPool(name string) bool {
+	return name != MasterPool
+}
+
+// IsWorkerPoolsUpgrading determines if machines in any of the worker MachineConfigPools
+// are currently upgrading by comparing MachineCount and UpdatedMachineCount
+func (m *machinery) IsWorkerPoolsUpgrading(c client.Client) (*WorkerPoolsUpgradingResult, error) {
+	configPools := &machineconfigv1.MachineConfigPoolList{}
+	err := c.List(context.TODO(), configPools)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WorkerPoolsUpgradingResult{}
+	for _, configPool := range configPools.Items {
+		if !IsWorkerPool(configPool.Name) {
+			continue
+		}
+		_, pausedByOperator := configPool.GetAnnotations()[PausedByAnnotationKey]
+		pool := PoolUpgradingResult{
+			Name:             configPool.Name,
+			Paused:           configPool.Spec.Paused,
+			PausedByOperator: pausedByOperator,
+			UpgradingResult: UpgradingResult{
+				IsUpgrading:  configPool.Status.MachineCount != configPool.Status.UpdatedMachineCount,
+				UpdatedCount: configPool.Status.UpdatedMachineCount,
+				MachineCount: configPool.Status.MachineCount,
+			},
+		}
+		result.IsUpgrading = result.IsUpgrading || pool.IsUpgrading
+		result.UpdatedCount += pool.UpdatedCount
+		result.MachineCount += pool.MachineCount
+		result.Pools = append(result.Pools, pool)
+	}
+	sort.Slice(result.Pools, func(i, j int) bool {
+		return result.Pools[i].Name < result.Pools[j].Name
+	})
+
+	return result, nil
+}
+
+// WorkerPoolNodes returns the nodes of each worker MachineConfigPool, keyed by the name of the pool and
+// selected by the pool's node selector. As the machine-config-operator does, a node selected by both the
+// worker pool and a custom worker pool is taken to belong to the custom pool only.
+func WorkerPoolNodes(c client.Client) (map[string][]corev1.Node, error) {
+	configPools := &machineconfigv1.MachineConfigPoolList{}
+	err := c.List(context.TODO(), configPools)
+	if err != nil {
+		return nil, err
+	}
+	selectors := map[string]labels.Selector{}
+	names := []string{}
+	for _, configPool := range configPools.Items {
+		if !IsWorkerPool(configPool.Name) || configPool.Spec.NodeSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(configPool.Spec.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		selectors[configPool.Name] = selector
+		names = append(names, configPool.Name)
+	}
+	sort.Strings(names)
+
+	// The nodes are matched here rather than listed by selector, so that they can be listed once for
+	// every pool and also from an informer cache
+	nodes := &corev1.NodeList{}
+	err = c.List(context.TODO(), nodes)
+	if err != nil {
+		return nil, err
+	}
+	poolNodes := map[string][]corev1.Node{}
+	for _, node := range nodes.Items {
+		pool := ""
+		for _, name := range names {
+			if !selectors[name].Matches(labels.Set(node.Labels)) {
+				continue
+			}
+			if pool == "" || pool == WorkerPool {
+				pool = name
+			}
+		}
+		if pool != "" {
+			poolNodes[pool] = append(poolNodes[pool], node)
+		}
+	}
+	return poolNodes, nil
+}
+
+// ListWorkerNodes lists the nodes of every worker MachineConfigPool
+func ListWorkerNodes(c client.Client) (*corev1.NodeList, error) {
+	poolNodes, err := WorkerPoolNodes(c)
+	if err != nil {
+		return nil, err
+	}
+	nodes := &corev1.NodeList{}
+	for _, pn := range poolNodes {
+		nodes.Items = append(nodes.Items, pn...)
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool {
+		return nodes.Items[i].Name < nodes.Items[j].Name
+	})
+	return nodes, nil
+}
+
+// PauseMachineConfigPool pauses the MachineConfigPool and marks it as paused by
+// managed-upgrade-operator. A pool which is already paused is left untouched.
+func (m *machinery) PauseMachineConfigPool(c client.Client, nodeType string) error {
+	configPool := &machineconfigv1.MachineConfigPool{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: nodeType}, configPool)
+	if err != nil {
+		return err
+	}
+
+	if configPool.Spec.Paused {
+		return nil
+	}
+
+	patch := client.MergeFrom(configPool.DeepCopy())
+	annotations := configPool.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[PausedByAnnotationKey] = "true"
+	configPool.SetAnnotations(annotations)
+	configPool.Spec.Paused = true
+	return c.Patch(context.TODO(), configPool, patch)
+}
+
+// UnpauseMachineConfigPool unpauses the MachineConfigPool if it was previously
+// paused by managed-upgrade-operator.
+func (m *machinery) UnpauseMachineConfigPool(c client.Client, nodeType string) error {
+	configPool := &machineconfigv1.MachineConfigPool{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: nodeType}, configPool)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := configPool.GetAnnotations()[PausedByAnnotationKey]; !ok {
+		return nil
+	}
+
+	patch := client.MergeFrom(configPool.DeepCopy())
+	annotations := configPool.GetAnnotations()
+	delete(annotations, PausedByAnnotationKey)
+	configPool.SetAnnotations(annotations)
+	configPool.Spec.Paused = false
+	return c.Patch(context.TODO(), configPool, patch)
+}
+
+// SetMachineConfigPoolMaxUnavailable sets the maxUnavailable of the MachineConfigPool, recording the
+// pool's original maxUnavailable the first time it is set so that it can be restored.
+func (m *machinery) SetMachineConfigPoolMaxUnavailable(c client.Client, nodeType string, maxUnavailable int) error {
+	configPool := &machineconfigv1.MachineConfigPool{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: nodeType}, configPool)
+	if err != nil {
+		return err
+	}
+
+	value := intstr.FromInt32(int32(maxUnavailable))
+	if configPool.Spec.MaxUnavailable != nil && *configPool.Spec.MaxUnavailable == value {
+		return nil
+	}
+
+	patch := client.MergeFrom(configPool.DeepCopy())
+	annotations := configPool.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if _, ok := annotations[OriginalMaxUnavailableAnnotationKey]; !ok {
+		original := ""
+		if configPool.Spec.MaxUnavailable != nil {
+			original = configPool.Spec.MaxUnavailable.String()
+		}
+		annotations[OriginalMaxUnavailableAnnotationKey] = original
+	}
+	configPool.SetAnnotations(annotations)
+	configPool.Spec.MaxUnavailable = &value
+	return c.Patch(context.TODO(), configPool, patch)
+}
+
+// RestoreMachineConfigPoolMaxUnavailable restores the maxUnavailable of the MachineConfigPool to the
+// value it had before it was set by managed-upgrade-operator, if it was.
+func (m *machinery) RestoreMachineConfigPoolMaxUnavailable(c client.Client, nodeType string) error {
+	configPool := &machineconfigv1.MachineConfigPool{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: nodeType}, configPool)
+	if err != nil {
+		return err
+	}
+
+	original, ok := configPool.GetAnnotations()[OriginalMaxUnavailableAnnotationKey]
+	if !ok {
+		return nil
+	}
+
+	patch := client.MergeFrom(configPool.DeepCopy())
+	annotations := configPool.GetAnnotations()
+	delete(annotations, OriginalMaxUnavailableAnnotationKey)
+	configPool.SetAnnotations(annotations)
+	configPool.Spec.MaxUnavailable = nil
+	if original != "" {
+		value := intstr.Parse(original)
+		configPool.Spec.MaxUnavailable = &value
+	}
+	return c.Patch(context.TODO(), configPool, patch)
+}
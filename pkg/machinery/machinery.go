@@ -16,6 +16,11 @@ const (
 //go:generate mockgen -destination=mocks/machinery.go -package=mocks github.com/openshift/managed-upgrade-operator/pkg/machinery Machinery
 type Machinery interface {
 	IsUpgrading(c client.Client, nodeType string) (*UpgradingResult, error)
+	IsWorkerPoolsUpgrading(c client.Client) (*WorkerPoolsUpgradingResult, error)
+	PauseMachineConfigPool(c client.Client, nodeType string) error
+	UnpauseMachineConfigPool(c client.Client, nodeType string) error
+	SetMachineConfigPoolMaxUnavailable(c client.Client, nodeType string, maxUnavailable int) error
+	RestoreMachineConfigPoolMaxUnavailable(c client.Client, nodeType string) error
 	IsNodeCordoned(node *corev1.Node) *IsCordonedResult
 	IsNodeUpgrading(node *corev1.Node) bool
 	HasMemoryPressure(node *corev1.Node) bool
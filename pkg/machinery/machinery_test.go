@@ -1,6 +1,7 @@
 package machinery
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,6 +10,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -75,6 +78,257 @@ var _ = Describe("Machinery client and utils", func() {
 		})
 	})
 
+	Context("When assessing whether all worker pools are upgraded", func() {
+		var configPools *machineconfigapi.MachineConfigPoolList
+
+		JustBeforeEach(func() {
+			configPools = &machineconfigapi.MachineConfigPoolList{
+				Items: []machineconfigapi.MachineConfigPool{
+					{ObjectMeta: metav1.ObjectMeta{Name: "worker"}, Status: machineconfigapi.MachineConfigPoolStatus{MachineCount: 3, UpdatedMachineCount: 3}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "master"}, Status: machineconfigapi.MachineConfigPoolStatus{MachineCount: 3, UpdatedMachineCount: 1}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "infra"}, Status: machineconfigapi.MachineConfigPoolStatus{MachineCount: 2, UpdatedMachineCount: 1}},
+				},
+			}
+		})
+
+		It("reports the error", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("Fake error"))
+			result, err := machineryClient.IsWorkerPoolsUpgrading(mockKubeClient)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+
+		It("combines the progress of every non-master pool", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *configPools).Return(nil)
+			result, err := machineryClient.IsWorkerPoolsUpgrading(mockKubeClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.IsUpgrading).To(BeTrue())
+			Expect(result.MachineCount).To(Equal(int32(5)))
+			Expect(result.UpdatedCount).To(Equal(int32(4)))
+			Expect(result.Pools).To(HaveLen(2))
+			Expect(result.Pools[0].Name).To(Equal("infra"))
+			Expect(result.Pools[0].IsUpgrading).To(BeTrue())
+			Expect(result.Pools[1].Name).To(Equal("worker"))
+			Expect(result.Pools[1].IsUpgrading).To(BeFalse())
+		})
+
+		It("reports whether each pool was paused by the operator", func() {
+			configPools.Items[0].Spec.Paused = true
+			configPools.Items[0].Annotations = map[string]string{PausedByAnnotationKey: "true"}
+			configPools.Items[2].Spec.Paused = true
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *configPools).Return(nil)
+			result, err := machineryClient.IsWorkerPoolsUpgrading(mockKubeClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Pools[0].Paused).To(BeTrue())
+			Expect(result.Pools[0].PausedByOperator).To(BeFalse())
+			Expect(result.Pools[1].Paused).To(BeTrue())
+			Expect(result.Pools[1].PausedByOperator).To(BeTrue())
+		})
+	})
+
+	Context("When listing the nodes of the worker pools", func() {
+		var (
+			configPools *machineconfigapi.MachineConfigPoolList
+			nodes       *corev1.NodeList
+		)
+
+		BeforeEach(func() {
+			selector := func(role string) *metav1.LabelSelector {
+				return &metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/" + role: ""}}
+			}
+			configPools = &machineconfigapi.MachineConfigPoolList{
+				Items: []machineconfigapi.MachineConfigPool{
+					{ObjectMeta: metav1.ObjectMeta{Name: "worker"}, Spec: machineconfigapi.MachineConfigPoolSpec{NodeSelector: selector("worker")}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "master"}, Spec: machineconfigapi.MachineConfigPoolSpec{NodeSelector: selector("master")}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "infra"}, Spec: machineconfigapi.MachineConfigPoolSpec{NodeSelector: selector("infra")}},
+				},
+			}
+			nodes = &corev1.NodeList{
+				Items: []corev1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "master-1", Labels: map[string]string{"node-role.kubernetes.io/master": ""}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"node-role.kubernetes.io/worker": ""}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "infra-1", Labels: map[string]string{"node-role.kubernetes.io/worker": "", "node-role.kubernetes.io/infra": ""}}},
+				},
+			}
+		})
+
+		It("assigns each node to the pool selecting it, preferring custom pools to the worker pool", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *configPools).Return(nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodes).Return(nil),
+			)
+			poolNodes, err := WorkerPoolNodes(mockKubeClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poolNodes).To(HaveLen(2))
+			Expect(poolNodes["worker"]).To(HaveLen(1))
+			Expect(poolNodes["worker"][0].Name).To(Equal("worker-1"))
+			Expect(poolNodes["infra"]).To(HaveLen(1))
+			Expect(poolNodes["infra"][0].Name).To(Equal("infra-1"))
+		})
+
+		It("lists the nodes of every worker pool", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *configPools).Return(nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, *nodes).Return(nil),
+			)
+			workers, err := ListWorkerNodes(mockKubeClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(workers.Items).To(HaveLen(2))
+			Expect(workers.Items[0].Name).To(Equal("infra-1"))
+			Expect(workers.Items[1].Name).To(Equal("worker-1"))
+		})
+
+		It("reports the error", func() {
+			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("Fake error"))
+			_, err := ListWorkerNodes(mockKubeClient)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When pausing a MachineConfigPool", func() {
+		var nodeType = "worker"
+
+		It("pauses and annotates an unpaused pool", func() {
+			configPool := machineconfigapi.MachineConfigPool{}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						pool := obj.(*machineconfigapi.MachineConfigPool)
+						Expect(pool.Spec.Paused).To(BeTrue())
+						Expect(pool.Annotations).To(HaveKey(PausedByAnnotationKey))
+						return nil
+					}),
+			)
+			err := machineryClient.PauseMachineConfigPool(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("leaves an already paused pool untouched", func() {
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{Paused: true}}
+			mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil)
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			err := machineryClient.PauseMachineConfigPool(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("unpauses a pool paused by the operator", func() {
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{Paused: true}}
+			configPool.Annotations = map[string]string{PausedByAnnotationKey: "true"}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						pool := obj.(*machineconfigapi.MachineConfigPool)
+						Expect(pool.Spec.Paused).To(BeFalse())
+						Expect(pool.Annotations).NotTo(HaveKey(PausedByAnnotationKey))
+						return nil
+					}),
+			)
+			err := machineryClient.UnpauseMachineConfigPool(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not unpause a pool paused by someone else", func() {
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{Paused: true}}
+			mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil)
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			err := machineryClient.UnpauseMachineConfigPool(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When tuning the maxUnavailable of a MachineConfigPool", func() {
+		var nodeType = "worker"
+
+		It("sets the maxUnavailable and records the original value", func() {
+			original := intstr.FromString("10%")
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{MaxUnavailable: &original}}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						pool := obj.(*machineconfigapi.MachineConfigPool)
+						Expect(*pool.Spec.MaxUnavailable).To(Equal(intstr.FromInt32(3)))
+						Expect(pool.Annotations).To(HaveKeyWithValue(OriginalMaxUnavailableAnnotationKey, "10%"))
+						return nil
+					}),
+			)
+			err := machineryClient.SetMachineConfigPoolMaxUnavailable(mockKubeClient, nodeType, 3)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("keeps the original value recorded when first set", func() {
+			current := intstr.FromInt32(3)
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{MaxUnavailable: &current}}
+			configPool.Annotations = map[string]string{OriginalMaxUnavailableAnnotationKey: ""}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						pool := obj.(*machineconfigapi.MachineConfigPool)
+						Expect(*pool.Spec.MaxUnavailable).To(Equal(intstr.FromInt32(2)))
+						Expect(pool.Annotations).To(HaveKeyWithValue(OriginalMaxUnavailableAnnotationKey, ""))
+						return nil
+					}),
+			)
+			err := machineryClient.SetMachineConfigPoolMaxUnavailable(mockKubeClient, nodeType, 2)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("leaves a pool with the same maxUnavailable untouched", func() {
+			current := intstr.FromInt32(3)
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{MaxUnavailable: &current}}
+			mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil)
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			err := machineryClient.SetMachineConfigPoolMaxUnavailable(mockKubeClient, nodeType, 3)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("restores the original maxUnavailable", func() {
+			current := intstr.FromInt32(3)
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{MaxUnavailable: &current}}
+			configPool.Annotations = map[string]string{OriginalMaxUnavailableAnnotationKey: "10%"}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						pool := obj.(*machineconfigapi.MachineConfigPool)
+						Expect(*pool.Spec.MaxUnavailable).To(Equal(intstr.FromString("10%")))
+						Expect(pool.Annotations).NotTo(HaveKey(OriginalMaxUnavailableAnnotationKey))
+						return nil
+					}),
+			)
+			err := machineryClient.RestoreMachineConfigPoolMaxUnavailable(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("restores an unset maxUnavailable", func() {
+			current := intstr.FromInt32(3)
+			configPool := machineconfigapi.MachineConfigPool{Spec: machineconfigapi.MachineConfigPoolSpec{MaxUnavailable: &current}}
+			configPool.Annotations = map[string]string{OriginalMaxUnavailableAnnotationKey: ""}
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil),
+				mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						pool := obj.(*machineconfigapi.MachineConfigPool)
+						Expect(pool.Spec.MaxUnavailable).To(BeNil())
+						return nil
+					}),
+			)
+			err := machineryClient.RestoreMachineConfigPoolMaxUnavailable(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not restore a maxUnavailable it did not set", func() {
+			configPool := machineconfigapi.MachineConfigPool{}
+			mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: nodeType}, gomock.Any()).SetArg(2, configPool).Return(nil)
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			err := machineryClient.RestoreMachineConfigPoolMaxUnavailable(mockKubeClient, nodeType)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("When assessing if a node is cordoned", func() {
 		It("Reports if the node is draining", func() {
 			testNode := &corev1.Node{
@@ -125,3 +125,74 @@ func (mr *MockMachineryMockRecorder) IsUpgrading(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUpgrading", reflect.TypeOf((*MockMachinery)(nil).IsUpgrading), arg0, arg1)
 }
+
+// IsWorkerPoolsUpgrading mocks base method.
+func (m *MockMachinery) IsWorkerPoolsUpgrading(arg0 client.Client) (*machinery.WorkerPoolsUpgradingResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsWorkerPoolsUpgrading", arg0)
+	ret0, _ := ret[0].(*machinery.WorkerPoolsUpgradingResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsWorkerPoolsUpgrading indicates an expected call of IsWorkerPoolsUpgrading.
+func (mr *MockMachineryMockRecorder) IsWorkerPoolsUpgrading(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsWorkerPoolsUpgrading", reflect.TypeOf((*MockMachinery)(nil).IsWorkerPoolsUpgrading), arg0)
+}
+
+// PauseMachineConfigPool mocks base method.
+func (m *MockMachinery) PauseMachineConfigPool(arg0 client.Client, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseMachineConfigPool", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseMachineConfigPool indicates an expected call of PauseMachineConfigPool.
+func (mr *MockMachineryMockRecorder) PauseMachineConfigPool(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseMachineConfigPool", reflect.TypeOf((*MockMachinery)(nil).PauseMachineConfigPool), arg0, arg1)
+}
+
+// RestoreMachineConfigPoolMaxUnavailable mocks base method.
+func (m *MockMachinery) RestoreMachineConfigPoolMaxUnavailable(arg0 client.Client, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreMachineConfigPoolMaxUnavailable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreMachineConfigPoolMaxUnavailable indicates an expected call of RestoreMachineConfigPoolMaxUnavailable.
+func (mr *MockMachineryMockRecorder) RestoreMachineConfigPoolMaxUnavailable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreMachineConfigPoolMaxUnavailable", reflect.TypeOf((*MockMachinery)(nil).RestoreMachineConfigPoolMaxUnavailable), arg0, arg1)
+}
+
+// SetMachineConfigPoolMaxUnavailable mocks base method.
+func (m *MockMachinery) SetMachineConfigPoolMaxUnavailable(arg0 client.Client, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMachineConfigPoolMaxUnavailable", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMachineConfigPoolMaxUnavailable indicates an expected call of SetMachineConfigPoolMaxUnavailable.
+func (mr *MockMachineryMockRecorder) SetMachineConfigPoolMaxUnavailable(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMachineConfigPoolMaxUnavailable", reflect.TypeOf((*MockMachinery)(nil).SetMachineConfigPoolMaxUnavailable), arg0, arg1, arg2)
+}
+
+// UnpauseMachineConfigPool mocks base method.
+func (m *MockMachinery) UnpauseMachineConfigPool(arg0 client.Client, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpauseMachineConfigPool", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpauseMachineConfigPool indicates an expected call of UnpauseMachineConfigPool.
+func (mr *MockMachineryMockRecorder) UnpauseMachineConfigPool(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpauseMachineConfigPool", reflect.TypeOf((*MockMachinery)(nil).UnpauseMachineConfigPool), arg0, arg1)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"os"
@@ -33,6 +34,10 @@ const (
 	nodeLabel    = "node_name"
 	alertsLabel  = "alerts"
 	failedReason = "reason"
+	stepLabel    = "step"
+	sinkLabel    = "sink"
+	nsLabel      = "namespace"
+	podLabel     = "pod"
 
 	Namespace = "upgradeoperator"
 	Subsystem = "upgrade"
@@ -67,8 +72,16 @@ const (
 	ClusterInvalidPDB                = "cluster_invalid_pdb"
 	ClusterInvalidPDBConf            = "cluster_invalid_pdb_configuration"
 	PDBQueryFailed                   = "pdb_query_failed"
+	ClusterDrainBlockingPDB          = "cluster_drain_blocking_pdb"
+	DrainSimulationFailed            = "drain_simulation_failed"
 	DvoClientCreationFailed          = "dvo_client_creation_failed"
 	DvoMetricsQueryFailed            = "dvo_metrics_query_failed"
+	APIServerErrorRateHigh           = "apiserver_error_rate_high"
+
+	// NodeDrainPodNeverForced is the reason a pod is held back from draining by a drain policy which never forces it
+	NodeDrainPodNeverForced = "never_force"
+	// NodeDrainPodManual is the reason a pod is held back from draining by its manual drain policy annotation
+	NodeDrainPodManual = "manual_drain_policy"
 )
 
 // Alerts sourced from https://github.com/openshift/managed-cluster-config/blob/master/deploy/sre-prometheus/100-managed-upgrade-operator.PrometheusRule.yaml
@@ -91,6 +104,8 @@ type Metrics interface {
 	UpdateMetricUpgradeWindowNotBreached(string)
 	UpdatemetricUpgradeNotificationFailed(string, string)
 	UpdatemetricUpgradeNotificationSucceeded(string, string)
+	UpdateMetricNotificationSinkFailed(string, string)
+	UpdateMetricNotificationSinkSucceeded(string, string)
 	UpdateMetricUpgradeConfigSyncTimestamp(string, time.Time)
 	UpdateMetricUpgradeWindowBreached(string)
 	UpdateMetricUpgradeStartedTimestamp(string, string, string, time.Time)
@@ -104,17 +119,21 @@ type Metrics interface {
 	UpdateMetricHealthcheckFailed(string, string, string, string)
 	UpdateMetricUpgradeWorkerTimeout(string, string)
 	ResetMetricUpgradeWorkerTimeout(string, string)
+	UpdateMetricUpgradeStepTimeout(string, string)
+	ResetAllMetricUpgradeStepTimeout()
 	UpdateMetricNodeDrainFailed(string)
 	ResetMetricNodeDrainFailed(string)
 	ResetAllMetricNodeDrainFailed()
+	UpdateMetricNodeDrainPodsHeld(string, string, []types.NamespacedName)
+	ResetMetricNodeDrainPodsHeld(string)
 	ResetFailureMetrics()
 	ResetEphemeralMetrics()
 	UpdateMetricNotificationEventSent(string, string, string)
 	UpdateMetricUpgradeResult(string, string, string, string, []string)
 	AlertsFromUpgrade(time.Time, time.Time) ([]string, error)
 	IsAlertFiring(alert string, checkedNS, ignoredNS []string) (bool, error)
-	IsMetricNotificationEventSentSet(upgradeConfigName string, event string, version string) (bool, error)
 	IsClusterVersionAtVersion(version string) (bool, error)
+	APIServerErrorRate(window time.Duration) (float64, error)
 	Query(query string) (*AlertResponse, error)
 }
 
@@ -254,11 +273,21 @@ var (
 		Name:      "worker_timeout",
 		Help:      "Worker nodes upgrade timeout",
 	}, []string{nameLabel, VersionLabel})
+	metricUpgradeStepTimeout = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "step_timeout",
+		Help:      "Upgrade step has exceeded its maximum duration",
+	}, []string{nameLabel, stepLabel})
 	metricNodeDrainFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: metricsTag,
 		Name:      "node_drain_timeout",
 		Help:      "Node cannot be drained successfully in time.",
 	}, []string{nodeLabel})
+	metricNodeDrainPodHeld = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "node_drain_pod_held",
+		Help:      "Pod is held back from being drained from the node.",
+	}, []string{nodeLabel, nsLabel, podLabel, failedReason})
 	metricUpgradeNotification = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: metricsTag,
 		Name:      "upgrade_notification",
@@ -273,6 +302,11 @@ var (
 		Name: "upgrade_notification_failed",
 		Help: "Failed to send notification",
 	}, []string{nameLabel, eventLabel})
+	metricNotificationSinkFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "notification_sink_failed",
+		Help:      "Failed to send notification to a secondary notification sink",
+	}, []string{sinkLabel, eventLabel})
 	metricUpgradeConfigSyncTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: metricsTag,
 		Name:      "upgradeconfig_sync_timestamp",
@@ -317,10 +351,13 @@ var (
 		metricUpgradeControlPlaneTimeout,
 		metricHealthcheckFailed,
 		metricUpgradeWorkerTimeout,
+		metricUpgradeStepTimeout,
 		metricNodeDrainFailed,
+		metricNodeDrainPodHeld,
 		metricUpgradeNotification,
 		metricUpgradeConfigSyncTimestamp,
 		metricUpgradeNotificationFailed,
+		metricNotificationSinkFailed,
 		upgradeStartedTimestamp,
 		upgradeCompletedTimestamp,
 		controlplaneUpgradeStartedTimestamp,
@@ -416,6 +453,17 @@ func (c *Counter) ResetMetricUpgradeWorkerTimeout(upgradeConfigName, version str
 		float64(0))
 }
 
+func (c *Counter) UpdateMetricUpgradeStepTimeout(upgradeConfigName, step string) {
+	metricUpgradeStepTimeout.With(prometheus.Labels{
+		stepLabel: step,
+		nameLabel: upgradeConfigName}).Set(
+		float64(1))
+}
+
+func (c *Counter) ResetAllMetricUpgradeStepTimeout() {
+	metricUpgradeStepTimeout.Reset()
+}
+
 func (c *Counter) UpdateMetricNodeDrainFailed(nodeName string) {
 	metricNodeDrainFailed.With(prometheus.Labels{
 		nodeLabel: nodeName}).Set(
@@ -432,6 +480,27 @@ func (c *Counter) ResetAllMetricNodeDrainFailed() {
 	metricNodeDrainFailed.Reset()
 }
 
+// UpdateMetricNodeDrainPodsHeld sets the pods on the node which are held back from draining for
+// the given reason, replacing those previously set for it
+func (c *Counter) UpdateMetricNodeDrainPodsHeld(nodeName string, reason string, pods []types.NamespacedName) {
+	metricNodeDrainPodHeld.DeletePartialMatch(prometheus.Labels{
+		nodeLabel:    nodeName,
+		failedReason: reason})
+	for _, p := range pods {
+		metricNodeDrainPodHeld.With(prometheus.Labels{
+			nodeLabel:    nodeName,
+			nsLabel:      p.Namespace,
+			podLabel:     p.Name,
+			failedReason: reason}).Set(
+			float64(1))
+	}
+}
+
+func (c *Counter) ResetMetricNodeDrainPodsHeld(nodeName string) {
+	metricNodeDrainPodHeld.DeletePartialMatch(prometheus.Labels{
+		nodeLabel: nodeName})
+}
+
 func (c *Counter) UpdateMetricUpgradeWindowNotBreached(upgradeConfigName string) {
 	metricUpgradeWindowBreached.With(prometheus.Labels{
 		nameLabel: upgradeConfigName}).Set(
@@ -466,6 +535,20 @@ func (c *Counter) UpdatemetricUpgradeNotificationSucceeded(upgradeConfigName str
 		float64(0))
 }
 
+func (c *Counter) UpdateMetricNotificationSinkFailed(sink string, event string) {
+	metricNotificationSinkFailed.With(prometheus.Labels{
+		sinkLabel:  sink,
+		eventLabel: event}).Set(
+		float64(1))
+}
+
+func (c *Counter) UpdateMetricNotificationSinkSucceeded(sink string, event string) {
+	metricNotificationSinkFailed.With(prometheus.Labels{
+		sinkLabel:  sink,
+		eventLabel: event}).Set(
+		float64(0))
+}
+
 func (c *Counter) UpdateMetricUpgradeStartedTimestamp(cluster_id string, name string, version string, time time.Time) {
 	upgradeStartedTimestamp.With(prometheus.Labels{
 		ClusterIdLabel: cluster_id,
@@ -543,7 +626,9 @@ func (c *Counter) ResetFailureMetrics() {
 		metricUpgradeControlPlaneTimeout,
 		metricHealthcheckFailed,
 		metricUpgradeWorkerTimeout,
+		metricUpgradeStepTimeout,
 		metricNodeDrainFailed,
+		metricNodeDrainPodHeld,
 		metricUpgradeNotification,
 		metricUpgradeNotificationFailed,
 		upgradeStartedTimestamp,
@@ -553,8 +638,8 @@ func (c *Counter) ResetFailureMetrics() {
 	}
 }
 
-func (c *Counter) IsMetricNotificationEventSentSet(upgradeConfigName string, event string, version string) (bool, error) {
-	cpMetrics, err := c.Query(fmt.Sprintf("%s_upgrade_notification{%s=\"%s\",%s=\"%s\",%s=\"%s\"}", metricsTag, nameLabel, upgradeConfigName, eventLabel, event, VersionLabel, version))
+func (c *Counter) IsClusterVersionAtVersion(version string) (bool, error) {
+	cpMetrics, err := c.Query(fmt.Sprintf("cluster_version{version=\"%s\",type=\"current\"}", version))
 	if err != nil {
 		return false, err
 	}
@@ -566,17 +651,29 @@ func (c *Counter) IsMetricNotificationEventSentSet(upgradeConfigName string, eve
 	return false, nil
 }
 
-func (c *Counter) IsClusterVersionAtVersion(version string) (bool, error) {
-	cpMetrics, err := c.Query(fmt.Sprintf("cluster_version{version=\"%s\",type=\"current\"}", version))
+// APIServerErrorRate reports the ratio of API server requests which failed with a
+// server error over the given window, out of all API server requests
+func (c *Counter) APIServerErrorRate(window time.Duration) (float64, error) {
+	cpMetrics, err := c.Query(fmt.Sprintf(`sum(rate(apiserver_request_total{code=~"5.."}[%[1]ds])) / sum(rate(apiserver_request_total[%[1]ds]))`, int(window.Seconds())))
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
-	if len(cpMetrics.Data.Result) > 0 {
-		return true, nil
+	if len(cpMetrics.Data.Result) == 0 || len(cpMetrics.Data.Result[0].Value) < 2 {
+		return 0, nil
 	}
-
-	return false, nil
+	value, ok := cpMetrics.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected API server error rate value %v", cpMetrics.Data.Result[0].Value[1])
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse API server error rate: %s", err)
+	}
+	if math.IsNaN(rate) {
+		return 0, nil
+	}
+	return rate, nil
 }
 
 // AlertsFromUpgrade reports any primary-paging critical alerts that were fired from managed-upgrade-operator during the last upgrade.
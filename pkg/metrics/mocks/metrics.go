@@ -15,6 +15,7 @@ import (
 
 	metrics "github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	gomock "go.uber.org/mock/gomock"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // MockMetrics is a mock of Metrics interface.
@@ -40,6 +41,21 @@ func (m *MockMetrics) EXPECT() *MockMetricsMockRecorder {
 	return m.recorder
 }
 
+// APIServerErrorRate mocks base method.
+func (m *MockMetrics) APIServerErrorRate(arg0 time.Duration) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIServerErrorRate", arg0)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// APIServerErrorRate indicates an expected call of APIServerErrorRate.
+func (mr *MockMetricsMockRecorder) APIServerErrorRate(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIServerErrorRate", reflect.TypeOf((*MockMetrics)(nil).APIServerErrorRate), arg0)
+}
+
 // AlertsFromUpgrade mocks base method.
 func (m *MockMetrics) AlertsFromUpgrade(arg0, arg1 time.Time) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -85,21 +101,6 @@ func (mr *MockMetricsMockRecorder) IsClusterVersionAtVersion(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsClusterVersionAtVersion", reflect.TypeOf((*MockMetrics)(nil).IsClusterVersionAtVersion), arg0)
 }
 
-// IsMetricNotificationEventSentSet mocks base method.
-func (m *MockMetrics) IsMetricNotificationEventSentSet(arg0, arg1, arg2 string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsMetricNotificationEventSentSet", arg0, arg1, arg2)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// IsMetricNotificationEventSentSet indicates an expected call of IsMetricNotificationEventSentSet.
-func (mr *MockMetricsMockRecorder) IsMetricNotificationEventSentSet(arg0, arg1, arg2 any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMetricNotificationEventSentSet", reflect.TypeOf((*MockMetrics)(nil).IsMetricNotificationEventSentSet), arg0, arg1, arg2)
-}
-
 // Query mocks base method.
 func (m *MockMetrics) Query(arg0 string) (*metrics.AlertResponse, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +128,18 @@ func (mr *MockMetricsMockRecorder) ResetAllMetricNodeDrainFailed() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAllMetricNodeDrainFailed", reflect.TypeOf((*MockMetrics)(nil).ResetAllMetricNodeDrainFailed))
 }
 
+// ResetAllMetricUpgradeStepTimeout mocks base method.
+func (m *MockMetrics) ResetAllMetricUpgradeStepTimeout() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetAllMetricUpgradeStepTimeout")
+}
+
+// ResetAllMetricUpgradeStepTimeout indicates an expected call of ResetAllMetricUpgradeStepTimeout.
+func (mr *MockMetricsMockRecorder) ResetAllMetricUpgradeStepTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAllMetricUpgradeStepTimeout", reflect.TypeOf((*MockMetrics)(nil).ResetAllMetricUpgradeStepTimeout))
+}
+
 // ResetEphemeralMetrics mocks base method.
 func (m *MockMetrics) ResetEphemeralMetrics() {
 	m.ctrl.T.Helper()
@@ -163,6 +176,18 @@ func (mr *MockMetricsMockRecorder) ResetMetricNodeDrainFailed(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMetricNodeDrainFailed", reflect.TypeOf((*MockMetrics)(nil).ResetMetricNodeDrainFailed), arg0)
 }
 
+// ResetMetricNodeDrainPodsHeld mocks base method.
+func (m *MockMetrics) ResetMetricNodeDrainPodsHeld(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResetMetricNodeDrainPodsHeld", arg0)
+}
+
+// ResetMetricNodeDrainPodsHeld indicates an expected call of ResetMetricNodeDrainPodsHeld.
+func (mr *MockMetricsMockRecorder) ResetMetricNodeDrainPodsHeld(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMetricNodeDrainPodsHeld", reflect.TypeOf((*MockMetrics)(nil).ResetMetricNodeDrainPodsHeld), arg0)
+}
+
 // ResetMetricUpgradeControlPlaneTimeout mocks base method.
 func (m *MockMetrics) ResetMetricUpgradeControlPlaneTimeout(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -247,6 +272,18 @@ func (mr *MockMetricsMockRecorder) UpdateMetricNodeDrainFailed(arg0 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricNodeDrainFailed", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricNodeDrainFailed), arg0)
 }
 
+// UpdateMetricNodeDrainPodsHeld mocks base method.
+func (m *MockMetrics) UpdateMetricNodeDrainPodsHeld(arg0, arg1 string, arg2 []types.NamespacedName) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateMetricNodeDrainPodsHeld", arg0, arg1, arg2)
+}
+
+// UpdateMetricNodeDrainPodsHeld indicates an expected call of UpdateMetricNodeDrainPodsHeld.
+func (mr *MockMetricsMockRecorder) UpdateMetricNodeDrainPodsHeld(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricNodeDrainPodsHeld", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricNodeDrainPodsHeld), arg0, arg1, arg2)
+}
+
 // UpdateMetricNotificationEventSent mocks base method.
 func (m *MockMetrics) UpdateMetricNotificationEventSent(arg0, arg1, arg2 string) {
 	m.ctrl.T.Helper()
@@ -259,6 +296,30 @@ func (mr *MockMetricsMockRecorder) UpdateMetricNotificationEventSent(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricNotificationEventSent", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricNotificationEventSent), arg0, arg1, arg2)
 }
 
+// UpdateMetricNotificationSinkFailed mocks base method.
+func (m *MockMetrics) UpdateMetricNotificationSinkFailed(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateMetricNotificationSinkFailed", arg0, arg1)
+}
+
+// UpdateMetricNotificationSinkFailed indicates an expected call of UpdateMetricNotificationSinkFailed.
+func (mr *MockMetricsMockRecorder) UpdateMetricNotificationSinkFailed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricNotificationSinkFailed", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricNotificationSinkFailed), arg0, arg1)
+}
+
+// UpdateMetricNotificationSinkSucceeded mocks base method.
+func (m *MockMetrics) UpdateMetricNotificationSinkSucceeded(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateMetricNotificationSinkSucceeded", arg0, arg1)
+}
+
+// UpdateMetricNotificationSinkSucceeded indicates an expected call of UpdateMetricNotificationSinkSucceeded.
+func (mr *MockMetricsMockRecorder) UpdateMetricNotificationSinkSucceeded(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricNotificationSinkSucceeded", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricNotificationSinkSucceeded), arg0, arg1)
+}
+
 // UpdateMetricScalingFailed mocks base method.
 func (m *MockMetrics) UpdateMetricScalingFailed(arg0 string) {
 	m.ctrl.T.Helper()
@@ -343,6 +404,18 @@ func (mr *MockMetricsMockRecorder) UpdateMetricUpgradeStartedTimestamp(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricUpgradeStartedTimestamp", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricUpgradeStartedTimestamp), arg0, arg1, arg2, arg3)
 }
 
+// UpdateMetricUpgradeStepTimeout mocks base method.
+func (m *MockMetrics) UpdateMetricUpgradeStepTimeout(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateMetricUpgradeStepTimeout", arg0, arg1)
+}
+
+// UpdateMetricUpgradeStepTimeout indicates an expected call of UpdateMetricUpgradeStepTimeout.
+func (mr *MockMetricsMockRecorder) UpdateMetricUpgradeStepTimeout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetricUpgradeStepTimeout", reflect.TypeOf((*MockMetrics)(nil).UpdateMetricUpgradeStepTimeout), arg0, arg1)
+}
+
 // UpdateMetricUpgradeWindowBreached mocks base method.
 func (m *MockMetrics) UpdateMetricUpgradeWindowBreached(arg0 string) {
 	m.ctrl.T.Helper()
@@ -1,12 +1,19 @@
 package notifier
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 const (
 	// OCM denotes OCM as the config manager source
 	OCM ConfigManagerSource = "OCM"
 	// LOCAL denotes a local config manager source
 	LOCAL ConfigManagerSource = "LOCAL"
+	// WEBHOOK denotes a webhook notifier source
+	WEBHOOK ConfigManagerSource = "WEBHOOK"
+	// EVENTS denotes a Kubernetes Events notifier source
+	EVENTS ConfigManagerSource = "EVENTS"
 )
 
 // ConfigManagerSource is a type that denotes the source of configuration management
@@ -15,6 +22,7 @@ type ConfigManagerSource string
 // NotifierConfig is a type that provides a NotifierConfig
 type NotifierConfig struct {
 	ConfigManager NotifierConfigManager `yaml:"configManager"`
+	Notifier      NotifierSource        `yaml:"notifier"`
 }
 
 // NotifierConfigManager is a type that provides a notifier source
@@ -22,19 +30,103 @@ type NotifierConfigManager struct {
 	Source string `yaml:"source"`
 }
 
+// NotifierSource is a type that selects the notifier independently of the config manager source, the
+// secondary sinks every notified state is also sent to, and the templates of the notification messages
+type NotifierSource struct {
+	Source string               `yaml:"source"`
+	Sinks  []NotifierSinkConfig `yaml:"sinks"`
+	// Locale selects the configured messages of the locale
+	Locale string `yaml:"locale"`
+	// Messages overrides the templates of the notification messages, keyed by locale and message
+	Messages map[string]map[string]string `yaml:"messages"`
+}
+
+// NotifierSinkConfig describes a secondary sink notified states are sent to
+type NotifierSinkConfig struct {
+	// Name identifies the sink in logs and metrics
+	Name string `yaml:"name"`
+	// Source is the notifier of the sink
+	Source string `yaml:"source"`
+	// States restricts the states sent to the sink, every state is sent if empty
+	States []string `yaml:"states"`
+	// Webhook configures the sink of a WEBHOOK source
+	Webhook WebhookConfig `yaml:"webhook"`
+}
+
 // IsValid returns no error if the notifier config is valid
 func (cfg *NotifierConfig) IsValid() error {
 	// the source can be missing. if it's not empty, validate it is a supported value
-	if cfg.ConfigManager.Source == "" {
-		return nil
+	if cfg.ConfigManager.Source != "" {
+		switch strings.ToUpper(cfg.ConfigManager.Source) {
+		case string(OCM):
+		case string(LOCAL):
+		default:
+			return ErrNoNotifierConfigured
+		}
 	}
 
-	switch strings.ToUpper(cfg.ConfigManager.Source) {
-	case string(OCM):
-		return nil
-	case string(LOCAL):
-		return nil
-	default:
+	if cfg.Notifier.Source != "" && !isNotifierSource(cfg.Notifier.Source) {
 		return ErrNoNotifierConfigured
 	}
+
+	names := map[string]bool{}
+	for i := range cfg.Notifier.Sinks {
+		sink := &cfg.Notifier.Sinks[i]
+		if err := sink.IsValid(); err != nil {
+			return fmt.Errorf("config notifier sinks is invalid: %v", err)
+		}
+		if names[sink.Name] {
+			return fmt.Errorf("config notifier sinks is invalid: sink %s is defined more than once", sink.Name)
+		}
+		names[sink.Name] = true
+	}
+
+	if err := validateMessages(cfg.Notifier.Messages); err != nil {
+		return fmt.Errorf("config notifier messages is invalid: %v", err)
+	}
+	return nil
+}
+
+// GetSource returns the source of the notifier, which is the config manager source unless a notifier
+// source is configured
+func (cfg *NotifierConfig) GetSource() string {
+	if cfg.Notifier.Source != "" {
+		return strings.ToUpper(cfg.Notifier.Source)
+	}
+	return strings.ToUpper(cfg.ConfigManager.Source)
+}
+
+// GetMessages returns the notification messages of the configured locale
+func (cfg *NotifierConfig) GetMessages() (*Messages, error) {
+	return NewMessages(cfg.Notifier.Locale, cfg.Notifier.Messages)
+}
+
+// IsValid returns no error if the sink has a name, a supported source and only known states
+func (sink *NotifierSinkConfig) IsValid() error {
+	if sink.Name == "" {
+		return fmt.Errorf("sink name must be set")
+	}
+	if !isNotifierSource(sink.Source) {
+		return fmt.Errorf("sink %s source %q is not supported", sink.Name, sink.Source)
+	}
+	for _, state := range sink.States {
+		if !isMuoState(MuoState(state)) {
+			return fmt.Errorf("sink %s state %q is not a known state", sink.Name, state)
+		}
+	}
+	if strings.ToUpper(sink.Source) == string(WEBHOOK) {
+		if err := sink.Webhook.IsValid(); err != nil {
+			return fmt.Errorf("sink %s webhook is invalid: %v", sink.Name, err)
+		}
+	}
+	return nil
+}
+
+// isNotifierSource returns true if the source names a notifier
+func isNotifierSource(source string) bool {
+	switch ConfigManagerSource(strings.ToUpper(source)) {
+	case OCM, LOCAL, WEBHOOK, EVENTS:
+		return true
+	}
+	return false
 }
@@ -59,6 +59,52 @@ var _ = Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(Equal(ErrNoNotifierConfigured))
 		})
+
+		// Return Source value as WEBHOOK
+		It("Notifier source value = webhook", func() {
+			notifierConfig.ConfigManager.Source = "LOCAL"
+			notifierConfig.Notifier.Source = "webhook"
+			err := notifierConfig.IsValid()
+			Expect(err).To(BeNil())
+			Expect(notifierConfig.GetSource()).To(Equal(string(WEBHOOK)))
+		})
+
+		// Fall back to the config manager source
+		It("Notifier source value is nil", func() {
+			notifierConfig.ConfigManager.Source = "ocm"
+			Expect(notifierConfig.GetSource()).To(Equal(ExpectOcm))
+		})
+
+		// Sinks with a valid source and states
+		It("Notifier sinks are valid", func() {
+			notifierConfig.Notifier.Sinks = []NotifierSinkConfig{
+				{Name: "events", Source: "events", States: []string{string(MuoStateFailed)}},
+				{Name: "webhook", Source: "WEBHOOK", Webhook: WebhookConfig{URL: "https://hooks.example.com"}},
+			}
+			Expect(notifierConfig.IsValid()).To(Succeed())
+		})
+
+		// Error with notifier sink configuration
+		It("Notifier sinks are invalid", func() {
+			for _, sink := range []NotifierSinkConfig{
+				{Source: "EVENTS"},
+				{Name: "unknown", Source: "ERROR"},
+				{Name: "state", Source: "EVENTS", States: []string{"StateUnknown"}},
+				{Name: "webhook", Source: "WEBHOOK"},
+			} {
+				notifierConfig.Notifier.Sinks = []NotifierSinkConfig{sink}
+				Expect(notifierConfig.IsValid()).NotTo(Succeed())
+			}
+			notifierConfig.Notifier.Sinks = []NotifierSinkConfig{{Name: "events", Source: "EVENTS"}, {Name: "events", Source: "LOCAL"}}
+			Expect(notifierConfig.IsValid()).NotTo(Succeed())
+		})
+
+		// Error with notifier configuration
+		It("No valid configured notifier source", func() {
+			notifierConfig.Notifier.Source = "ERROR"
+			err := notifierConfig.IsValid()
+			Expect(err).To(Equal(ErrNoNotifierConfigured))
+		})
 	})
 
 })
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
+)
+
+// UpgradeNotificationReason is the reason of the Events recorded by the EVENTS notifier
+const UpgradeNotificationReason = "UpgradeNotification"
+
+// warningStates are the states recorded as Warning Events, every other state is recorded as a Normal Event
+var warningStates = map[MuoState]bool{
+	MuoStateDelayed:             true,
+	MuoStateFailed:              true,
+	MuoStateCancelled:           true,
+	MuoStateScaleSkipped:        true,
+	MuoStateSkipped:             true,
+	MuoStateHealthCheckSL:       true,
+	MuoStatePreHealthCheckSL:    true,
+	MuoStateCanaryHealthCheckSL: true,
+}
+
+// NewEventNotifier returns a new eventNotifier
+func NewEventNotifier(recorder record.EventRecorder, upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager) (*eventNotifier, error) {
+	if recorder == nil {
+		return nil, fmt.Errorf("no event recorder to record notifications with")
+	}
+	return &eventNotifier{
+		recorder:             recorder,
+		upgradeConfigManager: upgradeConfigManager,
+	}, nil
+}
+
+// A notifier that records every state as a Kubernetes Event on the UpgradeConfig
+type eventNotifier struct {
+	// Records the Events
+	recorder record.EventRecorder
+	// Retrieves the upgrade config from the cluster
+	upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager
+}
+
+func (s *eventNotifier) NotifyState(state MuoState, description string) error {
+	uc, err := s.upgradeConfigManager.Get()
+	if err != nil {
+		return fmt.Errorf("can't read upgradeconfig to record notification on: %v", err)
+	}
+	eventType := corev1.EventTypeNormal
+	if warningStates[state] {
+		eventType = corev1.EventTypeWarning
+	}
+	s.recorder.Event(uc, eventType, UpgradeNotificationReason, fmt.Sprintf("Upgrade-State: %s Description: %s", state, description))
+	return nil
+}
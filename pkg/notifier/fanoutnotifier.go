@@ -0,0 +1,178 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
+)
+
+const (
+	// maxSinkDeliveryAttempts is the number of times a notification is sent to a secondary sink before
+	// its failed delivery is no longer retried
+	maxSinkDeliveryAttempts = 10
+	// sinkRetryInterval is the time after the first failed delivery to a secondary sink before it is
+	// retried. The interval doubles with each further failed attempt, up to maxSinkRetryInterval.
+	sinkRetryInterval    = time.Minute
+	maxSinkRetryInterval = time.Hour
+)
+
+// SinkRetrier is implemented by notifiers which retry the failed deliveries of notifications to their
+// secondary sinks
+type SinkRetrier interface {
+	RetrySinks(uc *upgradev1alpha1.UpgradeConfig)
+}
+
+// NewFanOutNotifier returns a notifier sending every state to the primary notifier and then to each
+// secondary sink accepting the state
+func NewFanOutNotifier(primary Notifier, sinks []*notifierSink, metricsClient metrics.Metrics, ledger Ledger, upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager) *fanOutNotifier {
+	return &fanOutNotifier{
+		primary:              primary,
+		sinks:                sinks,
+		metrics:              metricsClient,
+		ledger:               ledger,
+		upgradeConfigManager: upgradeConfigManager,
+	}
+}
+
+// A notifier that delivers each state to a primary notifier and several secondary sinks.
+// Only the primary notifier decides whether a state was notified: the failures of the secondary
+// sinks are logged and recorded in the upgradeoperator_notification_sink_failed metric, but are not
+// returned. A state the primary notifier fails to notify is not sent to the sinks, so that the
+// sinks receive it once, when its notification is retried.
+// The states are sent to the sinks in the background, so that a slow sink does not hold up the
+// reconcile. Each delivery to a sink is recorded in the ledger, and failed deliveries are retried
+// with an exponential backoff when RetrySinks is called.
+type fanOutNotifier struct {
+	// Notifier whose result is returned
+	primary Notifier
+	// Secondary sinks
+	sinks []*notifierSink
+	// Records the failures of the sinks
+	metrics metrics.Metrics
+	// Records the deliveries to the sinks
+	ledger Ledger
+	// Retrieves the upgrade config from the cluster
+	upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager
+	// Tracks the deliveries to the sinks started by the notifier
+	deliveries sync.WaitGroup
+	// Holds the ledger keys of the notifications being sent to the sinks
+	inFlight sync.Map
+}
+
+// notifierSink is a secondary sink of the fan-out notifier
+type notifierSink struct {
+	// Name identifying the sink
+	name string
+	// Notifier of the sink
+	notifier Notifier
+	// States sent to the sink, every state if empty
+	states map[MuoState]bool
+}
+
+// newNotifierSink returns a sink sending the states to the notifier, or every state if none are given
+func newNotifierSink(name string, n Notifier, states []string) *notifierSink {
+	sink := &notifierSink{name: name, notifier: n}
+	if len(states) > 0 {
+		sink.states = make(map[MuoState]bool, len(states))
+		for _, state := range states {
+			sink.states[MuoState(state)] = true
+		}
+	}
+	return sink
+}
+
+// accepts returns true if the state is sent to the sink
+func (sink *notifierSink) accepts(state MuoState) bool {
+	return sink.states == nil || sink.states[state]
+}
+
+func (s *fanOutNotifier) NotifyState(state MuoState, description string) error {
+	err := s.primary.NotifyState(state, description)
+	if err != nil {
+		return err
+	}
+	// A delivery which can't be recorded is sent regardless, it is just not retried
+	uc, err := s.upgradeConfigManager.Get()
+	if err != nil {
+		log.Error(err, fmt.Sprintf("can't read upgradeconfig to record the delivery of notification '%s' to the sinks", state))
+		uc = nil
+	}
+	for _, sink := range s.sinks {
+		if sink.accepts(state) {
+			s.deliver(uc, sink, state, description)
+		}
+	}
+	return nil
+}
+
+// RetrySinks sends the notifications of the upgrade which failed to be sent to a sink again, once
+// their retry interval has passed, until they have been attempted maxSinkDeliveryAttempts times
+func (s *fanOutNotifier) RetrySinks(uc *upgradev1alpha1.UpgradeConfig) {
+	failed, err := s.ledger.FailedSinkDeliveries(uc)
+	if err != nil {
+		log.Error(err, "can't read the failed notification sink deliveries from the notification ledger")
+		return
+	}
+	now := time.Now()
+	for _, entry := range failed {
+		if entry.Attempts >= maxSinkDeliveryAttempts || now.Before(entry.Timestamp.Add(sinkRetryBackoff(entry.Attempts))) {
+			continue
+		}
+		for _, sink := range s.sinks {
+			if sink.name == entry.Sink && sink.accepts(entry.State) {
+				log.Info(fmt.Sprintf("Retrying notification '%s' to sink %s after %d failed attempts", entry.State, sink.name, entry.Attempts))
+				s.deliver(uc, sink, entry.State, entry.Description)
+			}
+		}
+	}
+}
+
+// sinkRetryBackoff returns the time to wait after the last of the failed attempts to deliver a
+// notification to a sink before it is retried
+func sinkRetryBackoff(attempts int) time.Duration {
+	backoff := sinkRetryInterval
+	for i := 1; i < attempts && backoff < maxSinkRetryInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > maxSinkRetryInterval {
+		return maxSinkRetryInterval
+	}
+	return backoff
+}
+
+// deliver sends the state to the sink in the background and records the result, unless the state is
+// already being sent to the sink
+func (s *fanOutNotifier) deliver(uc *upgradev1alpha1.UpgradeConfig, sink *notifierSink, state MuoState, description string) {
+	key := sink.name + "/" + string(state)
+	if uc != nil {
+		key = sinkLedgerKey(uc.Spec.Desired.Version, state, sink.name)
+	}
+	if _, inFlight := s.inFlight.LoadOrStore(key, true); inFlight {
+		return
+	}
+	s.deliveries.Add(1)
+	go func() {
+		defer s.deliveries.Done()
+		defer s.inFlight.Delete(key)
+
+		sendErr := sink.notifier.NotifyState(state, description)
+		if sendErr != nil {
+			log.Error(sendErr, fmt.Sprintf("failed to send notification '%s' to sink %s", state, sink.name))
+			if s.metrics != nil {
+				s.metrics.UpdateMetricNotificationSinkFailed(sink.name, string(state))
+			}
+		} else if s.metrics != nil {
+			s.metrics.UpdateMetricNotificationSinkSucceeded(sink.name, string(state))
+		}
+		if uc == nil || s.ledger == nil {
+			return
+		}
+		if err := s.ledger.RecordSink(uc, state, sink.name, description, sendErr); err != nil {
+			log.Error(err, fmt.Sprintf("failed to record notification '%s' to sink %s in the notification ledger", state, sink.name))
+		}
+	}()
+}
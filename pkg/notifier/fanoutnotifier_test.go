@@ -0,0 +1,205 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	metricsMocks "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	ucMocks "github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager/mocks"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+// fakeNotifier records the states it is notified of, failing with its error
+type fakeNotifier struct {
+	states []MuoState
+	err    error
+	mutex  sync.Mutex
+}
+
+func (f *fakeNotifier) NotifyState(state MuoState, description string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.states = append(f.states, state)
+	return f.err
+}
+
+// notified returns the states the notifier was notified of
+func (f *fakeNotifier) notified() []MuoState {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]MuoState{}, f.states...)
+}
+
+var _ = Describe("Fan-out Notifier", func() {
+	var (
+		mockCtrl                 *gomock.Controller
+		primary                  *fakeNotifier
+		webhook                  *fakeNotifier
+		events                   *fakeNotifier
+		ledger                   *fakeLedger
+		mockMetrics              *metricsMocks.MockMetrics
+		mockUpgradeConfigManager *ucMocks.MockUpgradeConfigManager
+		upgradeConfig            *upgradev1alpha1.UpgradeConfig
+		fanOutNotify             *fanOutNotifier
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		primary = &fakeNotifier{}
+		webhook = &fakeNotifier{}
+		events = &fakeNotifier{}
+		ledger = &fakeLedger{}
+		mockMetrics = metricsMocks.NewMockMetrics(mockCtrl)
+		mockUpgradeConfigManager = ucMocks.NewMockUpgradeConfigManager(mockCtrl)
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().GetUpgradeConfig()
+		fanOutNotify = NewFanOutNotifier(primary, []*notifierSink{
+			newNotifierSink("webhook", webhook, []string{string(MuoStateFailed)}),
+			newNotifierSink("events", events, nil),
+		}, mockMetrics, ledger, mockUpgradeConfigManager)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("sends the state to the primary notifier and the sinks accepting it", func() {
+		mockUpgradeConfigManager.EXPECT().Get().Return(upgradeConfig, nil)
+		mockMetrics.EXPECT().UpdateMetricNotificationSinkSucceeded("events", string(MuoStateStarted))
+		Expect(fanOutNotify.NotifyState(MuoStateStarted, description)).To(Succeed())
+		fanOutNotify.deliveries.Wait()
+		Expect(primary.notified()).To(Equal([]MuoState{MuoStateStarted}))
+		Expect(webhook.notified()).To(BeEmpty())
+		Expect(events.notified()).To(Equal([]MuoState{MuoStateStarted}))
+		Expect(ledger.sinks).To(HaveLen(1))
+		Expect(ledger.sinks[0].Sink).To(Equal("events"))
+		Expect(ledger.sinks[0].Result).To(Equal(DeliverySent))
+	})
+
+	It("does not fail when a sink fails, and records its failure in the ledger", func() {
+		webhook.err = fmt.Errorf("fake error")
+		mockUpgradeConfigManager.EXPECT().Get().Return(upgradeConfig, nil)
+		mockMetrics.EXPECT().UpdateMetricNotificationSinkFailed("webhook", string(MuoStateFailed))
+		mockMetrics.EXPECT().UpdateMetricNotificationSinkSucceeded("events", string(MuoStateFailed))
+		Expect(fanOutNotify.NotifyState(MuoStateFailed, description)).To(Succeed())
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(Equal([]MuoState{MuoStateFailed}))
+		Expect(events.notified()).To(Equal([]MuoState{MuoStateFailed}))
+		failed, err := ledger.FailedSinkDeliveries(upgradeConfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failed).To(HaveLen(1))
+		Expect(failed[0].Sink).To(Equal("webhook"))
+		Expect(failed[0].Description).To(Equal(description))
+	})
+
+	It("does not wait for the sinks to be notified", func() {
+		blocked := &blockingNotifier{release: make(chan struct{})}
+		fanOutNotify.sinks = []*notifierSink{newNotifierSink("blocked", blocked, nil)}
+		mockUpgradeConfigManager.EXPECT().Get().Return(upgradeConfig, nil)
+		mockMetrics.EXPECT().UpdateMetricNotificationSinkSucceeded("blocked", string(MuoStateStarted))
+		Expect(fanOutNotify.NotifyState(MuoStateStarted, description)).To(Succeed())
+		close(blocked.release)
+		fanOutNotify.deliveries.Wait()
+	})
+
+	It("fails without notifying the sinks when the primary notifier fails", func() {
+		primary.err = fmt.Errorf("fake error")
+		Expect(fanOutNotify.NotifyState(MuoStateFailed, description)).NotTo(Succeed())
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(BeEmpty())
+		Expect(events.notified()).To(BeEmpty())
+	})
+
+	It("retries the failed deliveries to the sinks", func() {
+		Expect(ledger.RecordSink(upgradeConfig, MuoStateFailed, "webhook", description, fmt.Errorf("fake error"))).To(Succeed())
+		ledger.sinks[0].Timestamp = metav1.NewTime(time.Now().Add(-sinkRetryInterval))
+		mockMetrics.EXPECT().UpdateMetricNotificationSinkSucceeded("webhook", string(MuoStateFailed))
+		fanOutNotify.RetrySinks(upgradeConfig)
+		fanOutNotify.deliveries.Wait()
+		Expect(primary.notified()).To(BeEmpty())
+		Expect(webhook.notified()).To(Equal([]MuoState{MuoStateFailed}))
+		Expect(ledger.sinks[0].Result).To(Equal(DeliverySent))
+	})
+
+	It("waits longer before retrying a delivery after each failed attempt", func() {
+		for i := 0; i < 3; i++ {
+			Expect(ledger.RecordSink(upgradeConfig, MuoStateFailed, "webhook", description, fmt.Errorf("fake error"))).To(Succeed())
+		}
+		fanOutNotify.RetrySinks(upgradeConfig)
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(BeEmpty())
+
+		ledger.sinks[0].Timestamp = metav1.NewTime(time.Now().Add(-2 * sinkRetryInterval))
+		fanOutNotify.RetrySinks(upgradeConfig)
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(BeEmpty())
+
+		ledger.sinks[0].Timestamp = metav1.NewTime(time.Now().Add(-4 * sinkRetryInterval))
+		mockMetrics.EXPECT().UpdateMetricNotificationSinkSucceeded("webhook", string(MuoStateFailed))
+		fanOutNotify.RetrySinks(upgradeConfig)
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(Equal([]MuoState{MuoStateFailed}))
+	})
+
+	It("backs off the retries of a delivery up to a maximum interval", func() {
+		Expect(sinkRetryBackoff(1)).To(Equal(sinkRetryInterval))
+		Expect(sinkRetryBackoff(2)).To(Equal(2 * sinkRetryInterval))
+		Expect(sinkRetryBackoff(maxSinkDeliveryAttempts)).To(Equal(maxSinkRetryInterval))
+	})
+
+	It("stops retrying a delivery to a sink once it has been attempted too many times", func() {
+		for i := 0; i < maxSinkDeliveryAttempts; i++ {
+			Expect(ledger.RecordSink(upgradeConfig, MuoStateFailed, "webhook", description, fmt.Errorf("fake error"))).To(Succeed())
+		}
+		ledger.sinks[0].Timestamp = metav1.NewTime(time.Now().Add(-maxSinkRetryInterval))
+		fanOutNotify.RetrySinks(upgradeConfig)
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(BeEmpty())
+	})
+
+	It("does not retry the deliveries to sinks which are no longer configured", func() {
+		Expect(ledger.RecordSink(upgradeConfig, MuoStateFailed, "removed", description, fmt.Errorf("fake error"))).To(Succeed())
+		ledger.sinks[0].Timestamp = metav1.NewTime(time.Now().Add(-sinkRetryInterval))
+		fanOutNotify.RetrySinks(upgradeConfig)
+		fanOutNotify.deliveries.Wait()
+		Expect(webhook.notified()).To(BeEmpty())
+		Expect(events.notified()).To(BeEmpty())
+	})
+
+	Context("Event notifier", func() {
+		It("records the state as an Event on the UpgradeConfig", func() {
+			recorder := record.NewFakeRecorder(10)
+			mockUpgradeConfigManager := ucMocks.NewMockUpgradeConfigManager(mockCtrl)
+			mockUpgradeConfigManager.EXPECT().Get().Return(testStructs.NewUpgradeConfigBuilder().GetUpgradeConfig(), nil).Times(2)
+			eventNotify, err := NewEventNotifier(recorder, mockUpgradeConfigManager)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(eventNotify.NotifyState(MuoStateStarted, description)).To(Succeed())
+			Expect(<-recorder.Events).To(Equal("Normal UpgradeNotification Upgrade-State: StateStarted Description: Testing"))
+			Expect(eventNotify.NotifyState(MuoStateFailed, description)).To(Succeed())
+			Expect(<-recorder.Events).To(HavePrefix("Warning UpgradeNotification"))
+		})
+
+		It("requires a recorder", func() {
+			_, err := NewEventNotifier(nil, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+// blockingNotifier is a notifier which does not return until it is released
+type blockingNotifier struct {
+	release chan struct{}
+}
+
+func (b *blockingNotifier) NotifyState(state MuoState, description string) error {
+	<-b.release
+	return nil
+}
@@ -0,0 +1,262 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/util"
+)
+
+const (
+	// LedgerConfigMapName is the ConfigMap in the operator namespace recording the notifications sent
+	LedgerConfigMapName = "managed-upgrade-operator-notifications"
+)
+
+// DeliveryResult is the result of the last attempt to send a notification
+type DeliveryResult string
+
+const (
+	// DeliverySent is the result of a notification which was sent
+	DeliverySent DeliveryResult = "Sent"
+	// DeliveryFailed is the result of a notification which failed to be sent
+	DeliveryFailed DeliveryResult = "Failed"
+)
+
+// invalidLedgerKeyChars matches the characters which are not allowed in a ConfigMap key
+var invalidLedgerKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// LedgerEntry records the delivery of the notification of a state for an upgrade, to the primary notifier
+// or, if the Sink is set, to a secondary sink. The Sequence orders the entries of the upgrade by the time
+// they were last recorded. The entries of sinks keep the Description sent, so that it can be sent again.
+type LedgerEntry struct {
+	State       MuoState       `json:"state"`
+	Version     string         `json:"version"`
+	UpgradeAt   string         `json:"upgradeAt"`
+	Sink        string         `json:"sink,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Result      DeliveryResult `json:"result"`
+	Attempts    int            `json:"attempts"`
+	Sequence    int            `json:"sequence"`
+	Timestamp   metav1.Time    `json:"timestamp"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// Ledger records the notifications sent for an upgrade, so that a state is notified once per upgrade
+// regardless of operator restarts, and so that the upgrade's state transitions are tracked
+//
+//go:generate mockgen -destination=mocks/ledger.go -package=mocks github.com/openshift/managed-upgrade-operator/pkg/notifier Ledger
+type Ledger interface {
+	IsNotified(uc *upgradev1alpha1.UpgradeConfig, state MuoState) (bool, error)
+	LastState(uc *upgradev1alpha1.UpgradeConfig) (MuoState, error)
+	Record(uc *upgradev1alpha1.UpgradeConfig, state MuoState, sendErr error) error
+	FailedSinkDeliveries(uc *upgradev1alpha1.UpgradeConfig) ([]LedgerEntry, error)
+	RecordSink(uc *upgradev1alpha1.UpgradeConfig, state MuoState, sink string, description string, sendErr error) error
+}
+
+// NewLedger returns a Ledger kept in a ConfigMap of the operator namespace
+func NewLedger(c client.Client) Ledger {
+	return &configMapLedger{client: c}
+}
+
+type configMapLedger struct {
+	client client.Client
+}
+
+// IsNotified returns true if the notification of the state was sent for the upgrade
+func (l *configMapLedger) IsNotified(uc *upgradev1alpha1.UpgradeConfig, state MuoState) (bool, error) {
+	cm, err := l.get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	entry, err := readLedgerEntry(cm, ledgerKey(uc.Spec.Desired.Version, state))
+	if err != nil {
+		// An unreadable entry is overwritten when the notification is next recorded
+		log.Error(err, "ignoring notification ledger entry")
+		return false, nil
+	}
+	if entry == nil {
+		return false, nil
+	}
+	return entry.UpgradeAt == uc.Spec.UpgradeAt && entry.Result == DeliverySent, nil
+}
+
+// LastState returns the state of the upgrade which was notified last, or an empty state if none was
+func (l *configMapLedger) LastState(uc *upgradev1alpha1.UpgradeConfig) (MuoState, error) {
+	cm, err := l.get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	var last *LedgerEntry
+	for key := range cm.Data {
+		entry, err := readLedgerEntry(cm, key)
+		if err != nil || entry.Version != uc.Spec.Desired.Version || entry.UpgradeAt != uc.Spec.UpgradeAt {
+			continue
+		}
+		if entry.Sink != "" || entry.Result != DeliverySent || !IsTransitionState(entry.State) {
+			continue
+		}
+		if last == nil || entry.Sequence > last.Sequence {
+			last = entry
+		}
+	}
+	if last == nil {
+		return "", nil
+	}
+	return last.State, nil
+}
+
+// FailedSinkDeliveries returns the entries of the notifications of the upgrade which failed to be sent
+// to a secondary sink, ordered by the time they were last recorded
+func (l *configMapLedger) FailedSinkDeliveries(uc *upgradev1alpha1.UpgradeConfig) ([]LedgerEntry, error) {
+	cm, err := l.get()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	failed := []LedgerEntry{}
+	for key := range cm.Data {
+		entry, err := readLedgerEntry(cm, key)
+		if err != nil || entry.Version != uc.Spec.Desired.Version || entry.UpgradeAt != uc.Spec.UpgradeAt {
+			continue
+		}
+		if entry.Sink != "" && entry.Result == DeliveryFailed {
+			failed = append(failed, *entry)
+		}
+	}
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].Sequence < failed[j].Sequence
+	})
+	return failed, nil
+}
+
+// Record records the result of an attempt to send the notification of the state for the upgrade.
+// The entries of any other upgrade are removed from the ledger.
+func (l *configMapLedger) Record(uc *upgradev1alpha1.UpgradeConfig, state MuoState, sendErr error) error {
+	return l.record(uc, ledgerKey(uc.Spec.Desired.Version, state), &LedgerEntry{State: state}, sendErr)
+}
+
+// RecordSink records the result of an attempt to send the notification of the state for the upgrade to
+// the secondary sink. The entries of any other upgrade are removed from the ledger.
+func (l *configMapLedger) RecordSink(uc *upgradev1alpha1.UpgradeConfig, state MuoState, sink string, description string, sendErr error) error {
+	return l.record(uc, sinkLedgerKey(uc.Spec.Desired.Version, state, sink), &LedgerEntry{State: state, Sink: sink, Description: description}, sendErr)
+}
+
+// record records the result of an attempt to send the notification of the entry under the key
+func (l *configMapLedger) record(uc *upgradev1alpha1.UpgradeConfig, key string, newEntry *LedgerEntry, sendErr error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := l.get()
+		create := errors.IsNotFound(err)
+		if err != nil && !create {
+			return err
+		}
+		if create {
+			ns, err := util.GetOperatorNamespace()
+			if err != nil {
+				return err
+			}
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: LedgerConfigMapName, Namespace: ns}}
+		}
+
+		entry, err := readLedgerEntry(cm, key)
+		if err != nil || entry == nil || entry.UpgradeAt != uc.Spec.UpgradeAt {
+			entry = &LedgerEntry{State: newEntry.State, Sink: newEntry.Sink, Version: uc.Spec.Desired.Version, UpgradeAt: uc.Spec.UpgradeAt}
+		}
+		entry.Description = newEntry.Description
+		entry.Attempts++
+		entry.Timestamp = metav1.Now()
+		entry.Result = DeliverySent
+		entry.Error = ""
+		if sendErr != nil {
+			entry.Result = DeliveryFailed
+			entry.Error = sendErr.Error()
+		}
+
+		pruneLedger(cm, uc)
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		entry.Sequence = nextSequence(cm)
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		cm.Data[key] = string(data)
+
+		if create {
+			return l.client.Create(context.TODO(), cm)
+		}
+		return l.client.Update(context.TODO(), cm)
+	})
+}
+
+func (l *configMapLedger) get() (*corev1.ConfigMap, error) {
+	ns, err := util.GetOperatorNamespace()
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{}
+	err = l.client.Get(context.TODO(), client.ObjectKey{Namespace: ns, Name: LedgerConfigMapName}, cm)
+	return cm, err
+}
+
+// pruneLedger removes the entries of upgrades other than the upgrade from the ledger
+func pruneLedger(cm *corev1.ConfigMap, uc *upgradev1alpha1.UpgradeConfig) {
+	for key := range cm.Data {
+		entry, err := readLedgerEntry(cm, key)
+		if err != nil || entry.Version != uc.Spec.Desired.Version || entry.UpgradeAt != uc.Spec.UpgradeAt {
+			delete(cm.Data, key)
+		}
+	}
+}
+
+// nextSequence returns the sequence of the next entry recorded in the ledger
+func nextSequence(cm *corev1.ConfigMap) int {
+	next := 1
+	for key := range cm.Data {
+		if entry, err := readLedgerEntry(cm, key); err == nil && entry.Sequence >= next {
+			next = entry.Sequence + 1
+		}
+	}
+	return next
+}
+
+// readLedgerEntry returns the entry of the key, or nil if the ledger has none
+func readLedgerEntry(cm *corev1.ConfigMap, key string) (*LedgerEntry, error) {
+	data, ok := cm.Data[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := &LedgerEntry{}
+	if err := json.Unmarshal([]byte(data), entry); err != nil {
+		return nil, fmt.Errorf("can't read notification ledger entry %s: %v", key, err)
+	}
+	return entry, nil
+}
+
+// ledgerKey returns the key of the notification of the state for the version
+func ledgerKey(version string, state MuoState) string {
+	return invalidLedgerKeyChars.ReplaceAllString(version, "_") + "." + string(state)
+}
+
+// sinkLedgerKey returns the key of the notification of the state for the version to the secondary sink
+func sinkLedgerKey(version string, state MuoState, sink string) string {
+	return ledgerKey(version, state) + ".sink." + invalidLedgerKeyChars.ReplaceAllString(sink, "_")
+}
@@ -0,0 +1,213 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("Notification Ledger", func() {
+	var (
+		mockCtrl       *gomock.Controller
+		mockKubeClient *mocks.MockClient
+		ledger         Ledger
+		uc             *upgradev1alpha1.UpgradeConfig
+		ledgerName     = types.NamespacedName{Namespace: "test-namespace", Name: LedgerConfigMapName}
+		notFound       = kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, LedgerConfigMapName)
+	)
+
+	ledgerWith := func(entries ...LedgerEntry) corev1.ConfigMap {
+		cm := corev1.ConfigMap{Data: map[string]string{}}
+		for _, entry := range entries {
+			data, _ := json.Marshal(entry)
+			key := ledgerKey(entry.Version, entry.State)
+			if entry.Sink != "" {
+				key = sinkLedgerKey(entry.Version, entry.State, entry.Sink)
+			}
+			cm.Data[key] = string(data)
+		}
+		return cm
+	}
+
+	BeforeEach(func() {
+		_ = os.Setenv("OPERATOR_NAMESPACE", "test-namespace")
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		ledger = NewLedger(mockKubeClient)
+		uc = testStructs.NewUpgradeConfigBuilder().GetUpgradeConfig()
+		uc.Spec.Desired.Version = "4.18.1"
+		uc.Spec.UpgradeAt = "2025-06-01T12:00:00Z"
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("Checking whether a state was notified", func() {
+		It("reports no notification when there is no ledger", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).Return(notFound)
+			Expect(ledger.IsNotified(uc, MuoStateStarted)).To(BeFalse())
+		})
+
+		It("reports the notifications sent for the upgrade", func() {
+			cm := ledgerWith(
+				LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliverySent},
+				LedgerEntry{State: MuoStateDelayed, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliveryFailed},
+			)
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, cm).Times(3)
+			Expect(ledger.IsNotified(uc, MuoStateStarted)).To(BeTrue())
+			Expect(ledger.IsNotified(uc, MuoStateDelayed)).To(BeFalse())
+			Expect(ledger.IsNotified(uc, MuoStateCompleted)).To(BeFalse())
+		})
+
+		It("ignores the notifications sent for an earlier upgrade to the version", func() {
+			cm := ledgerWith(LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: "2025-05-01T12:00:00Z", Result: DeliverySent})
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, cm)
+			Expect(ledger.IsNotified(uc, MuoStateStarted)).To(BeFalse())
+		})
+
+		It("fails when the ledger can't be read", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).Return(fmt.Errorf("fake error"))
+			_, err := ledger.IsNotified(uc, MuoStateStarted)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Finding the last state notified", func() {
+		It("reports no state when there is no ledger", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).Return(notFound)
+			Expect(ledger.LastState(uc)).To(BeEmpty())
+		})
+
+		It("reports the upgrade state sent last", func() {
+			cm := ledgerWith(
+				LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliverySent, Sequence: 1},
+				LedgerEntry{State: MuoStateDelayed, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliverySent, Sequence: 2},
+				LedgerEntry{State: MuoStateHealthCheckSL, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliverySent, Sequence: 3},
+				LedgerEntry{State: MuoStateSkipped, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliveryFailed, Sequence: 4},
+				LedgerEntry{State: MuoStateCompleted, Version: "4.18.1", UpgradeAt: "2025-05-01T12:00:00Z", Result: DeliverySent, Sequence: 5},
+				LedgerEntry{State: MuoStateFailed, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Sink: "webhook", Result: DeliverySent, Sequence: 6},
+			)
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, cm)
+			Expect(ledger.LastState(uc)).To(Equal(MuoStateDelayed))
+		})
+	})
+
+	Context("Finding the failed deliveries to the sinks", func() {
+		It("reports the failed deliveries of the upgrade to the sinks in the order they were sent", func() {
+			cm := ledgerWith(
+				LedgerEntry{State: MuoStateFailed, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliveryFailed, Sequence: 1},
+				LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Sink: "webhook", Result: DeliveryFailed, Sequence: 3},
+				LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Sink: "events", Result: DeliveryFailed, Sequence: 2},
+				LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Sink: "slack", Result: DeliverySent, Sequence: 4},
+				LedgerEntry{State: MuoStateFailed, Version: "4.18.1", UpgradeAt: "2025-05-01T12:00:00Z", Sink: "webhook", Result: DeliveryFailed, Sequence: 5},
+			)
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, cm)
+			failed, err := ledger.FailedSinkDeliveries(uc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failed).To(HaveLen(2))
+			Expect(failed[0].Sink).To(Equal("events"))
+			Expect(failed[1].Sink).To(Equal("webhook"))
+		})
+
+		It("reports no failed deliveries when there is no ledger", func() {
+			mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).Return(notFound)
+			Expect(ledger.FailedSinkDeliveries(uc)).To(BeEmpty())
+		})
+	})
+
+	Context("Recording a notification", func() {
+		var recorded *corev1.ConfigMap
+
+		entryOf := func(state MuoState) LedgerEntry {
+			entry := LedgerEntry{}
+			Expect(json.Unmarshal([]byte(recorded.Data[ledgerKey("4.18.1", state)]), &entry)).To(Succeed())
+			return entry
+		}
+
+		It("creates the ledger", func() {
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).Return(notFound),
+				mockKubeClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, obj client.Object, _ ...client.CreateOption) error {
+					recorded = obj.(*corev1.ConfigMap)
+					return nil
+				}),
+			)
+			Expect(ledger.Record(uc, MuoStateStarted, nil)).To(Succeed())
+			Expect(recorded.Name).To(Equal(LedgerConfigMapName))
+			Expect(recorded.Namespace).To(Equal("test-namespace"))
+			entry := entryOf(MuoStateStarted)
+			Expect(entry.Result).To(Equal(DeliverySent))
+			Expect(entry.Attempts).To(Equal(1))
+			Expect(entry.Sequence).To(Equal(1))
+			Expect(entry.UpgradeAt).To(Equal(uc.Spec.UpgradeAt))
+		})
+
+		It("counts the attempts and prunes the entries of other upgrades", func() {
+			cm := ledgerWith(
+				LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliverySent, Attempts: 1, Sequence: 1},
+				LedgerEntry{State: MuoStateFailed, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliveryFailed, Attempts: 1, Sequence: 2},
+				LedgerEntry{State: MuoStateStarted, Version: "4.17.9", UpgradeAt: "2025-05-01T12:00:00Z", Result: DeliverySent, Attempts: 1, Sequence: 7},
+			)
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, cm),
+				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, obj client.Object, _ ...client.UpdateOption) error {
+					recorded = obj.(*corev1.ConfigMap)
+					return nil
+				}),
+			)
+			Expect(ledger.Record(uc, MuoStateFailed, fmt.Errorf("fake error"))).To(Succeed())
+			Expect(recorded.Data).To(HaveLen(2))
+			entry := entryOf(MuoStateFailed)
+			Expect(entry.Result).To(Equal(DeliveryFailed))
+			Expect(entry.Attempts).To(Equal(2))
+			Expect(entry.Sequence).To(Equal(3))
+			Expect(entry.Error).To(Equal("fake error"))
+		})
+
+		It("retries when the ledger was modified concurrently", func() {
+			conflict := kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, LedgerConfigMapName, fmt.Errorf("fake conflict"))
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, ledgerWith()),
+				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(conflict),
+				mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, ledgerWith()),
+				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()),
+			)
+			Expect(ledger.Record(uc, MuoStateStarted, nil)).To(Succeed())
+		})
+
+		It("records the delivery to a sink apart from the notification", func() {
+			cm := ledgerWith(LedgerEntry{State: MuoStateStarted, Version: "4.18.1", UpgradeAt: uc.Spec.UpgradeAt, Result: DeliverySent, Attempts: 1, Sequence: 1})
+			gomock.InOrder(
+				mockKubeClient.EXPECT().Get(gomock.Any(), ledgerName, gomock.Any()).SetArg(2, cm),
+				mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, obj client.Object, _ ...client.UpdateOption) error {
+					recorded = obj.(*corev1.ConfigMap)
+					return nil
+				}),
+			)
+			Expect(ledger.RecordSink(uc, MuoStateStarted, "webhook", "upgrade started", fmt.Errorf("fake error"))).To(Succeed())
+			Expect(recorded.Data).To(HaveLen(2))
+			Expect(entryOf(MuoStateStarted).Result).To(Equal(DeliverySent))
+			entry := LedgerEntry{}
+			Expect(json.Unmarshal([]byte(recorded.Data[sinkLedgerKey("4.18.1", MuoStateStarted, "webhook")]), &entry)).To(Succeed())
+			Expect(entry.Sink).To(Equal("webhook"))
+			Expect(entry.Description).To(Equal("upgrade started"))
+			Expect(entry.Result).To(Equal(DeliveryFailed))
+			Expect(entry.Attempts).To(Equal(1))
+			Expect(entry.Sequence).To(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,245 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+// DefaultLocale is the locale whose configured messages are used when the configured locale has none
+const DefaultLocale = "en"
+
+const (
+	// KCSUpgradeHealthCheck is the knowledge base article describing the upgrade health checks
+	KCSUpgradeHealthCheck = "https://access.redhat.com/solutions/7081505"
+	// KCSNodeDrainGracePeriod is the knowledge base article describing the node drain grace period
+	KCSNodeDrainGracePeriod = "https://access.redhat.com/solutions/7075425"
+)
+
+// MessageID identifies a notification message
+type MessageID string
+
+// Represents the notification messages which can be configured
+const (
+	MessageUpgradeStarted               MessageID = "UpgradeStarted"
+	MessageUpgradeCompleted             MessageID = "UpgradeCompleted"
+	MessageUpgradeNotUpgradable         MessageID = "UpgradeNotUpgradable"
+	MessageUpgradePrecheckFailed        MessageID = "UpgradePrecheckFailed"
+	MessageUpgradePreHealthCheckFailed  MessageID = "UpgradePreHealthCheckFailed"
+	MessageUpgradeExtDepCheckFailed     MessageID = "UpgradeExtDepCheckFailed"
+	MessageUpgradeScaleFailed           MessageID = "UpgradeScaleFailed"
+	MessageUpgradeScaleSkipped          MessageID = "UpgradeScaleSkipped"
+	MessageUpgradeDefaultDelayed        MessageID = "UpgradeDefaultDelayed"
+	MessageUpgradePreHealthCheckDelayed MessageID = "UpgradePreHealthCheckDelayed"
+	MessageUpgradeExtDepCheckDelayed    MessageID = "UpgradeExtDepCheckDelayed"
+	MessageUpgradeScaleDelayed          MessageID = "UpgradeScaleDelayed"
+	MessageUpgradeScaleDelaySkipped     MessageID = "UpgradeScaleDelaySkipped"
+	MessageUpgradeStepSkipped           MessageID = "UpgradeStepSkipped"
+	MessageNodeDrainDelayed             MessageID = "NodeDrainDelayed"
+	MessageHealthCheckDelayed           MessageID = "HealthCheckDelayed"
+	MessagePreHealthCheckWarning        MessageID = "PreHealthCheckWarning"
+	MessageCanaryHealthCheckFailed      MessageID = "CanaryHealthCheckFailed"
+	MessageControlPlaneStarted          MessageID = "ControlPlaneStarted"
+	MessageControlPlaneFinished         MessageID = "ControlPlaneFinished"
+	MessageWorkerPlaneFinished          MessageID = "WorkerPlaneFinished"
+
+	// ServiceLog summaries
+
+	MessageControlPlaneStartedSummary     MessageID = "ControlPlaneStartedSummary"
+	MessageControlPlaneFinishedSummary    MessageID = "ControlPlaneFinishedSummary"
+	MessageWorkerPlaneFinishedSummary     MessageID = "WorkerPlaneFinishedSummary"
+	MessageHealthCheckSummary             MessageID = "HealthCheckSummary"
+	MessagePreHealthCheckSummary          MessageID = "PreHealthCheckSummary"
+	MessageCanaryHealthCheckFailedSummary MessageID = "CanaryHealthCheckFailedSummary"
+)
+
+// defaultMessages holds the built-in template of every message
+var defaultMessages = map[MessageID]string{
+	MessageUpgradeStarted:               "Cluster is currently being upgraded to version {{.Version}}",
+	MessageUpgradeCompleted:             "Cluster has been successfully upgraded to version {{.Version}}",
+	MessageUpgradeNotUpgradable:         "{{.FailedCondition.Message}}",
+	MessageUpgradePrecheckFailed:        "Cluster upgrade to version {{.Version}} was cancelled as the cluster did not pass its pre-upgrade verification checks. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled",
+	MessageUpgradePreHealthCheckFailed:  "Cluster upgrade to version {{.Version}} was cancelled during the Pre-Health Check step. Health alerts are firing in the cluster which could impact the upgrade's operation, so the upgrade did not proceed. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled",
+	MessageUpgradeExtDepCheckFailed:     "Cluster upgrade to version {{.Version}} was cancelled during the External Dependency Availability Check step. A required external dependency of the upgrade was unavailable, so the upgrade did not proceed. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled",
+	MessageUpgradeScaleFailed:           "Cluster upgrade to version {{.Version}} was cancelled during the Scale-Up Worker Node step. A temporary additional worker node was unable to be created to temporarily house workloads, so the upgrade did not proceed. Automated upgrades will be retried on their next scheduling cycle. If you have manually scheduled an upgrade instead, it must now be rescheduled",
+	MessageUpgradeScaleSkipped:          "Cluster upgrade to version {{.Version}} has skipped Scale-Up additional Worker Node step for compute capacity reservation. This is an informational notification and no action is required by you",
+	MessageUpgradeDefaultDelayed:        "Cluster upgrade to version {{.Version}} is experiencing a delay whilst it performs necessary pre-upgrade procedures. The upgrade will continue to retry. This is an informational notification and no action is required",
+	MessageUpgradePreHealthCheckDelayed: "Cluster upgrade to version {{.Version}} is experiencing a delay as health alerts are firing in the cluster which could impact the upgrade's operation. The upgrade will continue to retry. This is an informational notification and no action is required by you",
+	MessageUpgradeExtDepCheckDelayed:    "Cluster upgrade to version {{.Version}} is experiencing a delay as an external dependency of the upgrade is currently unavailable. The upgrade will continue to retry. This is an informational notification and no action is required by you",
+	MessageUpgradeScaleDelayed:          "Cluster upgrade to version {{.Version}} is experiencing a delay attempting to scale up an additional worker node. The upgrade will continue to retry. This is an informational notification and no action is required by you",
+	MessageUpgradeScaleDelaySkipped:     "Cluster upgrade to version {{.Version}} has experienced an issue during capacity reservation efforts. This could be caused by cloud service provider quota limitations or temporary connectivity issues to/from the new worker node. The upgrade will continue without extra compute. This is an informational notification and no action is required by you",
+	MessageUpgradeStepSkipped:           "Cluster upgrade to version {{.Version}} has skipped an upgrade step which did not complete in the expected time. The upgrade will continue. This is an informational notification and no action is required by you",
+	MessageNodeDrainDelayed:             "Node drain grace period might be impacting cluster upgrade. Please refer to the article for further details {{.KCS}}",
+	MessageHealthCheckDelayed:           "Cluster upgrade to version {{.Version}} may experience a delay as following healthcheck(s): {{.HealthCheckResults}} are failing for the cluster which could impact the upgrade's operation.",
+	MessagePreHealthCheckWarning:        "Cluster upgrade to version {{.Version}} has been scheduled for more than 2 hours, cluster pre-upgrade health check has identified the following points which may impact the upgrade process: {{.HealthCheckResults}}. Please take actions to review and fix the issues before the upgrade begins to have seamless upgrade experience",
+	MessageCanaryHealthCheckFailed:      "Cluster upgrade to version {{.Version}} has paused the worker plane upgrade as the following healthcheck(s): {{.HealthCheckResults}} failed after upgrading the canary worker nodes. The remaining worker nodes will not be upgraded until the healthcheck(s) pass. Please review the canary worker nodes and the workloads running on them",
+	MessageControlPlaneStarted:          "Cluster upgrade to version {{.Version}} is starting with control and worker plane upgrade. This is an informational notification and no action is required",
+	MessageControlPlaneFinished:         "Cluster upgrade to version {{.Version}} has finished control plane upgrade. This is an informational notification and no action is required",
+	MessageWorkerPlaneFinished:          "Cluster upgrade to version {{.Version}} has finished worker plane upgrade. This is an informational notification and no action is required.",
+
+	MessageControlPlaneStartedSummary:     "Cluster is starting with control and worker plane upgrade",
+	MessageControlPlaneFinishedSummary:    "Cluster has finished control plane upgrade",
+	MessageWorkerPlaneFinishedSummary:     "Cluster has finished with worker plane upgrade",
+	MessageHealthCheckSummary:             "Cluster has encountered healthcheck failure during upgrade",
+	MessagePreHealthCheckSummary:          "Cluster has encountered pre-upgrade healthcheck failure",
+	MessageCanaryHealthCheckFailedSummary: "Cluster has held its worker plane upgrade after a canary healthcheck failure",
+}
+
+// messageKCS holds the knowledge base article of the messages which have one
+var messageKCS = map[MessageID]string{
+	MessageNodeDrainDelayed:               KCSNodeDrainGracePeriod,
+	MessageHealthCheckDelayed:             KCSUpgradeHealthCheck,
+	MessagePreHealthCheckWarning:          KCSUpgradeHealthCheck,
+	MessageCanaryHealthCheckFailed:        KCSUpgradeHealthCheck,
+	MessageHealthCheckSummary:             KCSUpgradeHealthCheck,
+	MessagePreHealthCheckSummary:          KCSUpgradeHealthCheck,
+	MessageCanaryHealthCheckFailedSummary: KCSUpgradeHealthCheck,
+}
+
+var builtinMessages = mustParseMessages(defaultMessages)
+
+// MessageContext holds the values available to the template of a message
+type MessageContext struct {
+	// Version is the version the cluster is upgrading to
+	Version string
+	// PrecedingVersion is the version the cluster is upgrading from, if known
+	PrecedingVersion string
+	// FailedCondition is the first incomplete condition of the upgrade, if any
+	FailedCondition *upgradev1alpha1.UpgradeCondition
+	// HealthCheckResults describes the failing health checks of a health check notification
+	HealthCheckResults string
+	// KCS links the knowledge base article of the message, if it has one
+	KCS string
+}
+
+// NewMessageContext returns the MessageContext of the current upgrade of the UpgradeConfig
+func NewMessageContext(uc *upgradev1alpha1.UpgradeConfig) MessageContext {
+	ctx := MessageContext{Version: uc.Spec.Desired.Version}
+	history := uc.Status.History.GetHistory(uc.Spec.Desired.Version)
+	if history == nil {
+		return ctx
+	}
+	ctx.PrecedingVersion = history.PrecedingVersion
+	for i := range history.Conditions {
+		// The first incomplete condition describes the step the upgrade got to (there should only be one)
+		if history.Conditions[i].IsFalse() {
+			ctx.FailedCondition = &history.Conditions[i]
+			break
+		}
+	}
+	return ctx
+}
+
+// Messages renders the notification messages of a locale from the configured templates, falling
+// back to the built-in templates. A nil Messages renders the built-in templates.
+type Messages struct {
+	templates map[MessageID]*template.Template
+}
+
+// DefaultMessages returns the Messages rendering the built-in templates
+func DefaultMessages() *Messages {
+	return &Messages{}
+}
+
+// NewMessages returns the Messages of the locale from the configured templates, which are keyed by
+// locale and message. A message the locale has no template for uses the template of the DefaultLocale.
+func NewMessages(locale string, templates map[string]map[string]string) (*Messages, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	resolved := toMessageIDs(templates[DefaultLocale])
+	for id, text := range toMessageIDs(templates[locale]) {
+		resolved[id] = text
+	}
+	parsed, err := parseMessages(resolved)
+	if err != nil {
+		return nil, err
+	}
+	return &Messages{templates: parsed}, nil
+}
+
+// Render returns the message rendered with the context. A configured template which fails to render
+// is logged and the built-in template is rendered instead.
+func (m *Messages) Render(id MessageID, ctx MessageContext) string {
+	if ctx.KCS == "" {
+		ctx.KCS = messageKCS[id]
+	}
+	if m != nil {
+		if tmpl, ok := m.templates[id]; ok {
+			text, err := execute(tmpl, ctx)
+			if err == nil {
+				return text
+			}
+			log.Error(err, fmt.Sprintf("failed to render configured message %s, using the default message", id))
+		}
+	}
+	text, err := execute(builtinMessages[id], ctx)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("failed to render message %s", id))
+	}
+	return text
+}
+
+// validateMessages returns an error if a configured template is not a known message, can't be parsed
+// or refers to a value the MessageContext does not have
+func validateMessages(templates map[string]map[string]string) error {
+	sample := MessageContext{FailedCondition: &upgradev1alpha1.UpgradeCondition{}}
+	for locale, messages := range templates {
+		parsed, err := parseMessages(toMessageIDs(messages))
+		if err != nil {
+			return fmt.Errorf("messages of locale %s are invalid: %v", locale, err)
+		}
+		for id, tmpl := range parsed {
+			if _, err := execute(tmpl, sample); err != nil {
+				return fmt.Errorf("message %s of locale %s is invalid: %v", id, locale, err)
+			}
+		}
+	}
+	return nil
+}
+
+func toMessageIDs(messages map[string]string) map[MessageID]string {
+	result := map[MessageID]string{}
+	for id, text := range messages {
+		result[MessageID(id)] = text
+	}
+	return result
+}
+
+// parseMessages parses the templates of known messages
+func parseMessages(messages map[MessageID]string) (map[MessageID]*template.Template, error) {
+	parsed := map[MessageID]*template.Template{}
+	for id, text := range messages {
+		if _, ok := defaultMessages[id]; !ok {
+			return nil, fmt.Errorf("%s is not a known message", id)
+		}
+		tmpl, err := template.New(string(id)).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		parsed[id] = tmpl
+	}
+	return parsed, nil
+}
+
+func mustParseMessages(messages map[MessageID]string) map[MessageID]*template.Template {
+	parsed, err := parseMessages(messages)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// execute renders the template with the context
+func execute(tmpl *template.Template, ctx MessageContext) (string, error) {
+	if tmpl == nil {
+		return "", fmt.Errorf("message has no template")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("Notification messages", func() {
+	var ctx MessageContext
+
+	BeforeEach(func() {
+		ctx = MessageContext{Version: "4.18.1", PrecedingVersion: "4.17.9", HealthCheckResults: "CriticalAlerts"}
+	})
+
+	It("has a built-in template for every message", func() {
+		for id := range defaultMessages {
+			Expect(builtinMessages).To(HaveKey(id))
+		}
+	})
+
+	It("renders the built-in messages", func() {
+		Expect(DefaultMessages().Render(MessageUpgradeStarted, ctx)).To(Equal("Cluster is currently being upgraded to version 4.18.1"))
+		Expect(DefaultMessages().Render(MessageHealthCheckDelayed, ctx)).To(Equal("Cluster upgrade to version 4.18.1 may experience a delay as following healthcheck(s): CriticalAlerts are failing for the cluster which could impact the upgrade's operation."))
+		Expect(DefaultMessages().Render(MessageNodeDrainDelayed, ctx)).To(HaveSuffix(KCSNodeDrainGracePeriod))
+	})
+
+	It("renders the built-in messages without configured messages", func() {
+		var messages *Messages
+		Expect(messages.Render(MessageUpgradeCompleted, ctx)).To(Equal("Cluster has been successfully upgraded to version 4.18.1"))
+	})
+
+	Context("With configured messages", func() {
+		templates := map[string]map[string]string{
+			DefaultLocale: {
+				string(MessageUpgradeStarted):   "Upgrading from {{.PrecedingVersion}} to {{.Version}}",
+				string(MessageUpgradeCompleted): "Upgraded to {{.Version}}",
+			},
+			"fr": {
+				string(MessageUpgradeStarted): "Mise à niveau vers {{.Version}}",
+			},
+		}
+
+		It("renders the messages of the locale", func() {
+			messages, err := NewMessages("fr", templates)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages.Render(MessageUpgradeStarted, ctx)).To(Equal("Mise à niveau vers 4.18.1"))
+		})
+
+		It("falls back to the messages of the default locale", func() {
+			messages, err := NewMessages("fr", templates)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages.Render(MessageUpgradeCompleted, ctx)).To(Equal("Upgraded to 4.18.1"))
+			messages, err = NewMessages("", templates)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages.Render(MessageUpgradeStarted, ctx)).To(Equal("Upgrading from 4.17.9 to 4.18.1"))
+		})
+
+		It("falls back to the built-in messages", func() {
+			messages, err := NewMessages("fr", templates)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages.Render(MessageUpgradeScaleSkipped, ctx)).To(Equal(DefaultMessages().Render(MessageUpgradeScaleSkipped, ctx)))
+		})
+
+		It("falls back to the built-in message if the configured message can't be rendered", func() {
+			messages, err := NewMessages("", map[string]map[string]string{
+				DefaultLocale: {string(MessageUpgradeStarted): "{{.FailedCondition.Message}}"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages.Render(MessageUpgradeStarted, ctx)).To(Equal("Cluster is currently being upgraded to version 4.18.1"))
+		})
+
+		It("rejects unknown messages and invalid templates", func() {
+			for _, invalid := range []map[string]string{
+				{"UnknownMessage": "text"},
+				{string(MessageUpgradeStarted): "{{.Version"},
+				{string(MessageUpgradeStarted): "{{.Unknown}}"},
+			} {
+				cfg := NotifierConfig{Notifier: NotifierSource{Messages: map[string]map[string]string{"fr": invalid}}}
+				Expect(cfg.IsValid()).NotTo(Succeed())
+			}
+			cfg := NotifierConfig{Notifier: NotifierSource{Locale: "fr", Messages: templates}}
+			Expect(cfg.IsValid()).To(Succeed())
+		})
+	})
+
+	Context("Message context", func() {
+		It("describes the current upgrade", func() {
+			uc := testStructs.NewUpgradeConfigBuilder().WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			uc.Status.History[0].PrecedingVersion = "4.17.9"
+			uc.Status.History[0].Conditions = []upgradev1alpha1.UpgradeCondition{
+				{Type: upgradev1alpha1.UpgradePreHealthCheck, Status: corev1.ConditionTrue},
+				{Type: upgradev1alpha1.UpgradeScaleUpExtraNodes, Status: corev1.ConditionFalse, Message: "Cannot scale nodes."},
+			}
+			ctx := NewMessageContext(uc)
+			Expect(ctx.Version).To(Equal(uc.Spec.Desired.Version))
+			Expect(ctx.PrecedingVersion).To(Equal("4.17.9"))
+			Expect(ctx.FailedCondition).NotTo(BeNil())
+			Expect(ctx.FailedCondition.Type).To(Equal(upgradev1alpha1.UpgradeScaleUpExtraNodes))
+		})
+
+		It("has no failed condition without a history", func() {
+			uc := testStructs.NewUpgradeConfigBuilder().GetUpgradeConfig()
+			Expect(NewMessageContext(uc).FailedCondition).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/openshift/managed-upgrade-operator/pkg/notifier (interfaces: Ledger)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/ledger.go -package=mocks github.com/openshift/managed-upgrade-operator/pkg/notifier Ledger
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	v1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	notifier "github.com/openshift/managed-upgrade-operator/pkg/notifier"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLedger is a mock of Ledger interface.
+type MockLedger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerMockRecorder
+}
+
+// MockLedgerMockRecorder is the mock recorder for MockLedger.
+type MockLedgerMockRecorder struct {
+	mock *MockLedger
+}
+
+// NewMockLedger creates a new mock instance.
+func NewMockLedger(ctrl *gomock.Controller) *MockLedger {
+	mock := &MockLedger{ctrl: ctrl}
+	mock.recorder = &MockLedgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedger) EXPECT() *MockLedgerMockRecorder {
+	return m.recorder
+}
+
+// FailedSinkDeliveries mocks base method.
+func (m *MockLedger) FailedSinkDeliveries(arg0 *v1alpha1.UpgradeConfig) ([]notifier.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailedSinkDeliveries", arg0)
+	ret0, _ := ret[0].([]notifier.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailedSinkDeliveries indicates an expected call of FailedSinkDeliveries.
+func (mr *MockLedgerMockRecorder) FailedSinkDeliveries(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailedSinkDeliveries", reflect.TypeOf((*MockLedger)(nil).FailedSinkDeliveries), arg0)
+}
+
+// IsNotified mocks base method.
+func (m *MockLedger) IsNotified(arg0 *v1alpha1.UpgradeConfig, arg1 notifier.MuoState) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNotified", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsNotified indicates an expected call of IsNotified.
+func (mr *MockLedgerMockRecorder) IsNotified(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNotified", reflect.TypeOf((*MockLedger)(nil).IsNotified), arg0, arg1)
+}
+
+// LastState mocks base method.
+func (m *MockLedger) LastState(arg0 *v1alpha1.UpgradeConfig) (notifier.MuoState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastState", arg0)
+	ret0, _ := ret[0].(notifier.MuoState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastState indicates an expected call of LastState.
+func (mr *MockLedgerMockRecorder) LastState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastState", reflect.TypeOf((*MockLedger)(nil).LastState), arg0)
+}
+
+// Record mocks base method.
+func (m *MockLedger) Record(arg0 *v1alpha1.UpgradeConfig, arg1 notifier.MuoState, arg2 error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockLedgerMockRecorder) Record(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockLedger)(nil).Record), arg0, arg1, arg2)
+}
+
+// RecordSink mocks base method.
+func (m *MockLedger) RecordSink(arg0 *v1alpha1.UpgradeConfig, arg1 notifier.MuoState, arg2, arg3 string, arg4 error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSink", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSink indicates an expected call of RecordSink.
+func (mr *MockLedgerMockRecorder) RecordSink(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSink", reflect.TypeOf((*MockLedger)(nil).RecordSink), arg0, arg1, arg2, arg3, arg4)
+}
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	configmanager "github.com/openshift/managed-upgrade-operator/pkg/configmanager"
+	metrics "github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	notifier "github.com/openshift/managed-upgrade-operator/pkg/notifier"
 	upgradeconfigmanager "github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
 	gomock "go.uber.org/mock/gomock"
@@ -43,16 +44,16 @@ func (m *MockNotifierBuilder) EXPECT() *MockNotifierBuilderMockRecorder {
 }
 
 // New mocks base method.
-func (m *MockNotifierBuilder) New(arg0 client.Client, arg1 configmanager.ConfigManagerBuilder, arg2 upgradeconfigmanager.UpgradeConfigManagerBuilder) (notifier.Notifier, error) {
+func (m *MockNotifierBuilder) New(arg0 client.Client, arg1 configmanager.ConfigManagerBuilder, arg2 upgradeconfigmanager.UpgradeConfigManagerBuilder, arg3 metrics.Metrics) (notifier.Notifier, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(notifier.Notifier)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // New indicates an expected call of New.
-func (mr *MockNotifierBuilderMockRecorder) New(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockNotifierBuilderMockRecorder) New(arg0, arg1, arg2, arg3 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockNotifierBuilder)(nil).New), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockNotifierBuilder)(nil).New), arg0, arg1, arg2, arg3)
 }
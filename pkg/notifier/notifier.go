@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/managed-upgrade-operator/config"
 	"github.com/openshift/managed-upgrade-operator/pkg/configmanager"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
@@ -24,7 +26,7 @@ type Notifier interface {
 //
 //go:generate mockgen -destination=mocks/notifier_builder.go -package=mocks github.com/openshift/managed-upgrade-operator/pkg/notifier NotifierBuilder
 type NotifierBuilder interface {
-	New(client.Client, configmanager.ConfigManagerBuilder, upgradeconfigmanager.UpgradeConfigManagerBuilder) (Notifier, error)
+	New(client.Client, configmanager.ConfigManagerBuilder, upgradeconfigmanager.UpgradeConfigManagerBuilder, metrics.Metrics) (Notifier, error)
 }
 
 // Represents valid notify states that can be reported
@@ -43,25 +45,57 @@ const (
 	MuoStateControlPlaneUpgradeStartedSL  MuoState = "StateControlPlaneStartedSL"
 	MuoStateControlPlaneUpgradeFinishedSL MuoState = "StateControlPlaneFinishedSL"
 	MuoStateWorkerPlaneUpgradeFinishedSL  MuoState = "StateWorkerPlaneFinishedSL"
+	MuoStateCanaryHealthCheckSL           MuoState = "StateCanaryHealthCheckSL"
 )
 
 // MuoState is a type
 type MuoState string
 
+// muoStates lists every state that can be reported
+var muoStates = []MuoState{
+	MuoStatePending,
+	MuoStateStarted,
+	MuoStateCompleted,
+	MuoStateDelayed,
+	MuoStateFailed,
+	MuoStateCancelled,
+	MuoStateScheduled,
+	MuoStateScaleSkipped,
+	MuoStateSkipped,
+	MuoStateHealthCheckSL,
+	MuoStatePreHealthCheckSL,
+	MuoStateControlPlaneUpgradeStartedSL,
+	MuoStateControlPlaneUpgradeFinishedSL,
+	MuoStateWorkerPlaneUpgradeFinishedSL,
+	MuoStateCanaryHealthCheckSL,
+}
+
+// isMuoState returns true if the state can be reported
+func isMuoState(state MuoState) bool {
+	for _, s := range muoStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
 // Errors
 var (
 	ErrNoNotifierConfigured = fmt.Errorf("no valid configured notifier")
 )
 
-// NewBuilder creates a new Notifier instance builder
-func NewBuilder() NotifierBuilder {
-	return &notifierBuilder{}
+// NewBuilder creates a new Notifier instance builder. The recorder records the Events of the EVENTS notifier.
+func NewBuilder(recorder record.EventRecorder) NotifierBuilder {
+	return &notifierBuilder{recorder: recorder}
 }
 
-type notifierBuilder struct{}
+type notifierBuilder struct {
+	recorder record.EventRecorder
+}
 
 // Creates a new Notifier instance
-func (nb *notifierBuilder) New(client client.Client, cfgBuilder configmanager.ConfigManagerBuilder, upgradeConfigManagerBuilder upgradeconfigmanager.UpgradeConfigManagerBuilder) (Notifier, error) {
+func (nb *notifierBuilder) New(client client.Client, cfgBuilder configmanager.ConfigManagerBuilder, upgradeConfigManagerBuilder upgradeconfigmanager.UpgradeConfigManagerBuilder, metricsClient metrics.Metrics) (Notifier, error) {
 	cfg, err := readNotifierConfig(client, cfgBuilder)
 	if err != nil {
 		return nil, err
@@ -80,13 +114,61 @@ func (nb *notifierBuilder) New(client client.Client, cfgBuilder configmanager.Co
 		return nil, err
 	}
 
-	switch strings.ToUpper(cfg.ConfigManager.Source) {
-	case "OCM":
+	messages, err := cfg.GetMessages()
+	if err != nil {
+		return nil, err
+	}
+
+	primary, err := nb.newNotifier(cfg.GetSource(), nil, client, cfgBuilder, upgradeConfigManager, messages, notificationsEnabled)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Notifier.Sinks) == 0 {
+		return primary, nil
+	}
+
+	// A secondary sink which can't be created is left out rather than preventing the notifications of the primary notifier
+	var sinks []*notifierSink
+	for _, sinkCfg := range cfg.Notifier.Sinks {
+		n, err := nb.newNotifier(strings.ToUpper(sinkCfg.Source), &sinkCfg.Webhook, client, cfgBuilder, upgradeConfigManager, messages, notificationsEnabled)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("failed to create notification sink %s", sinkCfg.Name))
+			continue
+		}
+		sinks = append(sinks, newNotifierSink(sinkCfg.Name, n, sinkCfg.States))
+	}
+	return NewFanOutNotifier(primary, sinks, metricsClient, NewLedger(client), upgradeConfigManager), nil
+}
+
+// newNotifier creates the notifier of the source. The webhook config of the notifier section is read
+// for a WEBHOOK source unless a webhook config is supplied.
+func (nb *notifierBuilder) newNotifier(source string, webhook *WebhookConfig, client client.Client, cfgBuilder configmanager.ConfigManagerBuilder, upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager, messages *Messages, notificationsEnabled bool) (Notifier, error) {
+	switch source {
+	case string(OCM):
 		cfg, err := readOcmNotifierConfig(client, cfgBuilder)
 		if err != nil {
 			return nil, err
 		}
-		mgr, err := NewOCMNotifier(client, cfg.GetOCMBaseURL(), upgradeConfigManager, notificationsEnabled)
+		mgr, err := NewOCMNotifier(client, cfg.GetOCMBaseURL(), upgradeConfigManager, messages, notificationsEnabled)
+		if err != nil {
+			return nil, err
+		}
+		return mgr, nil
+	case string(WEBHOOK):
+		if webhook == nil {
+			cfg, err := readWebhookNotifierConfig(client, cfgBuilder)
+			if err != nil {
+				return nil, err
+			}
+			webhook = &cfg.Notifier.Webhook
+		}
+		mgr, err := NewWebhookNotifier(client, *webhook, upgradeConfigManager)
+		if err != nil {
+			return nil, err
+		}
+		return mgr, nil
+	case string(EVENTS):
+		mgr, err := NewEventNotifier(nb.recorder, upgradeConfigManager)
 		if err != nil {
 			return nil, err
 		}
@@ -116,6 +198,15 @@ func readNotifierConfig(client client.Client, cfb configmanager.ConfigManagerBui
 	return cfg, cfg.IsValid()
 }
 
+// ReadMessages reads the notification messages of the configured locale
+func ReadMessages(client client.Client, cfb configmanager.ConfigManagerBuilder) (*Messages, error) {
+	cfg, err := readNotifierConfig(client, cfb)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.GetMessages()
+}
+
 // Read OCM provider configuration
 func readOcmNotifierConfig(client client.Client, cfb configmanager.ConfigManagerBuilder) (*OcmNotifierConfig, error) {
 	cfg := &OcmNotifierConfig{}
@@ -134,6 +225,24 @@ func readOcmNotifierConfig(client client.Client, cfb configmanager.ConfigManager
 	return cfg, cfg.IsValid()
 }
 
+// Read webhook notifier configuration
+func readWebhookNotifierConfig(client client.Client, cfb configmanager.ConfigManagerBuilder) (*WebhookNotifierConfig, error) {
+	cfg := &WebhookNotifierConfig{}
+
+	target := config.CMTarget{}
+	cmTarget, err := target.NewCMTarget()
+	if err != nil {
+		return cfg, err
+	}
+
+	cfm := cfb.New(client, cmTarget)
+	err = cfm.Into(cfg)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.IsValid()
+}
+
 // Read featuregate configuration
 func readOcmFeatureGate(client client.Client, cfb configmanager.ConfigManagerBuilder) (*OcmFeatureConfig, error) {
 	cfg := &OcmFeatureConfig{}
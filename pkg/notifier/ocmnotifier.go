@@ -10,13 +10,14 @@ import (
 	servicelogsv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
 	"github.com/openshift/managed-upgrade-operator/pkg/ocm"
 	"github.com/openshift/managed-upgrade-operator/pkg/ocmagent"
 	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
 )
 
 // NewOCMNotifier returns a ocmNotifier
-func NewOCMNotifier(client client.Client, ocmBaseUrl *url.URL, upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager, isEnabled bool) (*ocmNotifier, error) {
+func NewOCMNotifier(client client.Client, ocmBaseUrl *url.URL, upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager, messages *Messages, isEnabled bool) (*ocmNotifier, error) {
 	var (
 		ocmClient ocm.OcmClient
 		err       error
@@ -35,12 +36,12 @@ func NewOCMNotifier(client client.Client, ocmBaseUrl *url.URL, upgradeConfigMana
 		client:               client,
 		ocmClient:            ocmClient,
 		upgradeConfigManager: upgradeConfigManager,
+		messages:             messages,
 		notifications:        isEnabled,
 	}, nil
 }
 
 const SLserviceName string = "MUO"
-const upgrade_healthcheck_kcs string = "https://access.redhat.com/solutions/7081505"
 
 type OcmState string
 
@@ -67,16 +68,18 @@ var stateMap = map[MuoState]OcmState{
 }
 
 var (
-	// ServiceLogStateControlPlaneStarted defines the summary for control and worker plane upgrade starting servicelog
-	ServiceLogStateControlPlaneStarted = ServiceLogState{Severity: servicelogsv1.SeverityInfo, Summary: "Cluster is starting with control and worker plane upgrade"}
-	// ServiceLogStateControlPlaneFinished defines the summary for control plane upgrade finished servicelog
-	ServiceLogStateControlPlaneFinished = ServiceLogState{Severity: servicelogsv1.SeverityInfo, Summary: "Cluster has finished control plane upgrade"}
-	// ServiceLogStateWorkerPlaneFinished defines the summary for worker plane upgrade finished servicelog
-	ServiceLogStateWorkerPlaneFinished = ServiceLogState{Severity: servicelogsv1.SeverityInfo, Summary: "Cluster has finished with worker plane upgrade"}
-	//ServiceLogStateHealthCheckSL defines the summary for finished cluster healthcheck
-	ServiceLogStateHealthCheckSL = ServiceLogState{Severity: servicelogsv1.SeverityInfo, Summary: "Cluster has encountered healthcheck failure during upgrade"}
-	//ServiceLogStatePreHealthCheckSL defines the summary for finished cluster pre-upgrade healthcheck
-	ServiceLogStatePreHealthCheckSL = ServiceLogState{Severity: servicelogsv1.SeverityInfo, Summary: "Cluster has encountered pre-upgrade healthcheck failure"}
+	// ServiceLogStateControlPlaneStarted defines the control and worker plane upgrade starting servicelog
+	ServiceLogStateControlPlaneStarted = ServiceLogState{Severity: servicelogsv1.SeverityInfo}
+	// ServiceLogStateControlPlaneFinished defines the control plane upgrade finished servicelog
+	ServiceLogStateControlPlaneFinished = ServiceLogState{Severity: servicelogsv1.SeverityInfo}
+	// ServiceLogStateWorkerPlaneFinished defines the worker plane upgrade finished servicelog
+	ServiceLogStateWorkerPlaneFinished = ServiceLogState{Severity: servicelogsv1.SeverityInfo}
+	//ServiceLogStateHealthCheckSL defines the finished cluster healthcheck servicelog
+	ServiceLogStateHealthCheckSL = ServiceLogState{Severity: servicelogsv1.SeverityInfo}
+	//ServiceLogStatePreHealthCheckSL defines the finished cluster pre-upgrade healthcheck servicelog
+	ServiceLogStatePreHealthCheckSL = ServiceLogState{Severity: servicelogsv1.SeverityInfo}
+	//ServiceLogStateCanaryHealthCheckSL defines the failed canary worker node healthcheck servicelog
+	ServiceLogStateCanaryHealthCheckSL = ServiceLogState{Severity: servicelogsv1.SeverityWarning}
 )
 
 // ServiceLogState type defines the ServiceLog metadata
@@ -88,6 +91,17 @@ var serviceLogMap = map[MuoState]ServiceLogState{
 	MuoStateWorkerPlaneUpgradeFinishedSL:  ServiceLogStateWorkerPlaneFinished,
 	MuoStateHealthCheckSL:                 ServiceLogStateHealthCheckSL,
 	MuoStatePreHealthCheckSL:              ServiceLogStatePreHealthCheckSL,
+	MuoStateCanaryHealthCheckSL:           ServiceLogStateCanaryHealthCheckSL,
+}
+
+// serviceLogSummaryMap holds the message of the summary of every servicelog
+var serviceLogSummaryMap = map[MuoState]MessageID{
+	MuoStateControlPlaneUpgradeStartedSL:  MessageControlPlaneStartedSummary,
+	MuoStateControlPlaneUpgradeFinishedSL: MessageControlPlaneFinishedSummary,
+	MuoStateWorkerPlaneUpgradeFinishedSL:  MessageWorkerPlaneFinishedSummary,
+	MuoStateHealthCheckSL:                 MessageHealthCheckSummary,
+	MuoStatePreHealthCheckSL:              MessagePreHealthCheckSummary,
+	MuoStateCanaryHealthCheckSL:           MessageCanaryHealthCheckFailedSummary,
 }
 
 type ocmNotifier struct {
@@ -97,6 +111,8 @@ type ocmNotifier struct {
 	ocmClient ocm.OcmClient
 	// Retrieves the upgrade config from the cluster
 	upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager
+	// Renders the servicelog summaries
+	messages *Messages
 	// If the SendSerivceLogNotifications feature is enabled or not
 	notifications bool
 }
@@ -108,6 +124,11 @@ func (s *ocmNotifier) NotifyState(state MuoState, description string) error {
 		return fmt.Errorf("failed to retrieve internal ocm cluster ID: %v", err)
 	}
 
+	uc, err := s.upgradeConfigManager.Get()
+	if err != nil {
+		return fmt.Errorf("can't read upgradeconfig for notification: %v", err)
+	}
+
 	// Enable sending the servicelog notifications only if the featuregate is enabled
 	if s.notifications {
 		if strings.HasSuffix(toString(state), "SL") {
@@ -115,8 +136,10 @@ func (s *ocmNotifier) NotifyState(state MuoState, description string) error {
 			if !ok {
 				return fmt.Errorf("failed to map the servicelog state for MUO state %s", state)
 			}
-			if state == MuoStateHealthCheckSL || state == MuoStatePreHealthCheckSL {
-				slState.DocReferences = upgrade_healthcheck_kcs
+			summary := serviceLogSummaryMap[state]
+			slState.Summary = s.messages.Render(summary, NewMessageContext(uc))
+			if kcs, ok := messageKCS[summary]; ok {
+				slState.DocReferences = kcs
 			}
 			err = s.ocmClient.PostServiceLog((*ocm.ServiceLog)(&slState), description)
 			if err != nil {
@@ -125,40 +148,19 @@ func (s *ocmNotifier) NotifyState(state MuoState, description string) error {
 		}
 	}
 
-	policyId, err := s.getPolicyIdForUpgradeConfig(cluster.ID())
-	if err != nil {
-		return fmt.Errorf("can't determine policy ID to notify for: %v", err)
+	// Only the states of the upgrade are reflected in its policy. Whether the upgrade can transition
+	// to the state is decided before it is notified, from the states MUO has notified.
+	ocmState, ok := stateMap[state]
+	if !ok {
+		return nil
 	}
 
-	currentState, err := s.ocmClient.GetClusterUpgradePolicyState(*policyId, cluster.ID())
+	policyId, err := s.getPolicyIdForUpgradeConfig(cluster.ID(), uc)
 	if err != nil {
-		return fmt.Errorf("can't determine policy state: %v", err)
-	}
-
-	var muoCurrent MuoState
-	// Return the MuoState from the current OcmState, determine if MUO is "skipped" or "delayed" it is OCM "deleyed"
-	if OcmState(currentState.Value()) == OcmStateDelayed {
-		if strings.Contains(currentState.Description(), "retry") {
-			muoCurrent = MuoStateDelayed
-		} else {
-			muoCurrent = MuoStateSkipped
-		}
-	} else {
-		mstate, ok := mapState(OcmState(currentState.Value()), stateMap)
-		if !ok {
-			return fmt.Errorf("failed to convert OCM state")
-		}
-		muoCurrent = mstate
-	}
-
-	// Don't notify if the state is already at the same value
-	// Only notify if it's a valid transition
-	shouldNotify := validateStateTransition(muoCurrent, state)
-	if !shouldNotify {
-		return nil
+		return fmt.Errorf("can't determine policy ID to notify for: %v", err)
 	}
 
-	err = s.ocmClient.SetState(string(stateMap[state]), description, *policyId, cluster.ID())
+	err = s.ocmClient.SetState(string(ocmState), description, *policyId, cluster.ID())
 	if err != nil {
 		return fmt.Errorf("can't send notification: %v", err)
 	}
@@ -166,13 +168,7 @@ func (s *ocmNotifier) NotifyState(state MuoState, description string) error {
 }
 
 // Determines the Cluster Services Upgrade Policy ID corresponding to the UpgradeConfig
-func (s *ocmNotifier) getPolicyIdForUpgradeConfig(clusterId string) (*string, error) {
-	// Get current UC
-	uc, err := s.upgradeConfigManager.Get()
-	if err != nil {
-		return nil, err
-	}
-
+func (s *ocmNotifier) getPolicyIdForUpgradeConfig(clusterId string, uc *upgradev1alpha1.UpgradeConfig) (*string, error) {
 	// Get current policies
 	policies, err := s.ocmClient.GetClusterUpgradePolicies(clusterId)
 	if err != nil {
@@ -200,99 +196,6 @@ func (s *ocmNotifier) getPolicyIdForUpgradeConfig(clusterId string) (*string, er
 	return &policyId, nil
 }
 
-// Validates that a state transition can be made from the supplied from/to states
-func validateStateTransition(from MuoState, to MuoState) bool {
-
-	switch from {
-	case MuoStatePending:
-		// We shouldn't even be in this state to transition from
-		return false
-	case MuoStateScheduled:
-		// Can only go to started state
-		switch to {
-		case MuoStateStarted:
-			return true
-		default:
-			return false
-		}
-
-	case MuoStateStarted:
-		// Can go to a scale skipped, healthCheck, delayed, completed or failed state
-		switch to {
-		case MuoStateScaleSkipped:
-			return true
-		case MuoStateDelayed:
-			return true
-		case MuoStateCompleted:
-			return true
-		case MuoStateFailed:
-			return true
-		default:
-			return false
-		}
-
-	case MuoStateScaleSkipped:
-		// can go to skipped, delayed, completed or failed state
-		switch to {
-		case MuoStateDelayed:
-			return true
-		case MuoStateFailed:
-			return true
-		case MuoStateSkipped:
-			return true
-		case MuoStateCompleted:
-			return true
-		default:
-			return false
-		}
-
-	case MuoStateDelayed:
-		// can go to completed or failed or skipped state
-		switch to {
-		case MuoStateCompleted:
-			return true
-		case MuoStateFailed:
-			return true
-		case MuoStateSkipped:
-			return true
-		default:
-			return false
-		}
-
-	case MuoStateSkipped:
-		// can go to completed or failed state
-		switch to {
-		case MuoStateCompleted:
-			return true
-		case MuoStateFailed:
-			return true
-		default:
-			return false
-		}
-
-	case MuoStateCompleted:
-		// can't go anywhere
-		return false
-	case MuoStateFailed:
-		// can't go anywhere
-		return false
-	default:
-		return false
-	}
-}
-
-// return the MuoState from the given OcmState
-func mapState(os OcmState, dict map[MuoState]OcmState) (ms MuoState, ok bool) {
-	for k, v := range dict {
-		if v == os {
-			ms = k
-			ok = true
-			return ms, ok
-		}
-	}
-	return "", false
-}
-
 func toString(s MuoState) string {
 	return string(s)
 }
@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"fmt"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+// InitialState is the state of an upgrade no state has been notified for yet
+const InitialState = MuoStateScheduled
+
+// stateTransitions holds the states each state of an upgrade can transition to. States which are not
+// part of it, such as the ServiceLog states, are notified regardless of the state of the upgrade.
+var stateTransitions = map[MuoState][]MuoState{
+	MuoStateScheduled:    {MuoStateStarted},
+	MuoStateStarted:      {MuoStateScaleSkipped, MuoStateDelayed, MuoStateCompleted, MuoStateFailed},
+	MuoStateScaleSkipped: {MuoStateDelayed, MuoStateSkipped, MuoStateCompleted, MuoStateFailed},
+	MuoStateDelayed:      {MuoStateSkipped, MuoStateCompleted, MuoStateFailed},
+	MuoStateSkipped:      {MuoStateCompleted, MuoStateFailed},
+	MuoStateCompleted:    {},
+	MuoStateFailed:       {},
+
+	// The pending and cancelled states are set in OCM, no state transitions to or from them
+	MuoStatePending:   {},
+	MuoStateCancelled: {},
+}
+
+// StateTransitions returns the states each state of an upgrade can transition to
+func StateTransitions() map[MuoState][]MuoState {
+	transitions := make(map[MuoState][]MuoState, len(stateTransitions))
+	for from, to := range stateTransitions {
+		transitions[from] = append([]MuoState{}, to...)
+	}
+	return transitions
+}
+
+// IsTransitionState returns true if the state is a state of the upgrade, whose notification depends on
+// the state notified before it
+func IsTransitionState(state MuoState) bool {
+	_, ok := stateTransitions[state]
+	return ok
+}
+
+// IsValidTransition returns true if an upgrade in the from state can transition to the to state
+func IsValidTransition(from MuoState, to MuoState) bool {
+	for _, s := range stateTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinalState returns true if the state ends an upgrade: some state transitions to it and it
+// transitions to no state
+func isFinalState(state MuoState) bool {
+	if len(stateTransitions[state]) > 0 {
+		return false
+	}
+	for _, to := range stateTransitions {
+		for _, s := range to {
+			if s == state {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isReachable returns true if an upgrade in the from state can reach the to state through one or
+// more transitions
+func isReachable(from MuoState, to MuoState) bool {
+	for _, s := range stateTransitions[from] {
+		if s == to || isReachable(s, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// stateFromUpgradeConfig returns the state of an upgrade nothing has been notified for in the ledger,
+// as recorded in the UpgradeConfig's history: the started state once the start of the upgrade has been
+// notified, the initial state otherwise
+func stateFromUpgradeConfig(uc *upgradev1alpha1.UpgradeConfig) MuoState {
+	history := uc.Status.History.GetHistory(uc.Spec.Desired.Version)
+	if history != nil && history.Conditions.IsTrueFor(upgradev1alpha1.SendStartedNotification) {
+		return MuoStateStarted
+	}
+	return InitialState
+}
+
+// ShouldNotify returns true if the state has not been notified for the upgrade yet and, if it is a
+// state of the upgrade, the upgrade can transition to it from the last state notified.
+// An upgrade nothing has been notified for, for example as the ledger was lost while the operator was
+// redeployed, takes its state from the UpgradeConfig. As its earlier notifications may be missing, it
+// is notified of every state it can reach from there, and its final states are always notified.
+func ShouldNotify(ledger Ledger, uc *upgradev1alpha1.UpgradeConfig, state MuoState) (bool, error) {
+	notified, err := ledger.IsNotified(uc, state)
+	if err != nil || notified {
+		return false, err
+	}
+	if !IsTransitionState(state) {
+		return true, nil
+	}
+	last, err := ledger.LastState(uc)
+	if err != nil {
+		return false, err
+	}
+	if last == "" {
+		if isFinalState(state) {
+			return true, nil
+		}
+		last = stateFromUpgradeConfig(uc)
+		if last != InitialState {
+			if !isReachable(last, state) {
+				log.Info(fmt.Sprintf("Not notifying state %s as the upgrade can't reach it from state %s recorded in the UpgradeConfig", state, last))
+				return false, nil
+			}
+			return true, nil
+		}
+	}
+	if !IsValidTransition(last, state) {
+		log.Info(fmt.Sprintf("Not notifying state %s as the upgrade can't transition to it from state %s", state, last))
+		return false, nil
+	}
+	return true, nil
+}
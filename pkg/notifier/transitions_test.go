@@ -0,0 +1,210 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+// fakeLedger is a Ledger recording the notified states and the deliveries to sinks in memory
+type fakeLedger struct {
+	notified []MuoState
+	sinks    []LedgerEntry
+	err      error
+	mutex    sync.Mutex
+}
+
+func (l *fakeLedger) IsNotified(_ *upgradev1alpha1.UpgradeConfig, state MuoState) (bool, error) {
+	for _, s := range l.notified {
+		if s == state {
+			return true, l.err
+		}
+	}
+	return false, l.err
+}
+
+func (l *fakeLedger) LastState(_ *upgradev1alpha1.UpgradeConfig) (MuoState, error) {
+	for i := len(l.notified) - 1; i >= 0; i-- {
+		if IsTransitionState(l.notified[i]) {
+			return l.notified[i], l.err
+		}
+	}
+	return "", l.err
+}
+
+func (l *fakeLedger) Record(_ *upgradev1alpha1.UpgradeConfig, state MuoState, sendErr error) error {
+	if sendErr == nil {
+		l.notified = append(l.notified, state)
+	}
+	return l.err
+}
+
+func (l *fakeLedger) FailedSinkDeliveries(_ *upgradev1alpha1.UpgradeConfig) ([]LedgerEntry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	failed := []LedgerEntry{}
+	for _, entry := range l.sinks {
+		if entry.Result == DeliveryFailed {
+			failed = append(failed, entry)
+		}
+	}
+	return failed, l.err
+}
+
+func (l *fakeLedger) RecordSink(_ *upgradev1alpha1.UpgradeConfig, state MuoState, sink string, description string, sendErr error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	result := DeliverySent
+	if sendErr != nil {
+		result = DeliveryFailed
+	}
+	for i := range l.sinks {
+		if l.sinks[i].State == state && l.sinks[i].Sink == sink {
+			l.sinks[i].Result = result
+			l.sinks[i].Attempts++
+			l.sinks[i].Timestamp = metav1.Now()
+			return l.err
+		}
+	}
+	l.sinks = append(l.sinks, LedgerEntry{State: state, Sink: sink, Description: description, Result: result, Attempts: 1, Timestamp: metav1.Now()})
+	return l.err
+}
+
+var _ = Describe("Notification state transitions", func() {
+	// expected lists every transition of the upgrade's states, any other transition is invalid
+	expected := map[MuoState][]MuoState{
+		MuoStateScheduled:    {MuoStateStarted},
+		MuoStateStarted:      {MuoStateScaleSkipped, MuoStateDelayed, MuoStateCompleted, MuoStateFailed},
+		MuoStateScaleSkipped: {MuoStateDelayed, MuoStateSkipped, MuoStateCompleted, MuoStateFailed},
+		MuoStateDelayed:      {MuoStateSkipped, MuoStateCompleted, MuoStateFailed},
+		MuoStateSkipped:      {MuoStateCompleted, MuoStateFailed},
+	}
+	serviceLogStates := []MuoState{
+		MuoStateHealthCheckSL,
+		MuoStatePreHealthCheckSL,
+		MuoStateControlPlaneUpgradeStartedSL,
+		MuoStateControlPlaneUpgradeFinishedSL,
+		MuoStateWorkerPlaneUpgradeFinishedSL,
+		MuoStateCanaryHealthCheckSL,
+	}
+
+	It("allows exactly the expected transitions", func() {
+		for _, from := range muoStates {
+			for _, to := range muoStates {
+				Expect(IsValidTransition(from, to)).To(Equal(containsState(expected[from], to)), fmt.Sprintf("transition from %s to %s", from, to))
+			}
+		}
+	})
+
+	It("covers every state except the ServiceLog states", func() {
+		for _, state := range muoStates {
+			Expect(IsTransitionState(state)).To(Equal(!containsState(serviceLogStates, state)), string(state))
+		}
+	})
+
+	It("has no cycles, so every upgrade ends in a final state", func() {
+		var visit func(state MuoState, path []MuoState)
+		visit = func(state MuoState, path []MuoState) {
+			Expect(path).NotTo(ContainElement(state), fmt.Sprintf("cycle %v", append(path, state)))
+			for _, next := range StateTransitions()[state] {
+				visit(next, append(path, state))
+			}
+		}
+		visit(InitialState, nil)
+	})
+
+	It("returns a copy of the transitions", func() {
+		transitions := StateTransitions()
+		transitions[MuoStateCompleted] = append(transitions[MuoStateCompleted], MuoStateStarted)
+		Expect(IsValidTransition(MuoStateCompleted, MuoStateStarted)).To(BeFalse())
+	})
+
+	Context("Deciding whether to notify a state", func() {
+		var (
+			ledger *fakeLedger
+			uc     *upgradev1alpha1.UpgradeConfig
+		)
+
+		BeforeEach(func() {
+			ledger = &fakeLedger{}
+			uc = testStructs.NewUpgradeConfigBuilder().GetUpgradeConfig()
+		})
+
+		It("notifies the start of an upgrade nothing was notified for", func() {
+			Expect(ShouldNotify(ledger, uc, MuoStateStarted)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateSkipped)).To(BeFalse())
+		})
+
+		It("notifies the end of an upgrade nothing was notified for", func() {
+			Expect(ShouldNotify(ledger, uc, MuoStateCompleted)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateFailed)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateCancelled)).To(BeFalse())
+		})
+
+		It("notifies the states an upgrade whose start was notified can reach when its ledger is empty", func() {
+			uc = testStructs.NewUpgradeConfigBuilder().WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			history := uc.Status.History.GetHistory(uc.Spec.Desired.Version)
+			Expect(ShouldNotify(ledger, uc, MuoStateStarted)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateDelayed)).To(BeFalse())
+
+			history.Conditions.SetCondition(upgradev1alpha1.UpgradeCondition{Type: upgradev1alpha1.SendStartedNotification, Status: corev1.ConditionTrue})
+			uc.Status.History.SetHistory(*history)
+			Expect(ShouldNotify(ledger, uc, MuoStateStarted)).To(BeFalse())
+			Expect(ShouldNotify(ledger, uc, MuoStateScaleSkipped)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateDelayed)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateSkipped)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateCompleted)).To(BeTrue())
+		})
+
+		It("does not notify the end of an upgrade once it has ended", func() {
+			ledger.notified = []MuoState{MuoStateStarted, MuoStateFailed}
+			Expect(ShouldNotify(ledger, uc, MuoStateCompleted)).To(BeFalse())
+		})
+
+		It("notifies the states the upgrade can transition to from the last notified state", func() {
+			ledger.notified = []MuoState{MuoStateStarted, MuoStateDelayed}
+			Expect(ShouldNotify(ledger, uc, MuoStateSkipped)).To(BeTrue())
+			Expect(ShouldNotify(ledger, uc, MuoStateScaleSkipped)).To(BeFalse())
+		})
+
+		It("tells a delayed upgrade from a skipped one", func() {
+			ledger.notified = []MuoState{MuoStateStarted, MuoStateScaleSkipped, MuoStateSkipped}
+			Expect(ShouldNotify(ledger, uc, MuoStateDelayed)).To(BeFalse())
+			Expect(ShouldNotify(ledger, uc, MuoStateCompleted)).To(BeTrue())
+		})
+
+		It("does not notify a state twice", func() {
+			ledger.notified = []MuoState{MuoStateStarted}
+			Expect(ShouldNotify(ledger, uc, MuoStateStarted)).To(BeFalse())
+		})
+
+		It("notifies the ServiceLog states regardless of the upgrade's state", func() {
+			ledger.notified = []MuoState{MuoStateStarted, MuoStateCompleted}
+			for _, state := range serviceLogStates {
+				Expect(ShouldNotify(ledger, uc, state)).To(BeTrue(), string(state))
+			}
+		})
+
+		It("fails if the ledger can't be read", func() {
+			ledger.err = fmt.Errorf("fake error")
+			_, err := ShouldNotify(ledger, uc, MuoStateStarted)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func containsState(states []MuoState, state MuoState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
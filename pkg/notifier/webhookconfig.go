@@ -0,0 +1,84 @@
+package notifier
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// defaultWebhookTimeout is the timeout of a webhook request when none is configured
+	defaultWebhookTimeout = 10 * time.Second
+	// defaultWebhookRetryInterval is the delay before the first retry of a webhook request when none is configured
+	defaultWebhookRetryInterval = 1 * time.Second
+	// maxWebhookRetryInterval caps the delay between the retries of a webhook request
+	maxWebhookRetryInterval = 30 * time.Second
+)
+
+// WebhookNotifierConfig holds the Notifier field for the webhook notifier configuration
+type WebhookNotifierConfig struct {
+	Notifier WebhookNotifierSection `yaml:"notifier"`
+}
+
+// WebhookNotifierSection holds the Webhook field of the notifier configuration
+type WebhookNotifierSection struct {
+	Webhook WebhookConfig `yaml:"webhook"`
+}
+
+// WebhookConfig describes the endpoint upgrade state notifications are sent to
+type WebhookConfig struct {
+	// URL is the http(s) endpoint the notifications are posted to
+	URL string `yaml:"url"`
+	// Headers are added to every request
+	Headers map[string]string `yaml:"headers"`
+	// SecretName names a Secret in the operator namespace holding the credentials of the webhook
+	SecretName string `yaml:"secretName"`
+	// Timeout of a single request, in seconds
+	Timeout int `yaml:"timeout"`
+	// Retries is the number of times a failed request is retried
+	Retries int `yaml:"retries"`
+	// RetryInterval is the delay before the first retry in seconds, doubled for every further retry
+	RetryInterval int `yaml:"retryInterval"`
+}
+
+// IsValid returns a nil error when the WebhookNotifierConfig is valid
+func (cfg *WebhookNotifierConfig) IsValid() error {
+	if err := cfg.Notifier.Webhook.IsValid(); err != nil {
+		return fmt.Errorf("config notifier webhook is invalid: %v", err)
+	}
+	return nil
+}
+
+// IsValid returns a nil error when the WebhookConfig is valid
+func (wh *WebhookConfig) IsValid() error {
+	u, err := url.Parse(wh.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if wh.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if wh.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	if wh.RetryInterval < 0 {
+		return fmt.Errorf("retryInterval must not be negative")
+	}
+	return nil
+}
+
+// GetTimeoutDuration returns the timeout of a single webhook request
+func (wh *WebhookConfig) GetTimeoutDuration() time.Duration {
+	if wh.Timeout == 0 {
+		return defaultWebhookTimeout
+	}
+	return time.Duration(wh.Timeout) * time.Second
+}
+
+// GetRetryIntervalDuration returns the delay before the first retry of a webhook request
+func (wh *WebhookConfig) GetRetryIntervalDuration() time.Duration {
+	if wh.RetryInterval == 0 {
+		return defaultWebhookRetryInterval
+	}
+	return time.Duration(wh.RetryInterval) * time.Second
+}
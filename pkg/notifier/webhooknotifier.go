@@ -0,0 +1,181 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jpillora/backoff"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/config"
+	cv "github.com/openshift/managed-upgrade-operator/pkg/clusterversion"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
+	"github.com/openshift/managed-upgrade-operator/util"
+)
+
+const (
+	// WebhookSecretTokenKey is the key of the webhook Secret holding a bearer token sent in the Authorization header
+	WebhookSecretTokenKey = "token" //#nosec G101 -- This is a key name, not a credential
+	// WebhookSecretHMACKey is the key of the webhook Secret holding the key the payload is signed with
+	WebhookSecretHMACKey = "hmacKey" //#nosec G101 -- This is a key name, not a credential
+	// WebhookSignatureHeader carries the hex encoded HMAC-SHA256 signature of the payload, prefixed with "sha256="
+	WebhookSignatureHeader = "X-MUO-Signature"
+)
+
+// WebhookPayload is the JSON body posted to the webhook for every notified state
+type WebhookPayload struct {
+	State       MuoState                   `json:"state"`
+	Description string                     `json:"description"`
+	Version     string                     `json:"version,omitempty"`
+	ClusterID   string                     `json:"clusterId"`
+	Conditions  upgradev1alpha1.Conditions `json:"conditions,omitempty"`
+	Timestamp   time.Time                  `json:"timestamp"`
+}
+
+// NewWebhookNotifier returns a webhookNotifier
+func NewWebhookNotifier(client client.Client, cfg WebhookConfig, upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager) (*webhookNotifier, error) {
+	return &webhookNotifier{
+		client:               client,
+		cfg:                  cfg,
+		httpClient:           &http.Client{Timeout: cfg.GetTimeoutDuration()},
+		upgradeConfigManager: upgradeConfigManager,
+		cvClient:             cv.NewCVClient(client),
+		sleep:                time.Sleep,
+	}, nil
+}
+
+// A notifier that posts every state to a configured webhook
+type webhookNotifier struct {
+	// Cluster k8s client
+	client client.Client
+	// Webhook configuration
+	cfg WebhookConfig
+	// Client sending the webhook requests
+	httpClient *http.Client
+	// Retrieves the upgrade config from the cluster
+	upgradeConfigManager upgradeconfigmanager.UpgradeConfigManager
+	// Retrieves the cluster ID
+	cvClient cv.ClusterVersion
+	// Waits between retries
+	sleep func(time.Duration)
+}
+
+func (s *webhookNotifier) NotifyState(state MuoState, description string) error {
+	payload, err := s.payload(state, description)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %v", err)
+	}
+	secret, err := s.secret()
+	if err != nil {
+		return err
+	}
+
+	b := &backoff.Backoff{
+		Min:    s.cfg.GetRetryIntervalDuration(),
+		Max:    maxWebhookRetryInterval,
+		Factor: 2,
+	}
+	for attempt := 0; ; attempt++ {
+		retryable, err := s.post(body, secret)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= s.cfg.Retries {
+			return fmt.Errorf("can't send webhook notification: %v", err)
+		}
+		wait := b.Duration()
+		log.Info(fmt.Sprintf("Webhook notification of state %s failed, retrying in %s: %v", state, wait, err))
+		s.sleep(wait)
+	}
+}
+
+// payload returns the payload describing the state of the current upgrade
+func (s *webhookNotifier) payload(state MuoState, description string) (*WebhookPayload, error) {
+	uc, err := s.upgradeConfigManager.Get()
+	if err != nil {
+		return nil, fmt.Errorf("can't read upgradeconfig for webhook notification: %v", err)
+	}
+	payload := &WebhookPayload{
+		State:       state,
+		Description: description,
+		Version:     uc.Spec.Desired.Version,
+		ClusterID:   s.cvClient.GetClusterId(),
+		Timestamp:   time.Now().UTC(),
+	}
+	if history := uc.Status.History.GetHistory(uc.Spec.Desired.Version); history != nil {
+		payload.Conditions = history.Conditions
+	}
+	return payload, nil
+}
+
+// secret returns the credentials of the webhook, or nil if none are configured
+func (s *webhookNotifier) secret() (*corev1.Secret, error) {
+	if s.cfg.SecretName == "" {
+		return nil, nil
+	}
+	ns, err := util.GetOperatorNamespace()
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	err = s.client.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: s.cfg.SecretName}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("can't read webhook secret %s: %v", s.cfg.SecretName, err)
+	}
+	return secret, nil
+}
+
+// post sends the body to the webhook once, returning whether a failed request may be retried
+func (s *webhookNotifier) post(body []byte, secret *corev1.Secret) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to build webhook request: %v", err)
+	}
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("User-Agent", config.SetUserAgent())
+	req.Header.Set("Content-Type", "application/json")
+	if secret != nil {
+		if token, ok := secret.Data[WebhookSecretTokenKey]; ok {
+			req.Header.Set("Authorization", "Bearer "+string(token))
+		}
+		if key, ok := secret.Data[WebhookSecretHMACKey]; ok {
+			req.Header.Set(WebhookSignatureHeader, "sha256="+sign(key, body))
+		}
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("request to webhook received response code %d", resp.StatusCode)
+}
+
+// sign returns the hex encoded HMAC-SHA256 signature of the body
+func sign(key []byte, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
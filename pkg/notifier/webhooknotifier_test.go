@@ -0,0 +1,170 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	cvMocks "github.com/openshift/managed-upgrade-operator/pkg/clusterversion/mocks"
+	ucMocks "github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager/mocks"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+)
+
+var _ = Describe("Webhook Notifier", func() {
+	var (
+		mockCtrl                 *gomock.Controller
+		mockKubeClient           *mocks.MockClient
+		mockUpgradeConfigManager *ucMocks.MockUpgradeConfigManager
+		mockCVClient             *cvMocks.MockClusterVersion
+		upgradeConfig            *upgradev1alpha1.UpgradeConfig
+		server                   *httptest.Server
+		requests                 []*http.Request
+		bodies                   [][]byte
+		responses                []int
+		waits                    []time.Duration
+		notifier                 *webhookNotifier
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockUpgradeConfigManager = ucMocks.NewMockUpgradeConfigManager(mockCtrl)
+		mockCVClient = cvMocks.NewMockClusterVersion(mockCtrl)
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(types.NamespacedName{Name: "test", Namespace: "test-namespace"}).WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+		upgradeConfig.Status.History[0].Conditions = upgradev1alpha1.NewConditions(upgradev1alpha1.UpgradeCondition{
+			Type:   upgradev1alpha1.UpgradePreHealthCheck,
+			Status: corev1.ConditionTrue,
+		})
+		requests = nil
+		bodies = nil
+		responses = nil
+		waits = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, body)
+			status := http.StatusNoContent
+			if len(responses) > 0 {
+				status = responses[0]
+				responses = responses[1:]
+			}
+			w.WriteHeader(status)
+		}))
+		notifier = &webhookNotifier{
+			client:               mockKubeClient,
+			cfg:                  WebhookConfig{URL: server.URL, Headers: map[string]string{"X-Team": "sre"}, Retries: 2},
+			httpClient:           server.Client(),
+			upgradeConfigManager: mockUpgradeConfigManager,
+			cvClient:             mockCVClient,
+			sleep:                func(d time.Duration) { waits = append(waits, d) },
+		}
+		_ = os.Setenv("OPERATOR_NAMESPACE", "test-namespace")
+	})
+
+	AfterEach(func() {
+		server.Close()
+		mockCtrl.Finish()
+	})
+
+	expectPayload := func() {
+		mockUpgradeConfigManager.EXPECT().Get().Return(upgradeConfig, nil)
+		mockCVClient.EXPECT().GetClusterId().Return("cluster-id")
+	}
+
+	It("posts the state of the upgrade", func() {
+		expectPayload()
+		Expect(notifier.NotifyState(MuoStateStarted, "upgrade started")).To(Succeed())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(requests[0].Header.Get("X-Team")).To(Equal("sre"))
+		Expect(requests[0].Header.Get("Authorization")).To(BeEmpty())
+		Expect(requests[0].Header.Get(WebhookSignatureHeader)).To(BeEmpty())
+
+		payload := WebhookPayload{}
+		Expect(json.Unmarshal(bodies[0], &payload)).To(Succeed())
+		Expect(payload.State).To(Equal(MuoStateStarted))
+		Expect(payload.Description).To(Equal("upgrade started"))
+		Expect(payload.Version).To(Equal(upgradeConfig.Spec.Desired.Version))
+		Expect(payload.ClusterID).To(Equal("cluster-id"))
+		Expect(payload.Conditions).To(HaveLen(1))
+		Expect(payload.Conditions[0].Type).To(Equal(upgradev1alpha1.UpgradePreHealthCheck))
+	})
+
+	It("authenticates and signs the request with the webhook secret", func() {
+		notifier.cfg.SecretName = "webhook"
+		expectPayload()
+		mockKubeClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: "test-namespace", Name: "webhook"}, gomock.Any()).SetArg(2, corev1.Secret{
+			Data: map[string][]byte{
+				WebhookSecretTokenKey: []byte("token"),
+				WebhookSecretHMACKey:  []byte("key"),
+			},
+		})
+		Expect(notifier.NotifyState(MuoStateCompleted, "upgrade completed")).To(Succeed())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer token"))
+		Expect(requests[0].Header.Get(WebhookSignatureHeader)).To(Equal("sha256=" + sign([]byte("key"), bodies[0])))
+	})
+
+	It("fails if the webhook secret cannot be read", func() {
+		notifier.cfg.SecretName = "webhook"
+		expectPayload()
+		mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+		Expect(notifier.NotifyState(MuoStateCompleted, "upgrade completed")).NotTo(Succeed())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("retries failed requests with backoff", func() {
+		responses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+		expectPayload()
+		Expect(notifier.NotifyState(MuoStateDelayed, "upgrade delayed")).To(Succeed())
+		Expect(requests).To(HaveLen(3))
+		Expect(waits).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+	})
+
+	It("gives up once the retries are exhausted", func() {
+		responses = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+		expectPayload()
+		Expect(notifier.NotifyState(MuoStateFailed, "upgrade failed")).NotTo(Succeed())
+		Expect(requests).To(HaveLen(3))
+	})
+
+	It("does not retry requests the webhook rejects", func() {
+		responses = []int{http.StatusUnauthorized}
+		expectPayload()
+		Expect(notifier.NotifyState(MuoStateFailed, "upgrade failed")).NotTo(Succeed())
+		Expect(requests).To(HaveLen(1))
+		Expect(waits).To(BeEmpty())
+	})
+
+	Context("Webhook config", func() {
+		It("accepts an https URL", func() {
+			cfg := WebhookNotifierConfig{Notifier: WebhookNotifierSection{Webhook: WebhookConfig{URL: "https://hooks.example.com/muo"}}}
+			Expect(cfg.IsValid()).To(Succeed())
+			Expect(cfg.Notifier.Webhook.GetTimeoutDuration()).To(Equal(defaultWebhookTimeout))
+			Expect(cfg.Notifier.Webhook.GetRetryIntervalDuration()).To(Equal(defaultWebhookRetryInterval))
+		})
+		It("rejects a missing or non-http URL", func() {
+			cfg := WebhookNotifierConfig{}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+			cfg.Notifier.Webhook.URL = "ftp://hooks.example.com"
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+		It("rejects negative retries", func() {
+			cfg := WebhookNotifierConfig{Notifier: WebhookNotifierSection{Webhook: WebhookConfig{URL: "https://hooks.example.com", Retries: -1}}}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-multierror"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/fields"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -38,12 +39,15 @@ func FilterPods(podList *corev1.PodList, predicates ...PodPredicate) *corev1.Pod
 type DeleteResult struct {
 	Message              string
 	NumMarkedForDeletion int
+	// Pods holds the namespaced names of the pods marked for deletion
+	Pods []string
 }
 
 // DeletePods attempts to delete a given PodList and returns a DeleteResult and error
 func DeletePods(c client.Client, logger logr.Logger, pl *corev1.PodList, ignoreAlreadyDeleting bool, options ...client.DeleteOption) (*DeleteResult, error) {
 	me := &multierror.Error{}
 	var podsMarkedForDeletion []string
+	var pods []string
 	for _, p := range pl.Items {
 		p := p
 		if !ignoreAlreadyDeleting || p.DeletionTimestamp == nil {
@@ -54,6 +58,7 @@ func DeletePods(c client.Client, logger logr.Logger, pl *corev1.PodList, ignoreA
 				me = multierror.Append(err, me)
 			} else {
 				podsMarkedForDeletion = append(podsMarkedForDeletion, p.Name)
+				pods = append(pods, p.Namespace+"/"+p.Name)
 			}
 		} else {
 			logger.Info(fmt.Sprintf("Ignoring deleting pod %v because it is already being deleted", p.Name))
@@ -63,39 +68,77 @@ func DeletePods(c client.Client, logger logr.Logger, pl *corev1.PodList, ignoreA
 	return &DeleteResult{
 		Message:              fmt.Sprintf("Pod(s) %s have been marked for deletion", strings.Join(podsMarkedForDeletion, ",")),
 		NumMarkedForDeletion: len(podsMarkedForDeletion),
+		Pods:                 pods,
 	}, me.ErrorOrNil()
 }
 
+// FinalizerPredicate is a predicate function for a given finalizer
+type FinalizerPredicate func(string) bool
+
 // RemoveFinalizersResult is a type that describes the result of removing a finalizer
 type RemoveFinalizersResult struct {
 	Message    string
 	NumRemoved int
+	// Pods holds the namespaced names of the pods whose finalizers were removed
+	Pods []string
+	// Skipped holds the namespaced names of the pods none of whose finalizers may be removed
+	Skipped []string
 }
 
-// RemoveFinalizersFromPod attempts to remove the finalizers from a given PodList and returns a RemoveFinalizersResult and error
-func RemoveFinalizersFromPod(c client.Client, logger logr.Logger, pl *corev1.PodList) (*RemoveFinalizersResult, error) {
+// RemoveFinalizersFromPod attempts to remove the finalizers matching the predicate from a given PodList
+// and returns a RemoveFinalizersResult and error. All finalizers are removed if the predicate is nil.
+// Pods are patched on the condition their resourceVersion is unchanged, a pod modified since it was
+// listed is left for a later attempt.
+func RemoveFinalizersFromPod(c client.Client, logger logr.Logger, pl *corev1.PodList, removable FinalizerPredicate) (*RemoveFinalizersResult, error) {
 	var podsWithFinalizersRemoved []string
+	var podsSkipped []string
+	var pods []string
+	var skipped []string
 	me := &multierror.Error{}
 	for _, p := range pl.Items {
 		p := p
-		if len(p.GetFinalizers()) != 0 {
-			logger.Info(fmt.Sprintf("Applying remove finalizer strategy to pod %v/%v", p.Namespace, p.Name))
-			emptyFinalizer := make([]string, 0)
-			p.SetFinalizers(emptyFinalizer)
-
-			err := c.Update(context.TODO(), &p)
-			if err != nil {
-				logger.Error(err, fmt.Sprintf("failed to remove finalizer from the pod %v/%v", p.Namespace, p.Name))
-				me = multierror.Append(err, me)
-			} else {
-				podsWithFinalizersRemoved = append(podsWithFinalizersRemoved, p.Name)
+		if len(p.GetFinalizers()) == 0 {
+			continue
+		}
+		var kept []string
+		for _, f := range p.GetFinalizers() {
+			if removable != nil && !removable(f) {
+				kept = append(kept, f)
 			}
 		}
+		if len(kept) == len(p.GetFinalizers()) {
+			logger.Info(fmt.Sprintf("Skipping remove finalizer strategy for pod %v/%v as none of its finalizers %v may be removed", p.Namespace, p.Name, kept))
+			podsSkipped = append(podsSkipped, p.Name)
+			skipped = append(skipped, p.Namespace+"/"+p.Name)
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Applying remove finalizer strategy to pod %v/%v", p.Namespace, p.Name))
+		patch := client.MergeFromWithOptions(p.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		p.SetFinalizers(kept)
+		err := c.Patch(context.TODO(), &p, patch)
+		switch {
+		case err == nil:
+			podsWithFinalizersRemoved = append(podsWithFinalizersRemoved, p.Name)
+			pods = append(pods, p.Namespace+"/"+p.Name)
+		case apierrors.IsConflict(err):
+			logger.Info(fmt.Sprintf("Pod %v/%v has been modified since it was listed, its finalizers will be removed on the next attempt", p.Namespace, p.Name))
+		default:
+			logger.Error(err, fmt.Sprintf("failed to remove finalizer from the pod %v/%v", p.Namespace, p.Name))
+			me = multierror.Append(err, me)
+		}
+	}
+
+	msg := fmt.Sprintf("Finalizers removed for pods: %s", strings.Join(podsWithFinalizersRemoved, ","))
+	if len(podsSkipped) > 0 {
+		msg = fmt.Sprintf("%s. Finalizers not allowed to be removed for pods: %s", msg, strings.Join(podsSkipped, ","))
 	}
 
 	return &RemoveFinalizersResult{
-		Message:    fmt.Sprintf("Finalizers removed for pods: %s", strings.Join(podsWithFinalizersRemoved, ",")),
+		Message:    msg,
 		NumRemoved: len(podsWithFinalizersRemoved),
+		Pods:       pods,
+		Skipped:    skipped,
 	}, me.ErrorOrNil()
 }
 
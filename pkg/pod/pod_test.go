@@ -1,6 +1,7 @@
 package pod
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,7 +10,9 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -130,11 +133,47 @@ var _ = Describe("Pod Filter", func() {
 		})
 
 		It("Should remove finalizers if they exist", func() {
-			mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(2)
-			result, err := RemoveFinalizersFromPod(mockKubeClient, logger, podList)
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+			result, err := RemoveFinalizersFromPod(mockKubeClient, logger, podList, nil)
 			Expect(err).To(BeNil())
 			Expect(result.NumRemoved).To(Equal(2))
 		})
+
+		It("Should only remove the finalizers matching the predicate", func() {
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, p *corev1.Pod, patch client.Patch, po ...client.PatchOption) error {
+					Expect(p.Finalizers).To(Equal([]string{"deleteThisFinalizerAlso"}))
+					data, err := patch.Data(p)
+					Expect(err).To(BeNil())
+					Expect(string(data)).To(ContainSubstring(`"resourceVersion":"1"`))
+					return nil
+				})
+			podList.Items = podList.Items[:1]
+			podList.Items[0].ResourceVersion = "1"
+			result, err := RemoveFinalizersFromPod(mockKubeClient, logger, podList, func(f string) bool {
+				return f == "deleteThisFinalizer"
+			})
+			Expect(err).To(BeNil())
+			Expect(result.NumRemoved).To(Equal(1))
+			Expect(result.Skipped).To(BeEmpty())
+		})
+
+		It("Should skip the pods none of whose finalizers may be removed", func() {
+			result, err := RemoveFinalizersFromPod(mockKubeClient, logger, podList, func(f string) bool {
+				return false
+			})
+			Expect(err).To(BeNil())
+			Expect(result.NumRemoved).To(Equal(0))
+			Expect(result.Skipped).To(Equal([]string{"/testpod", "/testpod"}))
+		})
+
+		It("Should leave pods modified since they were listed for a later attempt", func() {
+			mockKubeClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+				apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "testpod", fmt.Errorf("fake conflict"))).Times(2)
+			result, err := RemoveFinalizersFromPod(mockKubeClient, logger, podList, nil)
+			Expect(err).To(BeNil())
+			Expect(result.NumRemoved).To(Equal(0))
+		})
 	})
 
 	Context("Deleting Pods", func() {
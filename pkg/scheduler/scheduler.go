@@ -33,26 +33,65 @@ type SchedulerResult struct {
 	IsReady          bool
 	IsBreached       bool
 	TimeUntilUpgrade time.Duration
+	// The earliest time at which the upgrade may commence, honouring any maintenance and freeze windows.
+	// Zero if no such time could be determined.
+	NextEligibleStart time.Time
+	// Indicates that the upgrade is being held back by a freeze window
+	IsFrozen     bool
+	FreezeReason string
 }
 
 func (s *scheduler) IsReadyToUpgrade(upgradeConfig *upgradev1alpha1.UpgradeConfig, timeOut time.Duration) SchedulerResult {
+	return isReadyToUpgradeAt(upgradeConfig, timeOut, time.Now())
+}
+
+func isReadyToUpgradeAt(upgradeConfig *upgradev1alpha1.UpgradeConfig, timeOut time.Duration, now time.Time) SchedulerResult {
 	upgradeTime, err := time.Parse(time.RFC3339, upgradeConfig.Spec.UpgradeAt)
 	if err != nil {
 		logger.Error(err, "failed to parse spec.upgradeAt", "upgradeAt", upgradeConfig.Spec.UpgradeAt)
 		return SchedulerResult{IsReady: false, IsBreached: false, TimeUntilUpgrade: 0}
 	}
-	now := time.Now()
-	if now.After(upgradeTime) {
+
+	from := upgradeTime
+	if now.After(from) {
+		from = now
+	}
+	nextStart, found, err := nextEligibleStart(upgradeConfig.Spec, from)
+	if err != nil {
+		logger.Error(err, "failed to evaluate maintenance windows")
+		return SchedulerResult{IsReady: false, IsBreached: false, TimeUntilUpgrade: 0}
+	}
+
+	result := SchedulerResult{NextEligibleStart: nextStart}
+	if freeze := activeFreeze(upgradeConfig.Spec.FreezeWindows, from); freeze != nil {
+		result.IsFrozen = true
+		result.FreezeReason = freeze.Reason
+	}
+
+	if !found {
+		logger.Info("Upgrade cannot commence within any maintenance window outside of freeze windows", "searchedUntil", from.Add(maxEligibleStartSearch))
+		return result
+	}
+
+	if now.After(upgradeTime) && !nextStart.After(now) {
+		result.IsReady = true
 		// Is the current time within the allowable upgrade window
-		if upgradeTime.Add(timeOut).After(now) {
-			return SchedulerResult{IsReady: true, IsBreached: false, TimeUntilUpgrade: 0}
+		since, err := eligibleSince(upgradeConfig.Spec, upgradeTime, now)
+		if err != nil {
+			logger.Error(err, "failed to evaluate maintenance windows")
+			return SchedulerResult{IsReady: false, IsBreached: false, TimeUntilUpgrade: 0}
 		}
-
-		return SchedulerResult{IsReady: true, IsBreached: true, TimeUntilUpgrade: 0}
+		result.IsBreached = !since.Add(timeOut).After(now)
+		return result
 	}
 
 	// It hasn't reached the upgrade window yet
-	pendingTime := upgradeTime.Sub(now)
+	pendingTime := nextStart.Sub(now)
+	result.TimeUntilUpgrade = pendingTime
+	if result.IsFrozen {
+		logger.Info(fmt.Sprintf("Upgrade is held back by a freeze window, next eligible start is in %d hours %d mins", int(pendingTime.Hours()), int(pendingTime.Minutes())-(int(pendingTime.Hours())*60)), "reason", result.FreezeReason)
+		return result
+	}
 	logger.Info(fmt.Sprintf("Upgrade is scheduled in %d hours %d mins", int(pendingTime.Hours()), int(pendingTime.Minutes())-(int(pendingTime.Hours())*60)))
-	return SchedulerResult{IsReady: false, IsBreached: false, TimeUntilUpgrade: pendingTime}
+	return result
 }
@@ -42,6 +42,127 @@ var _ = Describe("Scheduler", func() {
 		Expect(result.IsBreached).To(BeFalse())
 
 	})
+
+	Context("When maintenance and freeze windows are specified", func() {
+		var (
+			// A Wednesday
+			now = time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC)
+		)
+
+		BeforeEach(func() {
+			upgradeConfig = testUpgradeConfig(true, now.Add(-10*time.Minute).Format(time.RFC3339))
+		})
+
+		It("should be ready to upgrade when inside a maintenance window", func() {
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{StartTime: "11:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeTrue())
+			Expect(result.IsBreached).To(BeFalse())
+			Expect(result.NextEligibleStart).To(Equal(now))
+		})
+
+		It("should indicate breach when the maintenance window opened longer ago than the timeout", func() {
+			upgradeConfig = testUpgradeConfig(true, now.Add(-48*time.Hour).Format(time.RFC3339))
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{StartTime: "11:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 30*time.Minute, now)
+			Expect(result.IsReady).To(BeTrue())
+			Expect(result.IsBreached).To(BeTrue())
+		})
+
+		It("should not be ready to upgrade outside a maintenance window and report when the next one opens", func() {
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{Days: []upgradev1alpha1.Weekday{"Saturday"}, StartTime: "02:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeFalse())
+			expected := time.Date(2024, time.May, 18, 2, 0, 0, 0, time.UTC)
+			Expect(result.NextEligibleStart).To(Equal(expected))
+			Expect(result.TimeUntilUpgrade).To(Equal(expected.Sub(now)))
+		})
+
+		It("should evaluate maintenance windows in their time zone", func() {
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{StartTime: "22:00", Duration: metav1.Duration{Duration: 2 * time.Hour}, TimeZone: "Australia/Brisbane"},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeTrue())
+		})
+
+		It("should be ready inside a window that opened on a previous day", func() {
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{Days: []upgradev1alpha1.Weekday{"Tuesday"}, StartTime: "22:00", Duration: metav1.Duration{Duration: 16 * time.Hour}},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeTrue())
+		})
+
+		It("should refuse to commence inside a freeze window", func() {
+			upgradeConfig.Spec.FreezeWindows = []upgradev1alpha1.FreezeWindow{
+				{
+					Start:  metav1.Time{Time: now.Add(-time.Hour)},
+					End:    metav1.Time{Time: now.Add(2 * time.Hour)},
+					Reason: "quarter end",
+				},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeFalse())
+			Expect(result.IsFrozen).To(BeTrue())
+			Expect(result.FreezeReason).To(Equal("quarter end"))
+			Expect(result.NextEligibleStart).To(Equal(now.Add(2 * time.Hour)))
+			Expect(result.TimeUntilUpgrade).To(Equal(2 * time.Hour))
+		})
+
+		It("should skip maintenance windows that fall within a freeze window", func() {
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{StartTime: "11:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			}
+			upgradeConfig.Spec.FreezeWindows = []upgradev1alpha1.FreezeWindow{
+				{
+					Start: metav1.Time{Time: now.Add(-time.Hour)},
+					End:   metav1.Time{Time: now.Add(24 * time.Hour)},
+				},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeFalse())
+			Expect(result.IsFrozen).To(BeTrue())
+			// The freeze ends at 12:00 the next day, within that day's window
+			Expect(result.NextEligibleStart).To(Equal(now.Add(24 * time.Hour)))
+		})
+
+		It("should not measure breach from before the end of a freeze", func() {
+			upgradeConfig = testUpgradeConfig(true, now.Add(-48*time.Hour).Format(time.RFC3339))
+			upgradeConfig.Spec.FreezeWindows = []upgradev1alpha1.FreezeWindow{
+				{
+					Start: metav1.Time{Time: now.Add(-47 * time.Hour)},
+					End:   metav1.Time{Time: now.Add(-10 * time.Minute)},
+				},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeTrue())
+			Expect(result.IsBreached).To(BeFalse())
+		})
+
+		It("should not be ready if the maintenance window is invalid", func() {
+			upgradeConfig.Spec.MaintenanceWindows = []upgradev1alpha1.MaintenanceWindow{
+				{StartTime: "11:00", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Not/AZone"},
+			}
+			result := isReadyToUpgradeAt(upgradeConfig, 60*time.Minute, now)
+			Expect(result.IsReady).To(BeFalse())
+			Expect(ValidateMaintenanceWindow(upgradeConfig.Spec.MaintenanceWindows[0])).To(HaveOccurred())
+		})
+
+		It("should reject freeze windows that end before they start", func() {
+			f := upgradev1alpha1.FreezeWindow{
+				Start: metav1.Time{Time: now},
+				End:   metav1.Time{Time: now.Add(-time.Hour)},
+			}
+			Expect(ValidateFreezeWindow(f)).To(HaveOccurred())
+		})
+	})
 })
 
 func testUpgradeConfig(proceed bool, upgradeAt string) *upgradev1alpha1.UpgradeConfig {
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	// Embed the time zone database so maintenance window time zones can be
+	// resolved regardless of what the operator image ships with.
+	_ "time/tzdata"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+const (
+	// maxEligibleStartSearch bounds how far ahead of upgradeAt the scheduler
+	// will look for a time at which the upgrade may commence
+	maxEligibleStartSearch = 366 * 24 * time.Hour
+
+	// maintenanceWindowStartTimeLayout is the layout of MaintenanceWindow.StartTime
+	maintenanceWindowStartTimeLayout = "15:04"
+)
+
+// activeFreeze returns the freeze window that t falls within, or nil if there is none
+func activeFreeze(freezes []upgradev1alpha1.FreezeWindow, t time.Time) *upgradev1alpha1.FreezeWindow {
+	for i, f := range freezes {
+		if !t.Before(f.Start.Time) && t.Before(f.End.Time) {
+			return &freezes[i]
+		}
+	}
+	return nil
+}
+
+// ValidateMaintenanceWindow checks that the maintenance window can be evaluated
+func ValidateMaintenanceWindow(w upgradev1alpha1.MaintenanceWindow) error {
+	_, _, err := parseMaintenanceWindow(w)
+	return err
+}
+
+// ValidateFreezeWindow checks that the freeze window describes a valid period of time
+func ValidateFreezeWindow(f upgradev1alpha1.FreezeWindow) error {
+	if !f.End.After(f.Start.Time) {
+		return fmt.Errorf("freeze window end %s is not after its start %s", f.End.Format(time.RFC3339), f.Start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func parseMaintenanceWindow(w upgradev1alpha1.MaintenanceWindow) (*time.Location, time.Time, error) {
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid maintenance window time zone %q: %v", w.TimeZone, err)
+	}
+	start, err := time.Parse(maintenanceWindowStartTimeLayout, w.StartTime)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid maintenance window start time %q: %v", w.StartTime, err)
+	}
+	if w.Duration.Duration <= 0 {
+		return nil, time.Time{}, fmt.Errorf("maintenance window duration must be positive, got %s", w.Duration.Duration)
+	}
+	return loc, start, nil
+}
+
+func opensOn(w upgradev1alpha1.MaintenanceWindow, day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if string(d) == day.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceWindowAt evaluates the maintenance windows at time t. If t falls
+// within a window, inWindow is true and openedAt is when that window opened.
+// Otherwise nextOpen is the next time any of the windows opens.
+func maintenanceWindowAt(windows []upgradev1alpha1.MaintenanceWindow, t time.Time) (inWindow bool, openedAt time.Time, nextOpen time.Time, err error) {
+	for _, w := range windows {
+		loc, start, err := parseMaintenanceWindow(w)
+		if err != nil {
+			return false, time.Time{}, time.Time{}, err
+		}
+
+		local := t.In(loc)
+		// Look far enough back to catch windows that opened on a previous day and are still open
+		firstDay := -int(w.Duration.Duration/(24*time.Hour)) - 1
+		for offset := firstDay; offset <= 7; offset++ {
+			open := time.Date(local.Year(), local.Month(), local.Day()+offset, start.Hour(), start.Minute(), 0, 0, loc)
+			if !opensOn(w, open.Weekday()) {
+				continue
+			}
+			if !t.Before(open) && t.Before(open.Add(w.Duration.Duration)) {
+				if !inWindow || open.Before(openedAt) {
+					openedAt = open
+				}
+				inWindow = true
+				continue
+			}
+			if open.After(t) && (nextOpen.IsZero() || open.Before(nextOpen)) {
+				nextOpen = open
+			}
+		}
+	}
+	return inWindow, openedAt, nextOpen, nil
+}
+
+// nextEligibleStart returns the earliest time at or after t at which the upgrade
+// may commence according to the maintenance and freeze windows in the spec.
+// found is false if there is no such time within maxEligibleStartSearch.
+func nextEligibleStart(spec upgradev1alpha1.UpgradeConfigSpec, t time.Time) (eligible time.Time, found bool, err error) {
+	limit := t.Add(maxEligibleStartSearch)
+	for !t.After(limit) {
+		if f := activeFreeze(spec.FreezeWindows, t); f != nil {
+			t = f.End.Time
+			continue
+		}
+		if len(spec.MaintenanceWindows) == 0 {
+			return t, true, nil
+		}
+		inWindow, _, nextOpen, err := maintenanceWindowAt(spec.MaintenanceWindows, t)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if inWindow {
+			return t, true, nil
+		}
+		if nextOpen.IsZero() {
+			return time.Time{}, false, nil
+		}
+		t = nextOpen
+	}
+	return time.Time{}, false, nil
+}
+
+// eligibleSince returns the time from which the upgrade has continuously been
+// allowed to commence, assuming it is allowed to commence at now. This is the
+// latest of upgradeAt, the end of the most recent freeze and the opening of the
+// current maintenance window.
+func eligibleSince(spec upgradev1alpha1.UpgradeConfigSpec, upgradeTime time.Time, now time.Time) (time.Time, error) {
+	since := upgradeTime
+	for _, f := range spec.FreezeWindows {
+		if !f.End.After(now) && f.End.After(since) {
+			since = f.End.Time
+		}
+	}
+	if len(spec.MaintenanceWindows) > 0 {
+		_, openedAt, _, err := maintenanceWindowAt(spec.MaintenanceWindows, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if openedAt.After(since) {
+			since = openedAt
+		}
+	}
+	return since, nil
+}
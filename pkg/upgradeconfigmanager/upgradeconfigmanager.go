@@ -6,6 +6,7 @@ import (
 	"math"
 	"math/rand"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/jpillora/backoff"
@@ -41,6 +42,11 @@ const (
 	INITIAL_SYNC_DURATION = 1 * time.Minute
 	// ERROR_RETRY_DURATION is an error retryn duration
 	ERROR_RETRY_DURATION = 5 * time.Minute
+	// ProviderPausedAnnotation records the pause state last received from the provider, so that a
+	// pause set on the cluster can be told apart from one set by the provider
+	ProviderPausedAnnotation = "upgrade.managed.openshift.io/provider-paused"
+	// ProviderDryRunAnnotation records the dry run state last received from the provider
+	ProviderDryRunAnnotation = "upgrade.managed.openshift.io/provider-dry-run"
 )
 
 // Errors
@@ -247,8 +253,34 @@ func (s *upgradeConfigManager) Refresh() (bool, error) {
 	// Replace the spec with the refreshed upgrade spec
 	upgradeConfigSpec.DeepCopyInto(&replacementUpgradeConfig.Spec)
 
+	// The pause and dry run flags may also be set on the cluster. Record the values received from the
+	// provider, and retain the cluster's values only where they have been changed since they were last received.
+	replacementUpgradeConfig.SetAnnotations(map[string]string{
+		ProviderPausedAnnotation: strconv.FormatBool(upgradeConfigSpec.Paused),
+		ProviderDryRunAnnotation: strconv.FormatBool(upgradeConfigSpec.DryRun),
+	})
+	if isSetOnCluster(currentUpgradeConfig, ProviderPausedAnnotation, currentUpgradeConfig.Spec.Paused) {
+		replacementUpgradeConfig.Spec.Paused = currentUpgradeConfig.Spec.Paused
+		replacementUpgradeConfig.Spec.PausedReason = currentUpgradeConfig.Spec.PausedReason
+	}
+	if isSetOnCluster(currentUpgradeConfig, ProviderDryRunAnnotation, currentUpgradeConfig.Spec.DryRun) {
+		replacementUpgradeConfig.Spec.DryRun = currentUpgradeConfig.Spec.DryRun
+	}
+
 	// is there a difference between the original and replacement?
 	changed := !reflect.DeepEqual(replacementUpgradeConfig.Spec, currentUpgradeConfig.Spec)
+
+	// A change to only the pause and dry run flags does not change the upgrade, so the UpgradeConfig
+	// is updated in place to keep its status
+	if changed && foundUpgradeConfig && onlyFlagsChanged(currentUpgradeConfig.Spec, replacementUpgradeConfig.Spec) {
+		log.Info(fmt.Sprintf("pause or dry run changed for UpgradeConfig %v, updating it", currentUpgradeConfig.Name))
+		err := updateFlags(s.client, currentUpgradeConfig, replacementUpgradeConfig)
+		if err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
 	if changed {
 		err := recreateUpgradeConfigOnChange(s.client, foundUpgradeConfig, *currentUpgradeConfig, replacementUpgradeConfig, *s)
 		if err != nil {
@@ -257,11 +289,68 @@ func (s *upgradeConfigManager) Refresh() (bool, error) {
 		log.Info("Successfully create new UpgradeConfig")
 	} else {
 		log.Info(fmt.Sprintf("no change in spec from existing UpgradeConfig %v, won't update", currentUpgradeConfig.Name))
+		err := updateProviderAnnotations(s.client, currentUpgradeConfig, replacementUpgradeConfig.GetAnnotations())
+		if err != nil {
+			return false, err
+		}
 	}
 
 	return changed, nil
 }
 
+// isSetOnCluster returns whether a flag on the UpgradeConfig differs from the value last received from the
+// provider, as recorded in the given annotation, and so has been set on the cluster
+func isSetOnCluster(uc *upgradev1alpha1.UpgradeConfig, annotation string, value bool) bool {
+	return value != (uc.GetAnnotations()[annotation] == "true")
+}
+
+// onlyFlagsChanged returns whether two UpgradeConfig specs differ only in their pause and dry run flags
+func onlyFlagsChanged(current, replacement upgradev1alpha1.UpgradeConfigSpec) bool {
+	current.Paused, current.PausedReason, current.DryRun = false, "", false
+	replacement.Paused, replacement.PausedReason, replacement.DryRun = false, "", false
+	return reflect.DeepEqual(current, replacement)
+}
+
+// updateFlags sets the pause and dry run flags and the provider annotations of the replacement on
+// the UpgradeConfig
+func updateFlags(c client.Client, uc *upgradev1alpha1.UpgradeConfig, replacement upgradev1alpha1.UpgradeConfig) error {
+	updated := uc.DeepCopy()
+	updated.Spec.Paused = replacement.Spec.Paused
+	updated.Spec.PausedReason = replacement.Spec.PausedReason
+	updated.Spec.DryRun = replacement.Spec.DryRun
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	for k, v := range replacement.GetAnnotations() {
+		updated.Annotations[k] = v
+	}
+	return c.Update(context.TODO(), updated)
+}
+
+// updateProviderAnnotations records the flag values last received from the provider on an UpgradeConfig
+// whose spec has not otherwise changed
+func updateProviderAnnotations(c client.Client, uc *upgradev1alpha1.UpgradeConfig, annotations map[string]string) error {
+	current := uc.GetAnnotations()
+	changed := false
+	for k, v := range annotations {
+		if current[k] != v {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	updated := uc.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	for k, v := range annotations {
+		updated.Annotations[k] = v
+	}
+	return c.Update(context.TODO(), updated)
+}
+
 // Reads the UpgradeConfigManager's configuration
 func readConfigManagerConfig(client client.Client, cfb configmanager.ConfigManagerBuilder) (*UpgradeConfigManagerConfig, error) {
 	cfg := &UpgradeConfigManagerConfig{}
@@ -386,5 +386,96 @@ var _ = Describe("UpgradeConfigManager", func() {
 			Expect(err).To(BeNil())
 			Expect(changed).To(BeTrue())
 		})
+
+		Context("When the upgrade has been paused", func() {
+			var pausedUpgradeConfig *upgradev1alpha1.UpgradeConfig
+
+			BeforeEach(func() {
+				pausedUpgradeConfig = upgradeConfig.DeepCopy()
+				pausedUpgradeConfig.Spec.Paused = true
+				pausedUpgradeConfig.Spec.PausedReason = "investigating"
+			})
+
+			It("retains a pause set on the cluster", func() {
+				gomock.InOrder(
+					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *pausedUpgradeConfig).Return(nil),
+					mockSPClientBuilder.EXPECT().New(gomock.Any(), gomock.Any()).Return(mockSPClient, nil),
+					mockSPClient.EXPECT().Get().Return([]upgradev1alpha1.UpgradeConfigSpec{upgradeConfig.Spec}, nil),
+					mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, uc *upgradev1alpha1.UpgradeConfig, uo ...client.UpdateOption) error {
+							Expect(uc.Spec.Paused).To(BeTrue())
+							Expect(uc.Annotations).To(HaveKeyWithValue(ProviderPausedAnnotation, "false"))
+							return nil
+						}),
+				)
+				changed, err := manager.Refresh()
+				Expect(err).To(BeNil())
+				Expect(changed).To(BeFalse())
+			})
+
+			It("follows the provider unpausing a pause it set", func() {
+				pausedUpgradeConfig.Annotations = map[string]string{
+					ProviderPausedAnnotation: "true",
+					ProviderDryRunAnnotation: "false",
+				}
+				pausedUpgradeConfig.Status.History = []upgradev1alpha1.UpgradeHistory{{Version: TEST_UPGRADE_VERSION, Phase: upgradev1alpha1.UpgradePhaseUpgrading}}
+				gomock.InOrder(
+					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *pausedUpgradeConfig).Return(nil),
+					mockSPClientBuilder.EXPECT().New(gomock.Any(), gomock.Any()).Return(mockSPClient, nil),
+					mockSPClient.EXPECT().Get().Return([]upgradev1alpha1.UpgradeConfigSpec{upgradeConfig.Spec}, nil),
+					mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, uc *upgradev1alpha1.UpgradeConfig, uo ...client.UpdateOption) error {
+							Expect(uc.Spec.Paused).To(BeFalse())
+							Expect(uc.Spec.PausedReason).To(BeEmpty())
+							Expect(uc.Annotations).To(HaveKeyWithValue(ProviderPausedAnnotation, "false"))
+							Expect(uc.Status.History).To(HaveLen(1))
+							return nil
+						}),
+				)
+				changed, err := manager.Refresh()
+				Expect(err).To(BeNil())
+				Expect(changed).To(BeFalse())
+			})
+
+			It("follows the provider pausing the upgrade without recreating the UpgradeConfig", func() {
+				providerSpec := upgradeConfig.Spec
+				providerSpec.Paused = true
+				providerSpec.PausedReason = "incident"
+				providerSpec.DryRun = true
+				gomock.InOrder(
+					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, upgradeConfig).Return(nil),
+					mockSPClientBuilder.EXPECT().New(gomock.Any(), gomock.Any()).Return(mockSPClient, nil),
+					mockSPClient.EXPECT().Get().Return([]upgradev1alpha1.UpgradeConfigSpec{providerSpec}, nil),
+					mockKubeClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, uc *upgradev1alpha1.UpgradeConfig, uo ...client.UpdateOption) error {
+							Expect(uc.Spec.Paused).To(BeTrue())
+							Expect(uc.Spec.PausedReason).To(Equal("incident"))
+							Expect(uc.Spec.DryRun).To(BeTrue())
+							Expect(uc.Annotations).To(HaveKeyWithValue(ProviderPausedAnnotation, "true"))
+							Expect(uc.Annotations).To(HaveKeyWithValue(ProviderDryRunAnnotation, "true"))
+							return nil
+						}),
+				)
+				changed, err := manager.Refresh()
+				Expect(err).To(BeNil())
+				Expect(changed).To(BeFalse())
+			})
+
+			It("does not update an UpgradeConfig already recording the provider's values", func() {
+				pausedUpgradeConfig.Annotations = map[string]string{
+					ProviderPausedAnnotation: "true",
+					ProviderDryRunAnnotation: "false",
+				}
+				providerSpec := pausedUpgradeConfig.Spec
+				gomock.InOrder(
+					mockKubeClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *pausedUpgradeConfig).Return(nil),
+					mockSPClientBuilder.EXPECT().New(gomock.Any(), gomock.Any()).Return(mockSPClient, nil),
+					mockSPClient.EXPECT().Get().Return([]upgradev1alpha1.UpgradeConfigSpec{providerSpec}, nil),
+				)
+				changed, err := manager.Refresh()
+				Expect(err).To(BeNil())
+				Expect(changed).To(BeFalse())
+			})
+		})
 	})
 })
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
@@ -26,7 +27,7 @@ type aroUpgrader struct {
 }
 
 // NewAROUpgrader creates a new instance of an aroUpgrader
-func NewAROUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics.Metrics, notifier eventmanager.EventManager) (*aroUpgrader, error) {
+func NewAROUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics.Metrics, notifier eventmanager.EventManager, recorder record.EventRecorder) (*aroUpgrader, error) {
 	cfg := &upgraderConfig{}
 	err := cfm.Into(cfg)
 	if err != nil {
@@ -51,7 +52,8 @@ func NewAROUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics
 			notifier:             notifier,
 			config:               cfg,
 			scaler:               scaler.NewScaler(),
-			drainstrategyBuilder: drain.NewBuilder(),
+			drainstrategyBuilder: drain.NewBuilder(recorder),
+			recorder:             recorder,
 			maintenance:          m,
 			machinery:            machinery.NewMachinery(),
 			availabilityCheckers: acs,
@@ -67,14 +69,22 @@ func NewAROUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics
 		upgradesteps.Action(string(upgradev1alpha1.CommenceUpgrade), au.CommenceUpgrade),
 		upgradesteps.Action(string(upgradev1alpha1.ControlPlaneUpgraded), au.ControlPlaneUpgraded),
 		upgradesteps.Action(string(upgradev1alpha1.RemoveControlPlaneMaintWindow), au.RemoveControlPlaneMaintWindow),
+		upgradesteps.Action(string(upgradev1alpha1.ControlPlaneSoak), au.ControlPlaneSoak),
 		upgradesteps.Action(string(upgradev1alpha1.WorkersMaintWindow), au.CreateWorkerMaintWindow),
+		upgradesteps.Action(string(upgradev1alpha1.CanaryWorkerNodesUpgraded), au.CanaryWorkersUpgraded),
+		upgradesteps.Action(string(upgradev1alpha1.CanaryHealthCheck), au.CanaryHealthCheck),
+		upgradesteps.Action(string(upgradev1alpha1.ReleaseWorkerNodes), au.ReleaseWorkers),
 		upgradesteps.Action(string(upgradev1alpha1.AllWorkerNodesUpgraded), au.AllWorkersUpgraded),
 		upgradesteps.Action(string(upgradev1alpha1.RemoveExtraScaledNodes), au.RemoveExtraScaledNodes),
 		upgradesteps.Action(string(upgradev1alpha1.RemoveMaintWindow), au.RemoveMaintWindow),
 		upgradesteps.Action(string(upgradev1alpha1.PostClusterHealthCheck), au.PostUpgradeHealthCheck),
 		upgradesteps.Action(string(upgradev1alpha1.SendCompletedNotification), au.SendCompletedNotification),
 	}
-	au.steps = steps
+	steps, err = withCustomSteps(steps, cfg, au.clusterUpgrader)
+	if err != nil {
+		return nil, err
+	}
+	au.steps = withStepTimeouts(steps, cfg)
 
 	return &au, nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/openshift/managed-upgrade-operator/pkg/eventmanager"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
@@ -19,6 +20,7 @@ import (
 type ClusterUpgrader interface {
 	HealthCheck(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) (bool, error)
 	UpgradeCluster(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) (upgradev1alpha1.UpgradePhase, error)
+	Preflight(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) ([]upgradev1alpha1.PreflightCheck, error)
 }
 
 // ClusterUpgraderBuilder enables an implementation of a ClusterUpgraderBuilder
@@ -28,29 +30,34 @@ type ClusterUpgraderBuilder interface {
 	NewClient(client.Client, configmanager.ConfigManager, metrics.Metrics, eventmanager.EventManager, upgradev1alpha1.UpgradeType) (ClusterUpgrader, error)
 }
 
-// NewBuilder returns a clusterUpgraderBuilder
-func NewBuilder() ClusterUpgraderBuilder {
-	return &clusterUpgraderBuilder{}
+// NewBuilder returns a clusterUpgraderBuilder whose upgraders record Events with the given recorder
+func NewBuilder(recorder record.EventRecorder) ClusterUpgraderBuilder {
+	return &clusterUpgraderBuilder{
+		recorder: recorder,
+	}
 }
 
-type clusterUpgraderBuilder struct{}
+type clusterUpgraderBuilder struct {
+	// recorder records the Events of the upgrade steps and node drains
+	recorder record.EventRecorder
+}
 
 func (cub *clusterUpgraderBuilder) NewClient(c client.Client, cfm configmanager.ConfigManager, mc metrics.Metrics, nc eventmanager.EventManager, upgradeType upgradev1alpha1.UpgradeType) (ClusterUpgrader, error) {
 	switch upgradeType {
 	case upgradev1alpha1.OSD:
-		cu, err := NewOSDUpgrader(c, cfm, mc, nc)
+		cu, err := NewOSDUpgrader(c, cfm, mc, nc, cub.recorder)
 		if err != nil {
 			return nil, err
 		}
 		return cu, nil
 	case upgradev1alpha1.ARO:
-		cu, err := NewAROUpgrader(c, cfm, mc, nc)
+		cu, err := NewAROUpgrader(c, cfm, mc, nc, cub.recorder)
 		if err != nil {
 			return nil, err
 		}
 		return cu, nil
 	default:
-		cu, err := NewOSDUpgrader(c, cfm, mc, nc)
+		cu, err := NewOSDUpgrader(c, cfm, mc, nc, cub.recorder)
 		if err != nil {
 			return nil, err
 		}
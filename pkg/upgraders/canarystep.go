@@ -0,0 +1,128 @@
+package upgraders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
+)
+
+// CanaryWorkersUpgraded checks whether the worker nodes in the canary MachineConfigPool
+// are ready with new config, while the worker MachineConfigPool is held paused
+func (c *clusterUpgrader) CanaryWorkersUpgraded(ctx context.Context, logger logr.Logger) (bool, error) {
+	if !c.config.Canary.IsEnabled() || c.workersReleased() {
+		return true, nil
+	}
+
+	upgradingResult, err := c.machinery.IsUpgrading(c.client, c.config.Canary.Pool)
+	if err != nil {
+		return false, err
+	}
+	if upgradingResult.MachineCount == 0 {
+		logger.Info(fmt.Sprintf("canary MachineConfigPool %s has no machines", c.config.Canary.Pool))
+	}
+	if upgradingResult.IsUpgrading {
+		logger.Info(fmt.Sprintf("not all canary workers are upgraded, upgraded: %v, total: %v", upgradingResult.UpdatedCount, upgradingResult.MachineCount))
+		return false, nil
+	}
+	return true, nil
+}
+
+// CanaryHealthCheck gates the release of the remaining worker nodes on the health of
+// the cluster once the canary nodes have been upgraded and have soaked. A failing
+// gate is notified and keeps the worker MachineConfigPool paused.
+func (c *clusterUpgrader) CanaryHealthCheck(ctx context.Context, logger logr.Logger) (bool, error) {
+	if !c.config.Canary.IsEnabled() || c.workersReleased() {
+		return true, nil
+	}
+
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return false, nil
+	}
+	condition := history.Conditions.GetCondition(upgradev1alpha1.CanaryHealthCheck)
+	if condition != nil && condition.StartTime != nil {
+		soakEnd := condition.StartTime.Add(c.config.Canary.GetSoakDuration())
+		if time.Now().Before(soakEnd) {
+			logger.Info(fmt.Sprintf("canary workers are soaking until %s", soakEnd.Format(time.RFC3339)))
+			return false, nil
+		}
+	}
+
+	version := getCurrentVersion(c.cvClient, logger)
+	healthCheckFailed := []string{}
+	for _, hc := range c.enabledHealthChecks(c.config.Canary.GetHealthChecks()) {
+		result, err := hc.Check(ctx, upgradev1alpha1.CanaryHealthCheck, logger, version)
+		if err != nil {
+			logger.Info(fmt.Sprintf("Canary health check %s failed: %s", hc.Name(), err))
+			healthCheckFailed = append(healthCheckFailed, result)
+		}
+	}
+
+	if len(healthCheckFailed) > 0 {
+		result := strings.Join(healthCheckFailed, ",")
+		logger.Info(fmt.Sprintf("Holding worker upgrade due to following canary health check failure: %s", result))
+		err := c.notifier.NotifyResult(notifier.MuoStateCanaryHealthCheckSL, result)
+		if err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	logger.Info("Canary health check passed")
+	return true, nil
+}
+
+// ReleaseWorkers unpauses the worker MachineConfigPool once the canary nodes have
+// passed their health gate, allowing the remaining worker nodes to upgrade
+func (c *clusterUpgrader) ReleaseWorkers(ctx context.Context, logger logr.Logger) (bool, error) {
+	if !c.config.Canary.IsEnabled() {
+		return true, nil
+	}
+
+	upgradingResult, err := c.machinery.IsWorkerPoolsUpgrading(c.client)
+	if err != nil {
+		return false, err
+	}
+	if c.heldByPoolOrder(machinery.WorkerPool, upgradingResult.Pools) {
+		logger.Info("Worker MachineConfigPool will be released once the preceding MachineConfigPools have been upgraded")
+		return true, nil
+	}
+
+	logger.Info("Releasing worker MachineConfigPool")
+	err = c.machinery.UnpauseMachineConfigPool(c.client, machinery.WorkerPool)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// workersReleased returns whether the worker MachineConfigPool has been released
+// after the canary nodes passed their health gate
+func (c *clusterUpgrader) workersReleased() bool {
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return false
+	}
+	return history.Conditions.IsTrueFor(upgradev1alpha1.ReleaseWorkerNodes)
+}
+
+// workersHeldForCanary returns whether the worker MachineConfigPool must be held
+// paused so that only the canary nodes upgrade. Workers are held from when the
+// upgrade has commenced until they are released.
+func (c *clusterUpgrader) workersHeldForCanary() bool {
+	if !c.config.Canary.IsEnabled() {
+		return false
+	}
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil || history.Conditions.GetCondition(upgradev1alpha1.CommenceUpgrade) == nil {
+		return false
+	}
+	return !c.workersReleased()
+}
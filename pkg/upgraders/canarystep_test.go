@@ -0,0 +1,296 @@
+package upgraders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/clusterversion"
+	cvMocks "github.com/openshift/managed-upgrade-operator/pkg/clusterversion/mocks"
+	emMocks "github.com/openshift/managed-upgrade-operator/pkg/eventmanager/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	mockMachinery "github.com/openshift/managed-upgrade-operator/pkg/machinery/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("CanaryStep", func() {
+	var (
+		logger logr.Logger
+		// mocks
+		mockKubeClient      *mocks.MockClient
+		mockCtrl            *gomock.Controller
+		mockMachineryClient *mockMachinery.MockMachinery
+		mockMetricsClient   *mockMetrics.MockMetrics
+		mockCVClient        *cvMocks.MockClusterVersion
+		mockEMClient        *emMocks.MockEventManager
+		// upgradeconfig to be used during tests
+		upgradeConfig *upgradev1alpha1.UpgradeConfig
+
+		// upgrader to be used in testing
+		config   *upgraderConfig
+		upgrader *clusterUpgrader
+	)
+
+	setCondition := func(conditionType upgradev1alpha1.UpgradeConditionType, status corev1.ConditionStatus, startTime time.Time) {
+		history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+		history.Conditions.SetCondition(upgradev1alpha1.UpgradeCondition{
+			Type:      conditionType,
+			Status:    status,
+			StartTime: &metav1.Time{Time: startTime},
+		})
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+
+	BeforeEach(func() {
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(types.NamespacedName{
+			Name:      "test-upgradeconfig",
+			Namespace: "test-namespace",
+		}).WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockMetricsClient = mockMetrics.NewMockMetrics(mockCtrl)
+		mockMachineryClient = mockMachinery.NewMockMachinery(mockCtrl)
+		mockCVClient = cvMocks.NewMockClusterVersion(mockCtrl)
+		mockEMClient = emMocks.NewMockEventManager(mockCtrl)
+		logger = logf.Log.WithName("cluster upgrader test logger")
+		config = &upgraderConfig{
+			Canary: canaryConfig{
+				Pool: "worker-canary",
+			},
+		}
+		upgrader = &clusterUpgrader{
+			client:        mockKubeClient,
+			metrics:       mockMetricsClient,
+			cvClient:      mockCVClient,
+			notifier:      mockEMClient,
+			config:        config,
+			machinery:     mockMachineryClient,
+			upgradeConfig: upgradeConfig,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When canary rollout is disabled", func() {
+		BeforeEach(func() {
+			config.Canary = canaryConfig{}
+		})
+		It("completes the canary steps without doing anything", func() {
+			for _, step := range []func(context.Context, logr.Logger) (bool, error){
+				upgrader.CanaryWorkersUpgraded, upgrader.CanaryHealthCheck, upgrader.ReleaseWorkers,
+			} {
+				result, err := step(context.TODO(), logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+			}
+		})
+		It("does not hold the worker pool", func() {
+			setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue, time.Now())
+			Expect(upgrader.workersHeldForCanary()).To(BeFalse())
+		})
+	})
+
+	Context("When the canary workers are upgrading", func() {
+		It("waits for them to finish", func() {
+			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker-canary").Return(&machinery.UpgradingResult{IsUpgrading: true, UpdatedCount: 1, MachineCount: 2}, nil)
+			result, err := upgrader.CanaryWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+		It("reports an error checking the canary pool", func() {
+			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker-canary").Return(nil, fmt.Errorf("not found"))
+			result, err := upgrader.CanaryWorkersUpgraded(context.TODO(), logger)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+	})
+
+	Context("When the canary workers have upgraded", func() {
+		It("completes the step", func() {
+			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker-canary").Return(&machinery.UpgradingResult{IsUpgrading: false, UpdatedCount: 2, MachineCount: 2}, nil)
+			result, err := upgrader.CanaryWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+	})
+
+	Context("When running the canary health gate", func() {
+		var clusterVersion *configv1.ClusterVersion
+
+		BeforeEach(func() {
+			clusterVersion = &configv1.ClusterVersion{
+				Status: configv1.ClusterVersionStatus{
+					History: []configv1.UpdateHistory{
+						{State: configv1.CompletedUpdate, Version: "4.15.3"},
+					},
+				},
+			}
+			setCondition(upgradev1alpha1.CanaryHealthCheck, corev1.ConditionFalse, time.Now().Add(-time.Hour))
+		})
+
+		It("waits for the canary workers to soak", func() {
+			config.Canary.SoakTime = 30
+			setCondition(upgradev1alpha1.CanaryHealthCheck, corev1.ConditionFalse, time.Now())
+			result, err := upgrader.CanaryHealthCheck(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+
+		It("passes when the configured health checks pass", func() {
+			config.Canary.HealthChecks = []string{upgradev1alpha1.HealthCheckClusterOperators}
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{}}, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsStatusFailed, gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsDegraded, gomock.Any(), gomock.Any()),
+			)
+			result, err := upgrader.CanaryHealthCheck(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+
+		It("does not run the health checks which are disabled", func() {
+			config.HealthCheck.Checks = []healthCheckConfig{{Name: upgradev1alpha1.HealthCheckCriticalAlerts, Disabled: true}}
+			mockMetricsClient.EXPECT().Query(gomock.Any()).Times(0)
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{}}, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsStatusFailed, gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsDegraded, gomock.Any(), gomock.Any()),
+			)
+			result, err := upgrader.CanaryHealthCheck(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+
+		It("notifies and holds the workers when a health check fails", func() {
+			alerts := &metrics.AlertResponse{}
+			alerts.Data.Result = []metrics.AlertResult{{Metric: map[string]string{"alertname": "KubeNodeNotReady"}}}
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(alerts, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, metrics.CriticalAlertsFiring, gomock.Any(), gomock.Any()),
+				mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{}}, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsStatusFailed, gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsDegraded, gomock.Any(), gomock.Any()),
+				mockEMClient.EXPECT().NotifyResult(notifier.MuoStateCanaryHealthCheckSL, "CriticalAlertsHealthcheckFailed"),
+			)
+			result, err := upgrader.CanaryHealthCheck(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+	})
+
+	Context("When releasing the workers", func() {
+		It("unpauses the worker pool", func() {
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{}, nil),
+				mockMachineryClient.EXPECT().UnpauseMachineConfigPool(gomock.Any(), "worker"),
+			)
+			result, err := upgrader.ReleaseWorkers(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+	})
+
+	Context("When syncing the worker pool pause state", func() {
+		var upgradingResult *machinery.WorkerPoolsUpgradingResult
+
+		BeforeEach(func() {
+			upgradingResult = &machinery.WorkerPoolsUpgradingResult{
+				Pools: []machinery.PoolUpgradingResult{
+					{Name: "worker"},
+					{Name: "worker-canary", Paused: true, PausedByOperator: true},
+				},
+			}
+		})
+
+		It("does not hold the workers before the upgrade has commenced", func() {
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMachineryClient.EXPECT().UnpauseMachineConfigPool(gomock.Any(), "worker-canary"),
+			)
+			Expect(upgrader.syncWorkerPoolPause(logger)).To(Succeed())
+		})
+
+		It("holds the workers once the upgrade has commenced", func() {
+			setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue, time.Now())
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().PauseMachineConfigPool(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().UnpauseMachineConfigPool(gomock.Any(), "worker-canary"),
+			)
+			Expect(upgrader.syncWorkerPoolPause(logger)).To(Succeed())
+		})
+
+		It("stops holding the workers once they are released", func() {
+			setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue, time.Now())
+			setCondition(upgradev1alpha1.ReleaseWorkerNodes, corev1.ConditionTrue, time.Now())
+			upgradingResult.Pools[0].Paused = true
+			upgradingResult.Pools[0].PausedByOperator = true
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMachineryClient.EXPECT().UnpauseMachineConfigPool(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().UnpauseMachineConfigPool(gomock.Any(), "worker-canary"),
+			)
+			Expect(upgrader.syncWorkerPoolPause(logger)).To(Succeed())
+		})
+
+		It("pauses the pools which are not yet paused while the upgrade is paused", func() {
+			upgradeConfig.Spec.Paused = true
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().PauseMachineConfigPool(gomock.Any(), "worker"),
+			)
+			Expect(upgrader.syncWorkerPoolPause(logger)).To(Succeed())
+		})
+
+		It("does not pause a pool whose maxUnavailable cannot be restored", func() {
+			upgradeConfig.Spec.Paused = true
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "worker").Return(fmt.Errorf("fake error")),
+			)
+			Expect(upgrader.syncWorkerPoolPause(logger)).NotTo(Succeed())
+		})
+
+		It("does not unpause a pool paused by someone else", func() {
+			upgradingResult.Pools[0].Paused = true
+			upgradingResult.Pools[1].PausedByOperator = false
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil)
+			Expect(upgrader.syncWorkerPoolPause(logger)).To(Succeed())
+		})
+
+		It("does not hold the canary pool for the pool order", func() {
+			config.MachineConfigPools.Order = []string{"infra"}
+			setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue, time.Now())
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().PauseMachineConfigPool(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().UnpauseMachineConfigPool(gomock.Any(), "worker-canary"),
+			)
+			Expect(upgrader.syncWorkerPoolPause(logger)).To(Succeed())
+		})
+	})
+})
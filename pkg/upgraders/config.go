@@ -2,11 +2,19 @@ package upgraders
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"path"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
 	ac "github.com/openshift/managed-upgrade-operator/pkg/availabilitychecks"
 	"github.com/openshift/managed-upgrade-operator/pkg/drain"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
 )
 
 type upgraderConfig struct {
@@ -18,6 +26,11 @@ type upgraderConfig struct {
 	UpgradeWindow                  upgradeWindow                     `yaml:"upgradeWindow"`
 	Environment                    environment                       `yaml:"environment"`
 	FeatureGate                    featureGate                       `yaml:"featureGate"`
+	StepTimeouts                   []stepTimeout                     `yaml:"stepTimeouts"`
+	CustomSteps                    []customStep                      `yaml:"customSteps"`
+	Canary                         canaryConfig                      `yaml:"canary"`
+	MachineConfigPools             machineConfigPoolsConfig          `yaml:"machineConfigPools"`
+	ControlPlaneSoak               controlPlaneSoakConfig            `yaml:"controlPlaneSoak"`
 }
 
 type featureGate struct {
@@ -92,8 +105,71 @@ type scaleConfig struct {
 }
 
 type healthCheck struct {
-	IgnoredCriticals  []string `yaml:"ignoredCriticals"`
-	IgnoredNamespaces []string `yaml:"ignoredNamespaces"`
+	IgnoredCriticals  []string            `yaml:"ignoredCriticals"`
+	IgnoredNamespaces []string            `yaml:"ignoredNamespaces"`
+	Checks            []healthCheckConfig `yaml:"checks"`
+}
+
+// healthCheckConfig tunes how one of the registered health checks runs
+type healthCheckConfig struct {
+	Name     string              `yaml:"name"`
+	Disabled bool                `yaml:"disabled"`
+	Phases   []healthCheckPhase  `yaml:"phases"`
+	Severity healthCheckSeverity `yaml:"severity"`
+}
+
+func (cfg *healthCheck) IsValid() error {
+	seen := make(map[string]bool)
+	for _, hc := range cfg.Checks {
+		if getHealthCheckRegistration(hc.Name) == nil {
+			return fmt.Errorf("config healthCheck check %q is invalid", hc.Name)
+		}
+		if seen[hc.Name] {
+			return fmt.Errorf("config healthCheck check %q is configured more than once", hc.Name)
+		}
+		seen[hc.Name] = true
+		for _, phase := range hc.Phases {
+			if phase != healthCheckPhasePreSchedule && phase != healthCheckPhasePreUpgrade && phase != healthCheckPhasePostUpgrade {
+				return fmt.Errorf("config healthCheck check %q phase %q is invalid", hc.Name, phase)
+			}
+		}
+		if hc.Severity != "" && hc.Severity != healthCheckSeverityBlock && hc.Severity != healthCheckSeverityWarn {
+			return fmt.Errorf("config healthCheck check %q severity %q is invalid", hc.Name, hc.Severity)
+		}
+	}
+	return nil
+}
+
+// getCheckConfig returns how the registered health check is configured to run,
+// falling back to its registered phases and severity
+func (cfg *healthCheck) getCheckConfig(registration healthCheckRegistration) healthCheckConfig {
+	result := healthCheckConfig{Name: registration.name}
+	for _, hc := range cfg.Checks {
+		if hc.Name == registration.name {
+			result = hc
+			break
+		}
+	}
+	if len(result.Phases) == 0 {
+		result.Phases = registration.phases
+	}
+	if result.Severity == "" {
+		result.Severity = registration.severity
+	}
+	return result
+}
+
+// runsIn returns whether the health check is enabled for the phase
+func (cfg healthCheckConfig) runsIn(phase healthCheckPhase) bool {
+	if cfg.Disabled {
+		return false
+	}
+	for _, p := range cfg.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
 }
 
 func (cfg *upgraderConfig) IsValid() error {
@@ -103,6 +179,9 @@ func (cfg *upgraderConfig) IsValid() error {
 	if err := cfg.Scale.IsValid(); err != nil {
 		return err
 	}
+	if err := cfg.HealthCheck.IsValid(); err != nil {
+		return err
+	}
 	if cfg.NodeDrain.Timeout <= 0 {
 		return fmt.Errorf("config nodeDrain timeOut is invalid")
 	}
@@ -118,6 +197,28 @@ func (cfg *upgraderConfig) IsValid() error {
 	if len(cfg.ExtDependencyAvailabilityCheck.HTTP.URLS) > 0 && cfg.ExtDependencyAvailabilityCheck.HTTP.Timeout <= 0 || cfg.ExtDependencyAvailabilityCheck.HTTP.Timeout > 60 {
 		return fmt.Errorf("config HTTP timeout is invalid (Requires int between 1 - 60 inclusive)")
 	}
+	for _, st := range cfg.StepTimeouts {
+		if err := st.IsValid(); err != nil {
+			return err
+		}
+	}
+	for _, cs := range cfg.CustomSteps {
+		if err := cs.IsValid(); err != nil {
+			return err
+		}
+	}
+	if err := cfg.Canary.IsValid(); err != nil {
+		return err
+	}
+	if err := cfg.MachineConfigPools.IsValid(); err != nil {
+		return err
+	}
+	if err := cfg.ControlPlaneSoak.IsValid(); err != nil {
+		return err
+	}
+	if cfg.Canary.IsEnabled() && cfg.MachineConfigPools.GetPosition(cfg.Canary.Pool) < len(cfg.MachineConfigPools.Order) {
+		return fmt.Errorf("config machineConfigPools order must not contain the canary pool %s", cfg.Canary.Pool)
+	}
 	return nil
 }
 
@@ -125,6 +226,128 @@ func (cfg *upgraderConfig) GetScaleDuration() time.Duration {
 	return time.Duration(cfg.Scale.TimeOut) * time.Minute
 }
 
+// stepTimeout is the maximum duration of an upgrade step, in minutes, and the
+// policy applied to the step once it has been exceeded
+type stepTimeout struct {
+	Step    string                     `yaml:"step"`
+	TimeOut int                        `yaml:"timeOut"`
+	Policy  upgradesteps.TimeoutPolicy `yaml:"policy"`
+}
+
+func (cfg *stepTimeout) IsValid() error {
+	if cfg.Step == "" {
+		return fmt.Errorf("config stepTimeouts step is required")
+	}
+	if cfg.TimeOut < 0 {
+		return fmt.Errorf("config stepTimeouts timeOut for step %s is invalid", cfg.Step)
+	}
+	if !cfg.Policy.IsValid() {
+		return fmt.Errorf("config stepTimeouts policy %q for step %s is invalid", cfg.Policy, cfg.Step)
+	}
+	return nil
+}
+
+// GetStepTimeout returns the maximum duration of the named upgrade step and the
+// policy to apply once it has been exceeded. Step timeouts are opt-in: steps
+// which are not configured in stepTimeouts, or which are configured with a zero
+// duration, are unbounded.
+func (cfg *upgraderConfig) GetStepTimeout(step string) (time.Duration, upgradesteps.TimeoutPolicy) {
+	for _, st := range cfg.StepTimeouts {
+		if st.Step == step {
+			return time.Duration(st.TimeOut) * time.Minute, st.Policy
+		}
+	}
+	return 0, ""
+}
+
+// customStep is an operator-defined upgrade step which is inserted into the
+// upgrader's step list before or after one of its built-in steps
+type customStep struct {
+	Name   string          `yaml:"name"`
+	Before string          `yaml:"before"`
+	After  string          `yaml:"after"`
+	HTTP   *customStepHTTP `yaml:"http"`
+	Job    *customStepJob  `yaml:"job"`
+}
+
+// customStepHTTP configures a custom step which calls an HTTP endpoint
+type customStepHTTP struct {
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method" default:"GET"`
+	Headers map[string]string `yaml:"headers"`
+	// HeadersSecretName names a Secret in the operator namespace whose keys and values are sent as
+	// additional headers, for headers holding credentials
+	HeadersSecretName string `yaml:"headersSecretName"`
+	Timeout           int    `yaml:"timeout" default:"15"`
+}
+
+// customStepJob configures a custom step which runs a Kubernetes Job
+type customStepJob struct {
+	Image              string   `yaml:"image"`
+	Command            []string `yaml:"command"`
+	Args               []string `yaml:"args"`
+	ServiceAccountName string   `yaml:"serviceAccountName"`
+	BackoffLimit       int32    `yaml:"backoffLimit"`
+	// Attempts is the number of Jobs run before the step fails the upgrade
+	Attempts int `yaml:"attempts" default:"3"`
+}
+
+func (cfg *customStep) IsValid() error {
+	if errs := validation.IsValidLabelValue(cfg.Name); cfg.Name == "" || len(errs) > 0 {
+		return fmt.Errorf("config customSteps name %q is invalid", cfg.Name)
+	}
+	if (cfg.Before == "") == (cfg.After == "") {
+		return fmt.Errorf("config customSteps step %s requires exactly one of before or after", cfg.Name)
+	}
+	if (cfg.HTTP == nil) == (cfg.Job == nil) {
+		return fmt.Errorf("config customSteps step %s requires exactly one of http or job", cfg.Name)
+	}
+	if cfg.HTTP != nil {
+		if _, err := url.ParseRequestURI(cfg.HTTP.URL); err != nil {
+			return fmt.Errorf("config customSteps step %s has an invalid url: %w", cfg.Name, err)
+		}
+		if cfg.HTTP.Timeout < 0 {
+			return fmt.Errorf("config customSteps step %s http timeout is invalid", cfg.Name)
+		}
+	}
+	if cfg.Job != nil {
+		if cfg.Job.Image == "" {
+			return fmt.Errorf("config customSteps step %s requires a job image", cfg.Name)
+		}
+		if cfg.Job.BackoffLimit < 0 {
+			return fmt.Errorf("config customSteps step %s job backoffLimit is invalid", cfg.Name)
+		}
+		if cfg.Job.Attempts < 0 {
+			return fmt.Errorf("config customSteps step %s job attempts is invalid", cfg.Name)
+		}
+	}
+	return nil
+}
+
+// GetMethod returns the HTTP method used to call the endpoint
+func (cfg *customStepHTTP) GetMethod() string {
+	if cfg.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(cfg.Method)
+}
+
+// GetTimeoutDuration returns the timeout of a call to the endpoint
+func (cfg *customStepHTTP) GetTimeoutDuration() time.Duration {
+	if cfg.Timeout == 0 {
+		return 15 * time.Second
+	}
+	return time.Duration(cfg.Timeout) * time.Second
+}
+
+// GetAttempts returns the number of Jobs run before the step fails the upgrade
+func (cfg *customStepJob) GetAttempts() int {
+	if cfg.Attempts == 0 {
+		return 3
+	}
+	return cfg.Attempts
+}
+
 type environment struct {
 	Fedramp bool `yaml:"fedramp"`
 }
@@ -132,3 +355,160 @@ type environment struct {
 func (cfg *environment) IsFedramp() bool {
 	return cfg.Fedramp
 }
+
+// canaryConfig configures the canary rollout of worker nodes, in which the
+// nodes of a dedicated MachineConfigPool are upgraded and health checked
+// before the worker pool is allowed to upgrade
+type canaryConfig struct {
+	Pool         string   `yaml:"pool"`
+	SoakTime     int      `yaml:"soakTime"`
+	HealthChecks []string `yaml:"healthChecks"`
+}
+
+func (cfg *canaryConfig) IsValid() error {
+	if cfg.Pool == "" {
+		return nil
+	}
+	if cfg.Pool == machinery.WorkerPool || cfg.Pool == machinery.MasterPool {
+		return fmt.Errorf("config canary pool must not be the %s pool", cfg.Pool)
+	}
+	if cfg.SoakTime < 0 {
+		return fmt.Errorf("config canary soakTime is invalid")
+	}
+	for _, hc := range cfg.HealthChecks {
+		if getHealthCheckRegistration(hc) == nil {
+			return fmt.Errorf("config canary healthCheck %q is invalid", hc)
+		}
+	}
+	return nil
+}
+
+// IsEnabled returns whether worker nodes are to be rolled out through a canary pool
+func (cfg *canaryConfig) IsEnabled() bool {
+	return cfg.Pool != ""
+}
+
+// GetSoakDuration returns how long the canary nodes must have been upgraded
+// before the health gate is evaluated
+func (cfg *canaryConfig) GetSoakDuration() time.Duration {
+	return time.Duration(cfg.SoakTime) * time.Minute
+}
+
+// GetHealthChecks returns the health checks making up the canary health gate,
+// defaulting to the critical alerts and ClusterOperators health checks
+func (cfg *canaryConfig) GetHealthChecks() []string {
+	if len(cfg.HealthChecks) == 0 {
+		return []string{upgradev1alpha1.HealthCheckCriticalAlerts, upgradev1alpha1.HealthCheckClusterOperators}
+	}
+	return cfg.HealthChecks
+}
+
+// controlPlaneSoakConfig configures the soak of the upgraded control plane, during
+// which the worker MachineConfigPools are held paused and the upgrade is failed if
+// the control plane regresses
+type controlPlaneSoakConfig struct {
+	SoakTime              int     `yaml:"soakTime"`
+	APIErrorRateThreshold float64 `yaml:"apiErrorRateThreshold"`
+	// RegressionTime is how long, in minutes, a regression must persist before the upgrade is failed
+	RegressionTime int `yaml:"regressionTime"`
+}
+
+func (cfg *controlPlaneSoakConfig) IsValid() error {
+	if cfg.SoakTime < 0 {
+		return fmt.Errorf("config controlPlaneSoak soakTime is invalid")
+	}
+	if cfg.APIErrorRateThreshold < 0 || cfg.APIErrorRateThreshold > 1 {
+		return fmt.Errorf("config controlPlaneSoak apiErrorRateThreshold must be between 0 and 1")
+	}
+	if cfg.RegressionTime < 0 {
+		return fmt.Errorf("config controlPlaneSoak regressionTime is invalid")
+	}
+	return nil
+}
+
+// IsEnabled returns whether the upgraded control plane is to soak before the workers upgrade
+func (cfg *controlPlaneSoakConfig) IsEnabled() bool {
+	return cfg.SoakTime > 0
+}
+
+// GetSoakDuration returns how long the upgraded control plane must soak
+func (cfg *controlPlaneSoakConfig) GetSoakDuration() time.Duration {
+	return time.Duration(cfg.SoakTime) * time.Minute
+}
+
+// GetRegressionDuration returns how long the control plane must have regressed
+// for before the upgrade is failed. Defaults to the soak duration.
+func (cfg *controlPlaneSoakConfig) GetRegressionDuration() time.Duration {
+	if cfg.RegressionTime == 0 {
+		return cfg.GetSoakDuration()
+	}
+	return time.Duration(cfg.RegressionTime) * time.Minute
+}
+
+// machineConfigPoolsConfig configures how the worker MachineConfigPools are upgraded
+type machineConfigPoolsConfig struct {
+	Order []string `yaml:"order"`
+	// MaxUnavailable bounds the maxUnavailable each worker MachineConfigPool is tuned to while it upgrades
+	MaxUnavailable maxUnavailableConfig `yaml:"maxUnavailable"`
+}
+
+func (cfg *machineConfigPoolsConfig) IsValid() error {
+	if err := cfg.MaxUnavailable.IsValid(); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, pool := range cfg.Order {
+		if !machinery.IsWorkerPool(pool) {
+			return fmt.Errorf("config machineConfigPools order must not contain the %s pool", pool)
+		}
+		if seen[pool] {
+			return fmt.Errorf("config machineConfigPools order contains %s more than once", pool)
+		}
+		seen[pool] = true
+	}
+	return nil
+}
+
+// GetPosition returns the position of the MachineConfigPool in the upgrade order.
+// Pools which are not listed are upgraded after all of the listed pools.
+func (cfg *machineConfigPoolsConfig) GetPosition(pool string) int {
+	for i, p := range cfg.Order {
+		if p == pool {
+			return i
+		}
+	}
+	return len(cfg.Order)
+}
+
+// maxUnavailableConfig bounds the maxUnavailable the worker MachineConfigPool is tuned to, according to
+// the spare capacity of the worker nodes, while it upgrades. The pool is not tuned if Max is 0.
+type maxUnavailableConfig struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+func (cfg *maxUnavailableConfig) IsValid() error {
+	if !cfg.IsEnabled() {
+		if cfg.Min != 0 {
+			return fmt.Errorf("config machineConfigPools maxUnavailable max must be set if min is set")
+		}
+		return nil
+	}
+	if cfg.Min < 1 {
+		return fmt.Errorf("config machineConfigPools maxUnavailable min must be at least 1")
+	}
+	if cfg.Min > cfg.Max {
+		return fmt.Errorf("config machineConfigPools maxUnavailable min must not be greater than max")
+	}
+	return nil
+}
+
+// IsEnabled returns whether the maxUnavailable of the worker MachineConfigPool is tuned
+func (cfg *maxUnavailableConfig) IsEnabled() bool {
+	return cfg.Max > 0
+}
+
+// Bound returns the maxUnavailable bounded by the configured minimum and maximum
+func (cfg *maxUnavailableConfig) Bound(maxUnavailable int) int {
+	return min(max(maxUnavailable, cfg.Min), cfg.Max)
+}
@@ -1,8 +1,13 @@
 package upgraders
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
 )
 
 var _ = Describe("scaleConfig", func() {
@@ -114,3 +119,209 @@ var _ = Describe("upgraderConfig", func() {
 		})
 	})
 })
+
+var _ = Describe("stepTimeout", func() {
+	Describe("IsValid", func() {
+		It("returns no error for a valid step timeout", func() {
+			cfg := &stepTimeout{Step: "ExternalDependenciesAvailable", TimeOut: 60, Policy: upgradesteps.TimeoutPolicyFail}
+			Expect(cfg.IsValid()).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the step is missing", func() {
+			cfg := &stepTimeout{TimeOut: 60, Policy: upgradesteps.TimeoutPolicyFail}
+			Expect(cfg.IsValid()).To(HaveOccurred())
+		})
+
+		It("returns an error when TimeOut is negative", func() {
+			cfg := &stepTimeout{Step: "ExternalDependenciesAvailable", TimeOut: -1, Policy: upgradesteps.TimeoutPolicyFail}
+			Expect(cfg.IsValid()).To(HaveOccurred())
+		})
+
+		It("returns an error for an unknown policy", func() {
+			cfg := &stepTimeout{Step: "ExternalDependenciesAvailable", TimeOut: 60, Policy: "Ignore"}
+			err := cfg.IsValid()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Ignore"))
+		})
+	})
+})
+
+var _ = Describe("GetStepTimeout", func() {
+	var cfg *upgraderConfig
+
+	BeforeEach(func() {
+		cfg = &upgraderConfig{}
+	})
+
+	It("leaves steps which are not configured unbounded", func() {
+		for _, step := range []upgradev1alpha1.UpgradeConditionType{
+			upgradev1alpha1.ExtDepAvailabilityCheck,
+			upgradev1alpha1.UpgradeScaleUpExtraNodes,
+			upgradev1alpha1.ControlPlaneUpgraded,
+		} {
+			timeout, _ := cfg.GetStepTimeout(string(step))
+			Expect(timeout).To(BeZero())
+		}
+	})
+
+	It("prefers the configured step timeout", func() {
+		cfg.StepTimeouts = []stepTimeout{
+			{Step: string(upgradev1alpha1.ExtDepAvailabilityCheck), TimeOut: 15, Policy: upgradesteps.TimeoutPolicyRetry},
+		}
+		timeout, policy := cfg.GetStepTimeout(string(upgradev1alpha1.ExtDepAvailabilityCheck))
+		Expect(timeout).To(Equal(15 * time.Minute))
+		Expect(policy).To(Equal(upgradesteps.TimeoutPolicyRetry))
+	})
+})
+
+var _ = Describe("healthCheck", func() {
+	Describe("IsValid", func() {
+		It("returns no error when no checks are configured", func() {
+			cfg := healthCheck{}
+			Expect(cfg.IsValid()).To(Succeed())
+		})
+		It("returns no error for valid checks", func() {
+			cfg := healthCheck{Checks: []healthCheckConfig{
+				{Name: "PodDisruptionBudgets", Phases: []healthCheckPhase{"PreUpgrade"}, Severity: "Block"},
+				{Name: "ManuallyCordonedNodes", Disabled: true},
+			}}
+			Expect(cfg.IsValid()).To(Succeed())
+		})
+		It("returns an error for an unknown check", func() {
+			cfg := healthCheck{Checks: []healthCheckConfig{{Name: "NodeReady"}}}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+		It("returns an error for a check configured more than once", func() {
+			cfg := healthCheck{Checks: []healthCheckConfig{{Name: "CriticalAlerts"}, {Name: "CriticalAlerts"}}}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+		It("returns an error for an unknown phase", func() {
+			cfg := healthCheck{Checks: []healthCheckConfig{{Name: "CriticalAlerts", Phases: []healthCheckPhase{"DuringUpgrade"}}}}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+		It("returns an error for an unknown severity", func() {
+			cfg := healthCheck{Checks: []healthCheckConfig{{Name: "CriticalAlerts", Severity: "Fatal"}}}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+	})
+
+	It("defaults to the registered phases and severity", func() {
+		cfg := healthCheck{Checks: []healthCheckConfig{{Name: "PodDisruptionBudgets", Severity: "Block"}}}
+		check := cfg.getCheckConfig(*getHealthCheckRegistration("PodDisruptionBudgets"))
+		Expect(check.Severity).To(Equal(healthCheckSeverityBlock))
+		Expect(check.runsIn(healthCheckPhasePreUpgrade)).To(BeTrue())
+		Expect(check.runsIn(healthCheckPhasePostUpgrade)).To(BeFalse())
+
+		check = cfg.getCheckConfig(*getHealthCheckRegistration("ManuallyCordonedNodes"))
+		Expect(check.Severity).To(Equal(healthCheckSeverityWarn))
+	})
+
+	It("does not run disabled checks", func() {
+		cfg := healthCheck{Checks: []healthCheckConfig{{Name: "CriticalAlerts", Disabled: true}}}
+		check := cfg.getCheckConfig(*getHealthCheckRegistration("CriticalAlerts"))
+		Expect(check.runsIn(healthCheckPhasePreUpgrade)).To(BeFalse())
+	})
+})
+
+var _ = Describe("canaryConfig", func() {
+	Describe("IsValid", func() {
+		It("returns no error when the canary rollout is disabled", func() {
+			cfg := canaryConfig{}
+			Expect(cfg.IsValid()).To(Succeed())
+			Expect(cfg.IsEnabled()).To(BeFalse())
+		})
+		It("returns no error for a valid canary rollout", func() {
+			cfg := canaryConfig{Pool: "worker-canary", SoakTime: 15, HealthChecks: []string{"CriticalAlerts"}}
+			Expect(cfg.IsValid()).To(Succeed())
+			Expect(cfg.IsEnabled()).To(BeTrue())
+		})
+		It("returns an error when the pool is the worker pool", func() {
+			cfg := canaryConfig{Pool: "worker"}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+		It("returns an error when soakTime is negative", func() {
+			cfg := canaryConfig{Pool: "worker-canary", SoakTime: -1}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+		It("returns an error for an unknown health check", func() {
+			cfg := canaryConfig{Pool: "worker-canary", HealthChecks: []string{"NodeReady"}}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		})
+	})
+
+	It("defaults to all health checks", func() {
+		cfg := canaryConfig{Pool: "worker-canary"}
+		Expect(cfg.GetHealthChecks()).To(ConsistOf(upgradev1alpha1.HealthCheckCriticalAlerts, upgradev1alpha1.HealthCheckClusterOperators))
+	})
+})
+
+var _ = Describe("machineConfigPoolsConfig", func() {
+	It("returns no error for a valid order", func() {
+		cfg := machineConfigPoolsConfig{Order: []string{"infra", "worker"}}
+		Expect(cfg.IsValid()).To(Succeed())
+	})
+	It("returns an error when the order contains the master pool", func() {
+		cfg := machineConfigPoolsConfig{Order: []string{"master", "worker"}}
+		Expect(cfg.IsValid()).NotTo(Succeed())
+	})
+	It("returns an error when the order contains a pool more than once", func() {
+		cfg := machineConfigPoolsConfig{Order: []string{"infra", "infra"}}
+		Expect(cfg.IsValid()).NotTo(Succeed())
+	})
+	It("places unlisted pools after the listed pools", func() {
+		cfg := machineConfigPoolsConfig{Order: []string{"infra", "worker"}}
+		Expect(cfg.GetPosition("infra")).To(Equal(0))
+		Expect(cfg.GetPosition("worker")).To(Equal(1))
+		Expect(cfg.GetPosition("gpu")).To(Equal(2))
+	})
+	It("returns no error when the maxUnavailable is not tuned", func() {
+		cfg := machineConfigPoolsConfig{}
+		Expect(cfg.IsValid()).To(Succeed())
+		Expect(cfg.MaxUnavailable.IsEnabled()).To(BeFalse())
+	})
+	It("returns an error when the maxUnavailable bounds are invalid", func() {
+		for _, bounds := range []maxUnavailableConfig{{Min: 1}, {Min: 0, Max: 3}, {Min: 4, Max: 3}, {Min: -1, Max: 3}} {
+			cfg := machineConfigPoolsConfig{MaxUnavailable: bounds}
+			Expect(cfg.IsValid()).NotTo(Succeed())
+		}
+	})
+	It("bounds the maxUnavailable", func() {
+		cfg := maxUnavailableConfig{Min: 2, Max: 5}
+		Expect(cfg.IsValid()).To(Succeed())
+		Expect(cfg.Bound(0)).To(Equal(2))
+		Expect(cfg.Bound(3)).To(Equal(3))
+		Expect(cfg.Bound(9)).To(Equal(5))
+	})
+})
+
+var _ = Describe("controlPlaneSoakConfig", func() {
+	It("returns no error when the soak is disabled", func() {
+		cfg := controlPlaneSoakConfig{}
+		Expect(cfg.IsValid()).To(Succeed())
+		Expect(cfg.IsEnabled()).To(BeFalse())
+	})
+	It("returns no error for a valid soak", func() {
+		cfg := controlPlaneSoakConfig{SoakTime: 30, APIErrorRateThreshold: 0.05}
+		Expect(cfg.IsValid()).To(Succeed())
+		Expect(cfg.IsEnabled()).To(BeTrue())
+		Expect(cfg.GetSoakDuration()).To(Equal(30 * time.Minute))
+		Expect(cfg.GetRegressionDuration()).To(Equal(30 * time.Minute))
+	})
+	It("uses the configured regression time", func() {
+		cfg := controlPlaneSoakConfig{SoakTime: 30, RegressionTime: 10}
+		Expect(cfg.IsValid()).To(Succeed())
+		Expect(cfg.GetRegressionDuration()).To(Equal(10 * time.Minute))
+	})
+	It("returns an error when regressionTime is negative", func() {
+		cfg := controlPlaneSoakConfig{SoakTime: 30, RegressionTime: -1}
+		Expect(cfg.IsValid()).NotTo(Succeed())
+	})
+	It("returns an error when soakTime is negative", func() {
+		cfg := controlPlaneSoakConfig{SoakTime: -1}
+		Expect(cfg.IsValid()).NotTo(Succeed())
+	})
+	It("returns an error when the API error rate threshold is not a ratio", func() {
+		cfg := controlPlaneSoakConfig{SoakTime: 30, APIErrorRateThreshold: 5}
+		Expect(cfg.IsValid()).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,144 @@
+package upgraders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
+)
+
+// apiServerErrorRateWindow is the window over which the API server error rate is measured
+const apiServerErrorRateWindow = 5 * time.Minute
+
+// ControlPlaneSoak holds the worker MachineConfigPools paused while the upgraded control
+// plane soaks. If the control plane regresses during the soak, the worker pools are
+// left paused and the upgrade is failed rather than rolling the workers onto it. A
+// regression must persist for the configured regression time before the upgrade is
+// failed, so that a briefly firing alert or degraded ClusterOperator does not fail it.
+// A health check which cannot query the cluster is not counted as a regression, and
+// its result is cleared so that the regression time restarts once it can.
+func (c *clusterUpgrader) ControlPlaneSoak(ctx context.Context, logger logr.Logger) (bool, error) {
+	if !c.config.ControlPlaneSoak.IsEnabled() {
+		return true, nil
+	}
+
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return false, nil
+	}
+
+	version := getCurrentVersion(c.cvClient, logger)
+	regressions := []string{}
+
+	ok, err := CriticalAlerts(c.metrics, c.config, c.upgradeConfig, upgradev1alpha1.ControlPlaneSoak, logger, version)
+	if err != nil || !ok {
+		if isHealthCheckQueryError(err) {
+			clearHealthCheck(c.upgradeConfig, upgradev1alpha1.ControlPlaneSoak, upgradev1alpha1.HealthCheckCriticalAlerts)
+			return false, err
+		}
+		regressions = append(regressions, healthCheckError(upgradev1alpha1.HealthCheckCriticalAlerts, err).Error())
+	}
+
+	ok, err = ClusterOperators(c.metrics, c.cvClient, c.upgradeConfig, upgradev1alpha1.ControlPlaneSoak, logger, version)
+	if err != nil || !ok {
+		if isHealthCheckQueryError(err) {
+			clearHealthCheck(c.upgradeConfig, upgradev1alpha1.ControlPlaneSoak, upgradev1alpha1.HealthCheckClusterOperators)
+			return false, err
+		}
+		regressions = append(regressions, healthCheckError(upgradev1alpha1.HealthCheckClusterOperators, err).Error())
+	}
+
+	if threshold := c.config.ControlPlaneSoak.APIErrorRateThreshold; threshold > 0 {
+		rate, err := c.metrics.APIServerErrorRate(apiServerErrorRateWindow)
+		if err != nil {
+			return false, err
+		}
+		if rate > threshold {
+			err = fmt.Errorf("API server error rate of %.2f%% exceeds %.2f%%", rate*100, threshold*100)
+			recordHealthCheckFailed(c.upgradeConfig, upgradev1alpha1.ControlPlaneSoak, upgradev1alpha1.HealthCheckAPIServerErrorRate, metrics.APIServerErrorRateHigh, err, nil)
+			regressions = append(regressions, err.Error())
+		} else {
+			recordHealthCheckPassed(c.upgradeConfig, upgradev1alpha1.ControlPlaneSoak, upgradev1alpha1.HealthCheckAPIServerErrorRate)
+		}
+	}
+
+	if len(regressions) > 0 {
+		result := strings.Join(regressions, "; ")
+		regressedFor := c.regressedFor()
+		if regressedFor < c.config.ControlPlaneSoak.GetRegressionDuration() {
+			logger.Info(fmt.Sprintf("Control plane has regressed during soak for %s, holding worker pools: %s", regressedFor.Round(time.Second), result))
+			return false, nil
+		}
+		logger.Info(fmt.Sprintf("Control plane regressed during soak, failing upgrade: %s", result))
+		if err := c.pauseWorkerPools(logger); err != nil {
+			return false, err
+		}
+		return false, upgradesteps.FailUpgrade(fmt.Errorf("control plane regressed after upgrade: %s", result))
+	}
+
+	condition := history.Conditions.GetCondition(upgradev1alpha1.ControlPlaneSoak)
+	if condition != nil && condition.StartTime != nil {
+		soakEnd := condition.StartTime.Add(c.config.ControlPlaneSoak.GetSoakDuration())
+		if time.Now().Before(soakEnd) {
+			logger.Info(fmt.Sprintf("control plane is soaking until %s", soakEnd.Format(time.RFC3339)))
+			return false, nil
+		}
+	}
+
+	logger.Info("Control plane soak passed")
+	return true, nil
+}
+
+// pauseWorkerPools pauses every worker MachineConfigPool
+func (c *clusterUpgrader) pauseWorkerPools(logger logr.Logger) error {
+	upgradingResult, err := c.machinery.IsWorkerPoolsUpgrading(c.client)
+	if err != nil {
+		return err
+	}
+	for _, pool := range upgradingResult.Pools {
+		logger.Info(fmt.Sprintf("Pausing MachineConfigPool %s", pool.Name))
+		if err := c.machinery.PauseMachineConfigPool(c.client, pool.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workersHeldForSoak returns whether the worker MachineConfigPools must be held paused
+// while the control plane soaks. Workers are held from when the upgrade has commenced
+// until the soak has passed.
+func (c *clusterUpgrader) workersHeldForSoak() bool {
+	if !c.config.ControlPlaneSoak.IsEnabled() {
+		return false
+	}
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil || history.Conditions.GetCondition(upgradev1alpha1.CommenceUpgrade) == nil {
+		return false
+	}
+	return !history.Conditions.IsTrueFor(upgradev1alpha1.ControlPlaneSoak)
+}
+
+// regressedFor returns how long the longest-failing health check run by the control
+// plane soak has been failing for
+func (c *clusterUpgrader) regressedFor() time.Duration {
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return 0
+	}
+	var regressedFor time.Duration
+	for _, hc := range history.HealthChecks {
+		if hc.Step != upgradev1alpha1.ControlPlaneSoak || hc.Result != upgradev1alpha1.HealthCheckFailed || hc.LastTransitionTime == nil {
+			continue
+		}
+		if d := time.Since(hc.LastTransitionTime.Time); d > regressedFor {
+			regressedFor = d
+		}
+	}
+	return regressedFor
+}
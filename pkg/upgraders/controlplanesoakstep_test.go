@@ -0,0 +1,269 @@
+package upgraders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/clusterversion"
+	cvMocks "github.com/openshift/managed-upgrade-operator/pkg/clusterversion/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	mockMachinery "github.com/openshift/managed-upgrade-operator/pkg/machinery/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("ControlPlaneSoakStep", func() {
+	var (
+		logger logr.Logger
+		// mocks
+		mockKubeClient      *mocks.MockClient
+		mockCtrl            *gomock.Controller
+		mockMachineryClient *mockMachinery.MockMachinery
+		mockMetricsClient   *mockMetrics.MockMetrics
+		mockCVClient        *cvMocks.MockClusterVersion
+		// upgradeconfig to be used during tests
+		upgradeConfig  *upgradev1alpha1.UpgradeConfig
+		clusterVersion *configv1.ClusterVersion
+
+		// upgrader to be used in testing
+		config   *upgraderConfig
+		upgrader *clusterUpgrader
+	)
+
+	setCondition := func(conditionType upgradev1alpha1.UpgradeConditionType, status corev1.ConditionStatus, startTime time.Time) {
+		history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+		history.Conditions.SetCondition(upgradev1alpha1.UpgradeCondition{
+			Type:      conditionType,
+			Status:    status,
+			StartTime: &metav1.Time{Time: startTime},
+		})
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+
+	setFailedSince := func(name string, since time.Time) {
+		history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+		history.SetHealthCheck(upgradev1alpha1.HealthCheckResult{
+			Name:               name,
+			Step:               upgradev1alpha1.ControlPlaneSoak,
+			Result:             upgradev1alpha1.HealthCheckFailed,
+			LastTransitionTime: &metav1.Time{Time: since},
+		})
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+
+	expectHealthy := func() {
+		gomock.InOrder(
+			mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+			mockMetricsClient.EXPECT().Query(gomock.Any()).Return(&metrics.AlertResponse{}, nil),
+			mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.MetricsQueryFailed, gomock.Any(), gomock.Any()),
+			mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.CriticalAlertsFiring, gomock.Any(), gomock.Any()),
+			mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{}}, nil),
+			mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsStatusFailed, gomock.Any(), gomock.Any()),
+			mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsDegraded, gomock.Any(), gomock.Any()),
+		)
+	}
+
+	BeforeEach(func() {
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(types.NamespacedName{
+			Name:      "test-upgradeconfig",
+			Namespace: "test-namespace",
+		}).WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+		clusterVersion = &configv1.ClusterVersion{
+			Status: configv1.ClusterVersionStatus{
+				History: []configv1.UpdateHistory{
+					{State: configv1.CompletedUpdate, Version: "4.15.3"},
+				},
+			},
+		}
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockMetricsClient = mockMetrics.NewMockMetrics(mockCtrl)
+		mockMachineryClient = mockMachinery.NewMockMachinery(mockCtrl)
+		mockCVClient = cvMocks.NewMockClusterVersion(mockCtrl)
+		logger = logf.Log.WithName("cluster upgrader test logger")
+		config = &upgraderConfig{
+			ControlPlaneSoak: controlPlaneSoakConfig{
+				SoakTime:              30,
+				APIErrorRateThreshold: 0.05,
+			},
+		}
+		upgrader = &clusterUpgrader{
+			client:        mockKubeClient,
+			metrics:       mockMetricsClient,
+			cvClient:      mockCVClient,
+			config:        config,
+			machinery:     mockMachineryClient,
+			upgradeConfig: upgradeConfig,
+		}
+		setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue, time.Now().Add(-2*time.Hour))
+		setCondition(upgradev1alpha1.ControlPlaneSoak, corev1.ConditionFalse, time.Now().Add(-time.Hour))
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When the control plane soak is disabled", func() {
+		BeforeEach(func() {
+			config.ControlPlaneSoak = controlPlaneSoakConfig{}
+		})
+		It("completes the step without doing anything", func() {
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+		It("does not hold the worker pools", func() {
+			Expect(upgrader.workersHeldForSoak()).To(BeFalse())
+		})
+	})
+
+	Context("When the control plane is healthy", func() {
+		It("waits for the soak to finish", func() {
+			setCondition(upgradev1alpha1.ControlPlaneSoak, corev1.ConditionFalse, time.Now())
+			expectHealthy()
+			mockMetricsClient.EXPECT().APIServerErrorRate(apiServerErrorRateWindow).Return(0.01, nil)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			Expect(upgrader.workersHeldForSoak()).To(BeTrue())
+		})
+		It("completes the step once the soak has finished", func() {
+			expectHealthy()
+			mockMetricsClient.EXPECT().APIServerErrorRate(apiServerErrorRateWindow).Return(0.01, nil)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+		It("releases the worker pools once the soak has passed", func() {
+			setCondition(upgradev1alpha1.ControlPlaneSoak, corev1.ConditionTrue, time.Now().Add(-time.Hour))
+			Expect(upgrader.workersHeldForSoak()).To(BeFalse())
+		})
+	})
+
+	Context("When the control plane has just regressed", func() {
+		It("holds the worker pools without failing the upgrade", func() {
+			expectHealthy()
+			mockMetricsClient.EXPECT().APIServerErrorRate(apiServerErrorRateWindow).Return(0.2, nil)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			Expect(upgrader.workersHeldForSoak()).To(BeTrue())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			hc := history.GetHealthCheck(upgradev1alpha1.HealthCheckAPIServerErrorRate, upgradev1alpha1.ControlPlaneSoak)
+			Expect(hc).NotTo(BeNil())
+			Expect(hc.Result).To(Equal(upgradev1alpha1.HealthCheckFailed))
+			Expect(hc.LastTransitionTime).NotTo(BeNil())
+		})
+		It("does not reset how long the regression has persisted", func() {
+			since := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+			setFailedSince(upgradev1alpha1.HealthCheckAPIServerErrorRate, since)
+			expectHealthy()
+			mockMetricsClient.EXPECT().APIServerErrorRate(apiServerErrorRateWindow).Return(0.2, nil)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			hc := history.GetHealthCheck(upgradev1alpha1.HealthCheckAPIServerErrorRate, upgradev1alpha1.ControlPlaneSoak)
+			Expect(hc.LastTransitionTime.Time).To(BeTemporally("==", since))
+		})
+	})
+
+	Context("When the control plane regression persists", func() {
+		It("pauses the worker pools and fails the upgrade", func() {
+			setFailedSince(upgradev1alpha1.HealthCheckAPIServerErrorRate, time.Now().Add(-time.Hour))
+			expectHealthy()
+			gomock.InOrder(
+				mockMetricsClient.EXPECT().APIServerErrorRate(apiServerErrorRateWindow).Return(0.2, nil),
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{
+					Pools: []machinery.PoolUpgradingResult{{Name: "worker"}, {Name: "infra"}},
+				}, nil),
+				mockMachineryClient.EXPECT().PauseMachineConfigPool(gomock.Any(), "worker"),
+				mockMachineryClient.EXPECT().PauseMachineConfigPool(gomock.Any(), "infra"),
+			)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(result).To(BeFalse())
+			var failed *upgradesteps.UpgradeFailedError
+			Expect(errors.As(err, &failed)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("API server error rate of 20.00% exceeds 5.00%"))
+		})
+		It("fails the upgrade when a ClusterOperator is degraded", func() {
+			setFailedSince(upgradev1alpha1.HealthCheckClusterOperators, time.Now().Add(-time.Hour))
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(&metrics.AlertResponse{}, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.MetricsQueryFailed, gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.CriticalAlertsFiring, gomock.Any(), gomock.Any()),
+				mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{"authentication"}}, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, metrics.ClusterOperatorsDegraded, gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().APIServerErrorRate(apiServerErrorRateWindow).Return(0.0, nil),
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{}, nil),
+			)
+			_, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			var failed *upgradesteps.UpgradeFailedError
+			Expect(errors.As(err, &failed)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("degraded operators: authentication"))
+		})
+	})
+
+	Context("When the control plane cannot be queried", func() {
+		It("retries the step rather than failing the upgrade", func() {
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(nil, fmt.Errorf("prometheus unavailable")),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, metrics.MetricsQueryFailed, gomock.Any(), gomock.Any()),
+			)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(result).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+			var failed *upgradesteps.UpgradeFailedError
+			Expect(errors.As(err, &failed)).To(BeFalse())
+		})
+		It("retries the step when the ClusterOperators cannot be fetched", func() {
+			setFailedSince(upgradev1alpha1.HealthCheckClusterOperators, time.Now().Add(-time.Hour))
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(&metrics.AlertResponse{}, nil),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.MetricsQueryFailed, gomock.Any(), gomock.Any()),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.CriticalAlertsFiring, gomock.Any(), gomock.Any()),
+				mockCVClient.EXPECT().HasDegradedOperators().Return(nil, fmt.Errorf("api unavailable")),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, metrics.ClusterOperatorsStatusFailed, gomock.Any(), gomock.Any()),
+			)
+			result, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(result).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+			var failed *upgradesteps.UpgradeFailedError
+			Expect(errors.As(err, &failed)).To(BeFalse())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			Expect(history.GetHealthCheck(upgradev1alpha1.HealthCheckClusterOperators, upgradev1alpha1.ControlPlaneSoak)).To(BeNil())
+		})
+		It("does not count the time the alerts could not be queried towards a regression", func() {
+			gomock.InOrder(
+				mockCVClient.EXPECT().GetClusterVersion().Return(clusterVersion, nil),
+				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(nil, fmt.Errorf("prometheus unavailable")),
+				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, metrics.MetricsQueryFailed, gomock.Any(), gomock.Any()),
+			)
+			_, err := upgrader.ControlPlaneSoak(context.TODO(), logger)
+			Expect(err).To(HaveOccurred())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			Expect(history.GetHealthCheck(upgradev1alpha1.HealthCheckCriticalAlerts, upgradev1alpha1.ControlPlaneSoak)).To(BeNil())
+			Expect(upgrader.regressedFor()).To(BeZero())
+		})
+	})
+})
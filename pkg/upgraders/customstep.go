@@ -0,0 +1,226 @@
+package upgraders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/managed-upgrade-operator/config"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
+)
+
+const (
+	// customStepLabel labels the Jobs run by custom steps with the name of the step
+	customStepLabel = "upgrade.managed.openshift.io/custom-step"
+	// customStepVersionLabel labels the Jobs run by custom steps with the version being upgraded to
+	customStepVersionLabel = "upgrade.managed.openshift.io/version"
+)
+
+// customStepPayload is the body sent to the endpoint of an HTTP custom step
+// for methods other than GET
+type customStepPayload struct {
+	Step          string `json:"step"`
+	UpgradeConfig string `json:"upgradeConfig"`
+	Version       string `json:"version"`
+}
+
+// withCustomSteps inserts the configured custom steps into the step list before
+// or after the built-in steps they reference
+func withCustomSteps(steps []upgradesteps.UpgradeStep, cfg *upgraderConfig, c *clusterUpgrader) ([]upgradesteps.UpgradeStep, error) {
+	for _, cs := range cfg.CustomSteps {
+		step := upgradesteps.Action(cs.Name, c.customStepAction(cs))
+
+		index := -1
+		for i, s := range steps {
+			if s.String() == cs.Name {
+				return nil, fmt.Errorf("custom step %s has the same name as an existing upgrade step", cs.Name)
+			}
+			// Insert before the first or after the last step of the given name, as
+			// some conditions are shared by more than one step
+			if cs.Before != "" && s.String() == cs.Before && index < 0 {
+				index = i
+			}
+			if cs.After != "" && s.String() == cs.After {
+				index = i + 1
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("custom step %s references unknown upgrade step %s%s", cs.Name, cs.Before, cs.After)
+		}
+
+		steps = append(steps[:index], append([]upgradesteps.UpgradeStep{step}, steps[index:]...)...)
+	}
+	return steps, nil
+}
+
+// customStepAction returns the action run for a custom step
+func (c *clusterUpgrader) customStepAction(cs customStep) func(context.Context, logr.Logger) (bool, error) {
+	return func(ctx context.Context, logger logr.Logger) (bool, error) {
+		if cs.HTTP != nil {
+			return c.runHTTPCustomStep(ctx, logger, cs.Name, cs.HTTP)
+		}
+		return c.runJobCustomStep(ctx, logger, cs.Name, cs.Job)
+	}
+}
+
+// runHTTPCustomStep calls the custom step's endpoint. A 200 or 204 response
+// completes the step, a 202 response indicates the step is still in progress
+// and any other response is treated as an error.
+func (c *clusterUpgrader) runHTTPCustomStep(ctx context.Context, logger logr.Logger, name string, cfg *customStepHTTP) (bool, error) {
+	method := cfg.GetMethod()
+	var body io.Reader
+	if method != http.MethodGet {
+		payload, err := json.Marshal(customStepPayload{
+			Step:          name,
+			UpgradeConfig: c.upgradeConfig.Name,
+			Version:       c.upgradeConfig.Spec.Desired.Version,
+		})
+		if err != nil {
+			return false, err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, cfg.URL, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to build request for custom step %s: %v", name, err)
+	}
+	req.Header.Set("User-Agent", config.SetUserAgent())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if cfg.HeadersSecretName != "" {
+		secret := &corev1.Secret{}
+		err := c.client.Get(ctx, types.NamespacedName{Namespace: c.upgradeConfig.Namespace, Name: cfg.HeadersSecretName}, secret)
+		if err != nil {
+			return false, fmt.Errorf("can't read headers secret %s of custom step %s: %v", cfg.HeadersSecretName, name, err)
+		}
+		for k, v := range secret.Data {
+			req.Header.Set(k, string(v))
+		}
+	}
+
+	client := http.Client{Timeout: cfg.GetTimeoutDuration()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("custom step %s request failed: %v", name, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		logger.Info(fmt.Sprintf("Custom step %s completed", name))
+		return true, nil
+	case http.StatusAccepted:
+		logger.Info(fmt.Sprintf("Custom step %s is still in progress", name))
+		return false, nil
+	default:
+		return false, fmt.Errorf("custom step %s returned unexpected status %d", name, resp.StatusCode)
+	}
+}
+
+// runJobCustomStep runs the custom step's Job for the version being upgraded
+// to, creating it if it doesn't yet exist. A succeeded Job completes the step.
+// A failed Job is kept and replaced by a new Job until the configured number of
+// attempts have failed, when the step fails the upgrade.
+func (c *clusterUpgrader) runJobCustomStep(ctx context.Context, logger logr.Logger, name string, cfg *customStepJob) (bool, error) {
+	version := c.upgradeConfig.Spec.Desired.Version
+	jobs := &batchv1.JobList{}
+	err := c.client.List(ctx, jobs, client.InNamespace(c.upgradeConfig.Namespace), client.MatchingLabels{
+		customStepLabel:        name,
+		customStepVersionLabel: version,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list jobs for custom step %s: %v", name, err)
+	}
+
+	failed := 0
+	failure := ""
+	for _, job := range jobs.Items {
+		if job.Status.Succeeded > 0 {
+			logger.Info(fmt.Sprintf("Custom step %s job %s succeeded", name, job.Name))
+			return true, nil
+		}
+		if message, ok := jobFailure(&job); ok {
+			failed++
+			failure = fmt.Sprintf("job %s failed: %s", job.Name, message)
+			continue
+		}
+		logger.Info(fmt.Sprintf("Custom step %s job %s is still running", name, job.Name))
+		return false, nil
+	}
+
+	if failed >= cfg.GetAttempts() {
+		return false, upgradesteps.FailUpgrade(fmt.Errorf("custom step %s failed after %d attempts, last %s", name, failed, failure))
+	}
+	if failed > 0 {
+		logger.Info(fmt.Sprintf("Custom step %s %s, retrying after %d of %d attempts", name, failure, failed, cfg.GetAttempts()))
+	}
+
+	job := newCustomStepJob(name, version, c.upgradeConfig.Name, c.upgradeConfig.Namespace, cfg)
+	logger.Info(fmt.Sprintf("Creating job for custom step %s", name))
+	if err := c.client.Create(ctx, job); err != nil {
+		return false, fmt.Errorf("failed to create job for custom step %s: %v", name, err)
+	}
+	return false, nil
+}
+
+// jobFailure returns the message of the Job's failed condition, if it has failed
+func jobFailure(job *batchv1.Job) (string, bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return cond.Message, true
+		}
+	}
+	return "", false
+}
+
+// newCustomStepJob returns the Job run for a custom step
+func newCustomStepJob(name, version, ucName, namespace string, cfg *customStepJob) *batchv1.Job {
+	backoffLimit := cfg.BackoffLimit
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "muo-custom-step-",
+			Namespace:    namespace,
+			Labels: map[string]string{
+				customStepLabel:        name,
+				customStepVersionLabel: version,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: cfg.ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:    "step",
+							Image:   cfg.Image,
+							Command: cfg.Command,
+							Args:    cfg.Args,
+							Env: []corev1.EnvVar{
+								{Name: "UPGRADE_STEP", Value: name},
+								{Name: "UPGRADE_CONFIG", Value: ucName},
+								{Name: "UPGRADE_VERSION", Value: version},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,284 @@
+package upgraders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("CustomStep", func() {
+	var (
+		logger        logr.Logger
+		upgradeConfig *upgradev1alpha1.UpgradeConfig
+		testUpgrader  *clusterUpgrader
+	)
+
+	BeforeEach(func() {
+		logger = logf.Log.WithName("custom step test logger")
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(types.NamespacedName{
+			Name:      "test-upgradeconfig",
+			Namespace: "test-namespace",
+		}).GetUpgradeConfig()
+		testUpgrader = &clusterUpgrader{
+			client:        fake.NewClientBuilder().Build(),
+			config:        &upgraderConfig{},
+			upgradeConfig: upgradeConfig,
+		}
+	})
+
+	Context("When inserting custom steps", func() {
+		var steps []upgradesteps.UpgradeStep
+
+		BeforeEach(func() {
+			noop := func(ctx context.Context, logger logr.Logger) (bool, error) { return true, nil }
+			steps = []upgradesteps.UpgradeStep{
+				upgradesteps.Action(string(upgradev1alpha1.SendStartedNotification), noop),
+				upgradesteps.Action(string(upgradev1alpha1.SendStartedNotification), noop),
+				upgradesteps.Action(string(upgradev1alpha1.CommenceUpgrade), noop),
+				upgradesteps.Action(string(upgradev1alpha1.PostClusterHealthCheck), noop),
+			}
+		})
+
+		It("inserts steps before and after the referenced steps", func() {
+			testUpgrader.config.CustomSteps = []customStep{
+				{Name: "ChangeApproved", Before: string(upgradev1alpha1.CommenceUpgrade), HTTP: &customStepHTTP{URL: "http://example.com"}},
+				{Name: "SmokeTested", After: string(upgradev1alpha1.PostClusterHealthCheck), Job: &customStepJob{Image: "smoke"}},
+				{Name: "Announced", After: string(upgradev1alpha1.SendStartedNotification), HTTP: &customStepHTTP{URL: "http://example.com"}},
+			}
+			result, err := withCustomSteps(steps, testUpgrader.config, testUpgrader)
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, s := range result {
+				names = append(names, s.String())
+			}
+			Expect(names).To(Equal([]string{
+				string(upgradev1alpha1.SendStartedNotification),
+				string(upgradev1alpha1.SendStartedNotification),
+				"Announced",
+				"ChangeApproved",
+				string(upgradev1alpha1.CommenceUpgrade),
+				string(upgradev1alpha1.PostClusterHealthCheck),
+				"SmokeTested",
+			}))
+		})
+
+		It("returns an error if the referenced step does not exist", func() {
+			testUpgrader.config.CustomSteps = []customStep{
+				{Name: "ChangeApproved", Before: "DoesNotExist", HTTP: &customStepHTTP{URL: "http://example.com"}},
+			}
+			_, err := withCustomSteps(steps, testUpgrader.config, testUpgrader)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("returns an error if the custom step clashes with an existing step", func() {
+			testUpgrader.config.CustomSteps = []customStep{
+				{Name: string(upgradev1alpha1.CommenceUpgrade), Before: string(upgradev1alpha1.PostClusterHealthCheck), HTTP: &customStepHTTP{URL: "http://example.com"}},
+			}
+			_, err := withCustomSteps(steps, testUpgrader.config, testUpgrader)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When validating custom step config", func() {
+		It("accepts a valid http step", func() {
+			cs := customStep{Name: "ChangeApproved", Before: "UpgradeCommenced", HTTP: &customStepHTTP{URL: "https://cmdb.example.com/approved"}}
+			Expect(cs.IsValid()).NotTo(HaveOccurred())
+		})
+		It("requires exactly one of before or after", func() {
+			cs := customStep{Name: "ChangeApproved", Before: "UpgradeCommenced", After: "UpgradeCommenced", HTTP: &customStepHTTP{URL: "https://cmdb.example.com"}}
+			Expect(cs.IsValid()).To(HaveOccurred())
+		})
+		It("requires exactly one of http or job", func() {
+			cs := customStep{Name: "ChangeApproved", Before: "UpgradeCommenced"}
+			Expect(cs.IsValid()).To(HaveOccurred())
+		})
+		It("requires a non-negative number of job attempts", func() {
+			cs := customStep{Name: "SmokeTested", After: "ClusterHealthyAfterUpgrade", Job: &customStepJob{Image: "smoke", Attempts: -1}}
+			Expect(cs.IsValid()).To(HaveOccurred())
+		})
+		It("requires a job image", func() {
+			cs := customStep{Name: "SmokeTested", After: "ClusterHealthyAfterUpgrade", Job: &customStepJob{}}
+			Expect(cs.IsValid()).To(HaveOccurred())
+		})
+		It("requires a name usable as a label value", func() {
+			cs := customStep{Name: "not a valid name", Before: "UpgradeCommenced", HTTP: &customStepHTTP{URL: "https://cmdb.example.com"}}
+			Expect(cs.IsValid()).To(HaveOccurred())
+		})
+	})
+
+	Context("When running an HTTP custom step", func() {
+		var (
+			status   int
+			received *http.Request
+			payload  customStepPayload
+			server   *httptest.Server
+		)
+
+		BeforeEach(func() {
+			payload = customStepPayload{}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				if r.Body != nil {
+					_ = json.NewDecoder(r.Body).Decode(&payload)
+				}
+				w.WriteHeader(status)
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("completes the step on a 200 response", func() {
+			status = http.StatusOK
+			cfg := &customStepHTTP{URL: server.URL, Headers: map[string]string{"X-Ticket": "CHG123"}}
+			result, err := testUpgrader.runHTTPCustomStep(context.TODO(), logger, "ChangeApproved", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+			Expect(received.Method).To(Equal(http.MethodGet))
+			Expect(received.Header.Get("X-Ticket")).To(Equal("CHG123"))
+		})
+
+		It("sends the headers held in the headers secret", func() {
+			status = http.StatusOK
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "custom-step-headers", Namespace: upgradeConfig.Namespace},
+				Data:       map[string][]byte{"Authorization": []byte("Bearer token")},
+			}
+			Expect(testUpgrader.client.Create(context.TODO(), secret)).To(Succeed())
+			cfg := &customStepHTTP{URL: server.URL, HeadersSecretName: secret.Name}
+			result, err := testUpgrader.runHTTPCustomStep(context.TODO(), logger, "ChangeApproved", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+			Expect(received.Header.Get("Authorization")).To(Equal("Bearer token"))
+		})
+
+		It("returns an error if the headers secret can't be read", func() {
+			cfg := &customStepHTTP{URL: server.URL, HeadersSecretName: "missing"}
+			result, err := testUpgrader.runHTTPCustomStep(context.TODO(), logger, "ChangeApproved", cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+
+		It("sends the upgrade details when posting", func() {
+			status = http.StatusNoContent
+			cfg := &customStepHTTP{URL: server.URL, Method: "post"}
+			result, err := testUpgrader.runHTTPCustomStep(context.TODO(), logger, "ChangeApproved", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+			Expect(received.Method).To(Equal(http.MethodPost))
+			Expect(payload.Step).To(Equal("ChangeApproved"))
+			Expect(payload.Version).To(Equal(upgradeConfig.Spec.Desired.Version))
+		})
+
+		It("keeps the step in progress on a 202 response", func() {
+			status = http.StatusAccepted
+			result, err := testUpgrader.runHTTPCustomStep(context.TODO(), logger, "ChangeApproved", &customStepHTTP{URL: server.URL})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+
+		It("returns an error on any other response", func() {
+			status = http.StatusForbidden
+			result, err := testUpgrader.runHTTPCustomStep(context.TODO(), logger, "ChangeApproved", &customStepHTTP{URL: server.URL})
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+	})
+
+	Context("When running a Job custom step", func() {
+		var cfg *customStepJob
+
+		BeforeEach(func() {
+			cfg = &customStepJob{Image: "quay.io/example/smoke:latest", Command: []string{"/smoke"}}
+		})
+
+		getJob := func() *batchv1.Job {
+			jobs := &batchv1.JobList{}
+			Expect(testUpgrader.client.List(context.TODO(), jobs, client.InNamespace(upgradeConfig.Namespace))).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			return &jobs.Items[0]
+		}
+
+		It("creates the job and waits for it", func() {
+			result, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			job := getJob()
+			Expect(job.Labels[customStepLabel]).To(Equal("SmokeTested"))
+			Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal(cfg.Image))
+
+			result, err = testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			getJob()
+		})
+
+		It("completes the step once the job succeeds", func() {
+			_, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			job := getJob()
+			job.Status.Succeeded = 1
+			Expect(testUpgrader.client.Status().Update(context.TODO(), job)).To(Succeed())
+
+			result, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeTrue())
+		})
+
+		failJobs := func() {
+			jobs := &batchv1.JobList{}
+			Expect(testUpgrader.client.List(context.TODO(), jobs, client.InNamespace(upgradeConfig.Namespace))).To(Succeed())
+			for i := range jobs.Items {
+				jobs.Items[i].Status.Conditions = []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+				}
+				Expect(testUpgrader.client.Status().Update(context.TODO(), &jobs.Items[i])).To(Succeed())
+			}
+		}
+
+		It("runs a new job once the job fails", func() {
+			_, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			failJobs()
+
+			result, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			jobs := &batchv1.JobList{}
+			Expect(testUpgrader.client.List(context.TODO(), jobs, client.InNamespace(upgradeConfig.Namespace))).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(2))
+		})
+
+		It("fails the upgrade once every attempt has failed", func() {
+			cfg.Attempts = 2
+			for i := 0; i < cfg.Attempts; i++ {
+				_, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+				Expect(err).NotTo(HaveOccurred())
+				failJobs()
+			}
+
+			result, err := testUpgrader.runJobCustomStep(context.TODO(), logger, "SmokeTested", cfg)
+			var failed *upgradesteps.UpgradeFailedError
+			Expect(errors.As(err, &failed)).To(BeTrue())
+			Expect(result).To(BeFalse())
+		})
+	})
+})
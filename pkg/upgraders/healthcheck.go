@@ -0,0 +1,226 @@
+package upgraders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+// HealthCheck is a check of the health of the cluster, run by the upgrade's health check steps
+type HealthCheck interface {
+	// Name returns the name by which the health check is configured and reported
+	Name() string
+	// Check returns an error if the cluster fails the health check, along with
+	// a summary of the failure to be notified
+	Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error)
+}
+
+// healthCheckPhase is a point in the upgrade at which health checks are run
+type healthCheckPhase string
+
+const (
+	// healthCheckPhasePreSchedule runs when the upgrade is scheduled, to give a
+	// heads up on anything which may delay it
+	healthCheckPhasePreSchedule healthCheckPhase = "PreSchedule"
+	// healthCheckPhasePreUpgrade runs before the upgrade commences
+	healthCheckPhasePreUpgrade healthCheckPhase = "PreUpgrade"
+	// healthCheckPhasePostUpgrade runs once the upgrade has completed
+	healthCheckPhasePostUpgrade healthCheckPhase = "PostUpgrade"
+)
+
+// healthCheckSeverity is how a failing health check affects the upgrade
+type healthCheckSeverity string
+
+const (
+	// healthCheckSeverityBlock holds the upgrade step until the health check passes
+	healthCheckSeverityBlock healthCheckSeverity = "Block"
+	// healthCheckSeverityWarn only notifies the failure
+	healthCheckSeverityWarn healthCheckSeverity = "Warn"
+)
+
+// healthCheckRegistration registers a health check along with how it runs
+// when not configured otherwise
+type healthCheckRegistration struct {
+	name     string
+	phases   []healthCheckPhase
+	severity healthCheckSeverity
+	new      func(c *clusterUpgrader) HealthCheck
+}
+
+// healthCheckRegistry holds the health checks which can be configured, in the
+// order in which they run
+var healthCheckRegistry = []healthCheckRegistration{
+	{
+		name:     upgradev1alpha1.HealthCheckCriticalAlerts,
+		phases:   []healthCheckPhase{healthCheckPhasePreSchedule, healthCheckPhasePreUpgrade, healthCheckPhasePostUpgrade},
+		severity: healthCheckSeverityBlock,
+		new:      func(c *clusterUpgrader) HealthCheck { return &criticalAlertsHealthCheck{c} },
+	},
+	{
+		name:     upgradev1alpha1.HealthCheckClusterOperators,
+		phases:   []healthCheckPhase{healthCheckPhasePreSchedule, healthCheckPhasePreUpgrade, healthCheckPhasePostUpgrade},
+		severity: healthCheckSeverityBlock,
+		new:      func(c *clusterUpgrader) HealthCheck { return &clusterOperatorsHealthCheck{c} },
+	},
+	{
+		name:     upgradev1alpha1.HealthCheckCapacityReservation,
+		phases:   []healthCheckPhase{healthCheckPhasePreSchedule, healthCheckPhasePreUpgrade},
+		severity: healthCheckSeverityWarn,
+		new:      func(c *clusterUpgrader) HealthCheck { return &capacityReservationHealthCheck{c} },
+	},
+	{
+		name:     upgradev1alpha1.HealthCheckManuallyCordonedNodes,
+		phases:   []healthCheckPhase{healthCheckPhasePreSchedule, healthCheckPhasePreUpgrade},
+		severity: healthCheckSeverityWarn,
+		new:      func(c *clusterUpgrader) HealthCheck { return &manuallyCordonedNodesHealthCheck{c} },
+	},
+	{
+		name:     upgradev1alpha1.HealthCheckNodeUnschedulableTaints,
+		phases:   []healthCheckPhase{healthCheckPhasePreSchedule, healthCheckPhasePreUpgrade},
+		severity: healthCheckSeverityWarn,
+		new:      func(c *clusterUpgrader) HealthCheck { return &nodeUnschedulableTaintsHealthCheck{c} },
+	},
+	{
+		name:     upgradev1alpha1.HealthCheckPodDisruptionBudgets,
+		phases:   []healthCheckPhase{healthCheckPhasePreSchedule, healthCheckPhasePreUpgrade},
+		severity: healthCheckSeverityWarn,
+		new:      func(c *clusterUpgrader) HealthCheck { return &podDisruptionBudgetsHealthCheck{c} },
+	},
+}
+
+// legacyHealthChecks are the only health checks run before the upgrade commences while the
+// PreHealthCheck feature gate is disabled
+var legacyHealthChecks = []string{
+	upgradev1alpha1.HealthCheckCriticalAlerts,
+	upgradev1alpha1.HealthCheckClusterOperators,
+}
+
+// getHealthCheckRegistration returns the registration of the named health check, or nil if there is none
+func getHealthCheckRegistration(name string) *healthCheckRegistration {
+	for i := range healthCheckRegistry {
+		if healthCheckRegistry[i].name == name {
+			return &healthCheckRegistry[i]
+		}
+	}
+	return nil
+}
+
+// healthCheckFailure is the failure of a health check
+type healthCheckFailure struct {
+	// result summarises the failure to be notified
+	result string
+	err    error
+	// blocking is whether the failure holds the upgrade step
+	blocking bool
+}
+
+// configuredHealthCheck is a health check configured to run, along with its severity
+type configuredHealthCheck struct {
+	HealthCheck
+	severity healthCheckSeverity
+}
+
+// healthChecksFor returns the health checks configured to run in the given phase,
+// in the order in which they run
+func (c *clusterUpgrader) healthChecksFor(phase healthCheckPhase) []configuredHealthCheck {
+	checks := []configuredHealthCheck{}
+	for _, registration := range healthCheckRegistry {
+		cfg := c.config.HealthCheck.getCheckConfig(registration)
+		if !cfg.runsIn(phase) {
+			continue
+		}
+		checks = append(checks, configuredHealthCheck{
+			HealthCheck: registration.new(c),
+			severity:    cfg.Severity,
+		})
+	}
+	return checks
+}
+
+// enabledHealthChecks returns the named health checks which are not disabled, in the
+// given order
+func (c *clusterUpgrader) enabledHealthChecks(names []string) []configuredHealthCheck {
+	checks := []configuredHealthCheck{}
+	for _, name := range names {
+		registration := getHealthCheckRegistration(name)
+		if registration == nil {
+			continue
+		}
+		cfg := c.config.HealthCheck.getCheckConfig(*registration)
+		if cfg.Disabled {
+			continue
+		}
+		checks = append(checks, configuredHealthCheck{
+			HealthCheck: registration.new(c),
+			severity:    cfg.Severity,
+		})
+	}
+	return checks
+}
+
+// withNames returns the health checks among the given ones which have one of the names
+func withNames(checks []configuredHealthCheck, names []string) []configuredHealthCheck {
+	result := []configuredHealthCheck{}
+	for _, hc := range checks {
+		for _, name := range names {
+			if hc.Name() == name {
+				result = append(result, hc)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// runHealthChecks runs the given health checks for the given upgrade step, and
+// returns those which failed
+func (c *clusterUpgrader) runHealthChecks(ctx context.Context, checks []configuredHealthCheck, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) []healthCheckFailure {
+	failures := []healthCheckFailure{}
+	for _, hc := range checks {
+		result, err := hc.Check(ctx, step, logger, version)
+		if err == nil {
+			continue
+		}
+		logger.Info(fmt.Sprintf("Health check %s failed: %s", hc.Name(), err))
+		failures = append(failures, healthCheckFailure{
+			result:   result,
+			err:      err,
+			blocking: hc.severity == healthCheckSeverityBlock,
+		})
+	}
+	return failures
+}
+
+// healthCheckError returns the error of a failed health check, for health
+// checks which may fail without one
+func healthCheckError(name string, err error) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("health check %s failed", name)
+}
+
+// healthCheckQueryError is returned by a health check which could not query the
+// cluster, rather than finding it to be unhealthy
+type healthCheckQueryError struct {
+	err error
+}
+
+func (e *healthCheckQueryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *healthCheckQueryError) Unwrap() error {
+	return e.err
+}
+
+// isHealthCheckQueryError returns whether the health check failed because the
+// cluster could not be queried
+func isHealthCheckQueryError(err error) bool {
+	var queryErr *healthCheckQueryError
+	return errors.As(err, &queryErr)
+}
@@ -1,6 +1,7 @@
 package upgraders
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -12,7 +13,7 @@ import (
 
 // CriticalAlerts function will check the list of alerts and namespaces to be ignored for healthcheck
 // and filter the critical open firing alerts via the ALERTS metric.
-func CriticalAlerts(metricsClient metrics.Metrics, cfg *upgraderConfig, ug *upgradev1alpha1.UpgradeConfig, logger logr.Logger, version string) (bool, error) {
+func CriticalAlerts(metricsClient metrics.Metrics, cfg *upgraderConfig, ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (bool, error) {
 	ic := cfg.HealthCheck.IgnoredCriticals
 	icQuery := ""
 	if len(ic) > 0 {
@@ -34,7 +35,9 @@ func CriticalAlerts(metricsClient metrics.Metrics, cfg *upgraderConfig, ug *upgr
 	if err != nil {
 		logger.Info("Unable to query metrics to check for open alerts")
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.MetricsQueryFailed, version, state)
-		return false, fmt.Errorf("unable to query critical alerts: %s", err)
+		err = fmt.Errorf("unable to query critical alerts: %s", err)
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckCriticalAlerts, metrics.MetricsQueryFailed, err, nil)
+		return false, &healthCheckQueryError{err}
 	}
 
 	alertCount := len(alerts.Data.Result)
@@ -42,6 +45,7 @@ func CriticalAlerts(metricsClient metrics.Metrics, cfg *upgraderConfig, ug *upgr
 	if alertCount > 0 {
 		alert := []string{}
 		uniqueAlerts := make(map[string]bool)
+		objects := []upgradev1alpha1.HealthCheckObject{}
 
 		for _, r := range alerts.Data.Result {
 			a := r.Metric["alertname"]
@@ -51,16 +55,39 @@ func CriticalAlerts(metricsClient metrics.Metrics, cfg *upgraderConfig, ug *upgr
 			}
 			alert = append(alert, a)
 			uniqueAlerts[a] = true
+			objects = append(objects, upgradev1alpha1.HealthCheckObject{Kind: "Alert", Namespace: r.Metric["namespace"], Name: a})
 		}
 
 		logger.Info(fmt.Sprintf("Critical alert(s) firing: %s. Cannot continue upgrade", strings.Join(alert, ", ")))
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.CriticalAlertsFiring, version, state)
 
-		return false, fmt.Errorf("critical alert(s) firing: %s", strings.Join(alert, ", "))
+		err = fmt.Errorf("critical alert(s) firing: %s", strings.Join(alert, ", "))
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckCriticalAlerts, metrics.CriticalAlertsFiring, err, objects)
+		return false, err
 	}
 
 	logger.Info("Prehealth check for critical alerts passed")
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.MetricsQueryFailed, version, state)
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.CriticalAlertsFiring, version, state)
+	recordHealthCheckPassed(ug, step, upgradev1alpha1.HealthCheckCriticalAlerts)
 	return true, nil
 }
+
+// criticalAlertsHealthCheck is the HealthCheck for critical alerts firing
+type criticalAlertsHealthCheck struct {
+	c *clusterUpgrader
+}
+
+// Name returns the name of the health check
+func (h *criticalAlertsHealthCheck) Name() string {
+	return upgradev1alpha1.HealthCheckCriticalAlerts
+}
+
+// Check checks whether there are critical alerts firing
+func (h *criticalAlertsHealthCheck) Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error) {
+	ok, err := CriticalAlerts(h.c.metrics, h.c.config, h.c.upgradeConfig, step, logger, version)
+	if err != nil || !ok {
+		return "CriticalAlertsHealthcheckFailed", healthCheckError(h.Name(), err)
+	}
+	return "", nil
+}
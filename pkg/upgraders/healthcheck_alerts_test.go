@@ -100,9 +100,13 @@ var _ = Describe("HealthCheck Alerts", func() {
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.MetricsQueryFailed, gomock.Any(), gomock.Any()),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.CriticalAlertsFiring, gomock.Any(), gomock.Any()),
 			)
-			result, err := CriticalAlerts(mockMetricsClient, upgrader.config, upgradeConfig, logger, version)
+			result, err := CriticalAlerts(mockMetricsClient, upgrader.config, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(result).Should(BeTrue())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			healthCheck := history.GetHealthCheck(upgradev1alpha1.HealthCheckCriticalAlerts, upgradev1alpha1.UpgradePreHealthCheck)
+			Expect(healthCheck).NotTo(BeNil())
+			Expect(healthCheck.Result).To(Equal(upgradev1alpha1.HealthCheckPassed))
 		})
 	})
 
@@ -112,8 +116,8 @@ var _ = Describe("HealthCheck Alerts", func() {
 			alertsResponse = &metrics.AlertResponse{
 				Data: metrics.AlertData{
 					Result: []metrics.AlertResult{
-						{Metric: make(map[string]string), Value: nil},
-						{Metric: make(map[string]string), Value: nil},
+						{Metric: map[string]string{"alertname": "KubePodCrashLooping", "namespace": "ns2"}, Value: nil},
+						{Metric: map[string]string{"alertname": "KubePodCrashLooping", "namespace": "ns2"}, Value: nil},
 					},
 				},
 			}
@@ -123,9 +127,17 @@ var _ = Describe("HealthCheck Alerts", func() {
 				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(alertsResponse, nil),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any()),
 			)
-			result, err := CriticalAlerts(mockMetricsClient, upgrader.config, upgradeConfig, logger, version)
+			result, err := CriticalAlerts(mockMetricsClient, upgrader.config, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(BeFalse())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			healthCheck := history.GetHealthCheck(upgradev1alpha1.HealthCheckCriticalAlerts, upgradev1alpha1.UpgradePreHealthCheck)
+			Expect(healthCheck).NotTo(BeNil())
+			Expect(healthCheck.Result).To(Equal(upgradev1alpha1.HealthCheckFailed))
+			Expect(healthCheck.Reason).To(Equal(metrics.CriticalAlertsFiring))
+			Expect(healthCheck.AffectedObjects).To(Equal([]upgradev1alpha1.HealthCheckObject{
+				{Kind: "Alert", Namespace: "ns2", Name: "KubePodCrashLooping"},
+			}))
 		})
 	})
 
@@ -140,7 +152,7 @@ var _ = Describe("HealthCheck Alerts", func() {
 				mockMetricsClient.EXPECT().Query(gomock.Any()).Return(alertsResponse, fakeError),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any()),
 			)
-			result, err := CriticalAlerts(mockMetricsClient, upgrader.config, upgradeConfig, logger, version)
+			result, err := CriticalAlerts(mockMetricsClient, upgrader.config, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(BeFalse())
 		})
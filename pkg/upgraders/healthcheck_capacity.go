@@ -0,0 +1,43 @@
+package upgraders
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+)
+
+// capacityReservationHealthCheck is the HealthCheck for whether the extra compute
+// capacity requested for the upgrade can be reserved
+type capacityReservationHealthCheck struct {
+	c *clusterUpgrader
+}
+
+// Name returns the name of the health check
+func (h *capacityReservationHealthCheck) Name() string {
+	return upgradev1alpha1.HealthCheckCapacityReservation
+}
+
+// Check checks whether the compute capacity can be reserved, if it is requested
+func (h *capacityReservationHealthCheck) Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error) {
+	if !h.c.upgradeConfig.Spec.CapacityReservation {
+		return "", nil
+	}
+
+	history := h.c.upgradeConfig.Status.History.GetHistory(h.c.upgradeConfig.Spec.Desired.Version)
+	state := string(history.Phase)
+
+	ok, err := h.c.scaler.CanScale(h.c.client, logger)
+	if !ok || err != nil {
+		h.c.metrics.UpdateMetricHealthcheckFailed(h.c.upgradeConfig.Name, metrics.DefaultWorkerMachinepoolNotFound, version, state)
+		err = healthCheckError(h.Name(), err)
+		recordHealthCheckFailed(h.c.upgradeConfig, step, h.Name(), metrics.DefaultWorkerMachinepoolNotFound, err, nil)
+		return "CapacityReservationHealthcheckFailed", err
+	}
+	logger.Info("Prehealth check for CapacityReservation passed")
+	h.c.metrics.UpdateMetricHealthcheckSucceeded(h.c.upgradeConfig.Name, metrics.DefaultWorkerMachinepoolNotFound, version, state)
+	recordHealthCheckPassed(h.c.upgradeConfig, step, h.Name())
+	return "", nil
+}
@@ -1,6 +1,7 @@
 package upgraders
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -13,7 +14,7 @@ import (
 
 // ClusterOperators function will check the degraded ClusterOperators and if there are any found then
 // error is reported.
-func ClusterOperators(metricsClient metrics.Metrics, cvClient cv.ClusterVersion, ug *upgradev1alpha1.UpgradeConfig, logger logr.Logger, version string) (bool, error) {
+func ClusterOperators(metricsClient metrics.Metrics, cvClient cv.ClusterVersion, ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (bool, error) {
 	// Get current upgrade state
 	history := ug.Status.History.GetHistory(ug.Spec.Desired.Version)
 	state := string(history.Phase)
@@ -22,15 +23,38 @@ func ClusterOperators(metricsClient metrics.Metrics, cvClient cv.ClusterVersion,
 	if err != nil {
 		logger.Info("Unable to fetch status of clusteroperators")
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.ClusterOperatorsStatusFailed, version, state)
-		return false, err
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckClusterOperators, metrics.ClusterOperatorsStatusFailed, err, nil)
+		return false, &healthCheckQueryError{err}
 	}
 	if len(result.Degraded) > 0 {
 		logger.Info(fmt.Sprintf("Degraded operators: %s", strings.Join(result.Degraded, ", ")))
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.ClusterOperatorsDegraded, version, state)
-		return false, fmt.Errorf("degraded operators: %s", strings.Join(result.Degraded, ", "))
+		err = fmt.Errorf("degraded operators: %s", strings.Join(result.Degraded, ", "))
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckClusterOperators, metrics.ClusterOperatorsDegraded, err, healthCheckObjects("ClusterOperator", result.Degraded))
+		return false, err
 	}
 	logger.Info("Prehealth check for clusteroperators passed")
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterOperatorsStatusFailed, version, state)
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterOperatorsDegraded, version, state)
+	recordHealthCheckPassed(ug, step, upgradev1alpha1.HealthCheckClusterOperators)
 	return true, nil
 }
+
+// clusterOperatorsHealthCheck is the HealthCheck for degraded ClusterOperators
+type clusterOperatorsHealthCheck struct {
+	c *clusterUpgrader
+}
+
+// Name returns the name of the health check
+func (h *clusterOperatorsHealthCheck) Name() string {
+	return upgradev1alpha1.HealthCheckClusterOperators
+}
+
+// Check checks whether there are degraded ClusterOperators
+func (h *clusterOperatorsHealthCheck) Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error) {
+	ok, err := ClusterOperators(h.c.metrics, h.c.cvClient, h.c.upgradeConfig, step, logger, version)
+	if err != nil || !ok {
+		return "ClusterOperatorsHealthcheckFailed", healthCheckError(h.Name(), err)
+	}
+	return "", nil
+}
@@ -65,7 +65,7 @@ var _ = Describe("HealthCheck ClusterOperators", func() {
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsStatusFailed, gomock.Any(), gomock.Any()),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterOperatorsDegraded, gomock.Any(), gomock.Any()),
 			)
-			result, err := ClusterOperators(mockMetricsClient, mockCVClient, upgradeConfig, logger, version)
+			result, err := ClusterOperators(mockMetricsClient, mockCVClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(result).Should(BeTrue())
 		})
@@ -77,7 +77,7 @@ var _ = Describe("HealthCheck ClusterOperators", func() {
 				mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{"test-clusteroperator"}}, nil),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any()),
 			)
-			result, err := ClusterOperators(mockMetricsClient, mockCVClient, upgradeConfig, logger, version)
+			result, err := ClusterOperators(mockMetricsClient, mockCVClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(BeFalse())
 		})
@@ -90,7 +90,7 @@ var _ = Describe("HealthCheck ClusterOperators", func() {
 				mockCVClient.EXPECT().HasDegradedOperators().Return(&clusterversion.HasDegradedOperatorsResult{Degraded: []string{}}, fakeError),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any()),
 			)
-			result, err := ClusterOperators(mockMetricsClient, mockCVClient, upgradeConfig, logger, version)
+			result, err := ClusterOperators(mockMetricsClient, mockCVClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(BeFalse())
 		})
@@ -15,7 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func ManuallyCordonedNodes(metricsClient metrics.Metrics, machinery machinery.Machinery, c client.Client, ug *upgradev1alpha1.UpgradeConfig, logger logr.Logger, version string) ([]string, error) {
+func ManuallyCordonedNodes(metricsClient metrics.Metrics, machinery machinery.Machinery, c client.Client, ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) ([]string, error) {
 	nodes := &corev1.NodeList{}
 	cops := &client.ListOptions{
 		Raw: &metav1.ListOptions{
@@ -33,6 +33,7 @@ func ManuallyCordonedNodes(metricsClient metrics.Metrics, machinery machinery.Ma
 		logger.Info("Unable to fetch node list")
 		// Use PrecedingVersion as the versionLabel here because preflight check is performed before the upgrade
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.ClusterNodeQueryFailed, version, state)
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckManuallyCordonedNodes, metrics.ClusterNodeQueryFailed, err, nil)
 		return nil, err
 	}
 
@@ -53,15 +54,18 @@ func ManuallyCordonedNodes(metricsClient metrics.Metrics, machinery machinery.Ma
 		// Manually cordon node check failed, fail the healthcheck and return failed nodes
 		// Use PrecedingVersion as the versionLabel here because preflight check is performed before the upgrade
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.ClusterNodesManuallyCordoned, version, state)
-		return manuallyCordonNodes, fmt.Errorf("cordoned nodes: %s", strings.Join(manuallyCordonNodes, ", "))
+		err = fmt.Errorf("cordoned nodes: %s", strings.Join(manuallyCordonNodes, ", "))
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckManuallyCordonedNodes, metrics.ClusterNodesManuallyCordoned, err, healthCheckObjects("Node", manuallyCordonNodes))
+		return manuallyCordonNodes, err
 	}
 	logger.Info("Prehealth check for manually cordoned node passed")
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterNodeQueryFailed, version, state)
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterNodesManuallyCordoned, version, state)
+	recordHealthCheckPassed(ug, step, upgradev1alpha1.HealthCheckManuallyCordonedNodes)
 	return nil, nil
 }
 
-func NodeUnschedulableTaints(metricsClient metrics.Metrics, machinery machinery.Machinery, c client.Client, ug *upgradev1alpha1.UpgradeConfig, logger logr.Logger, version string) ([]string, error) {
+func NodeUnschedulableTaints(metricsClient metrics.Metrics, machinery machinery.Machinery, c client.Client, ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) ([]string, error) {
 	nodes := &corev1.NodeList{}
 	cops := &client.ListOptions{}
 
@@ -75,6 +79,7 @@ func NodeUnschedulableTaints(metricsClient metrics.Metrics, machinery machinery.
 		logger.Info("Unable to fetch node list")
 		// Use PrecedingVersion as the versionLabel here because preflight check is performed before the upgrade
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, metrics.ClusterNodeQueryFailed, version, state)
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckNodeUnschedulableTaints, metrics.ClusterNodeQueryFailed, err, nil)
 		return nil, err
 	}
 
@@ -125,10 +130,51 @@ func NodeUnschedulableTaints(metricsClient metrics.Metrics, machinery machinery.
 			unschedulableNodes = append(unschedulableNodes, pidPressureNodes...)
 		}
 
-		return unschedulableNodes, fmt.Errorf("unschedulable taints on nodes: %s", strings.Join(unschedulableNodes, ", "))
+		err = fmt.Errorf("unschedulable taints on nodes: %s", strings.Join(unschedulableNodes, ", "))
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckNodeUnschedulableTaints, metrics.ClusterNodesTaintedUnschedulable, err, healthCheckObjects("Node", unschedulableNodes))
+		return unschedulableNodes, err
 	}
 	logger.Info("Prehealth check for unschedulable node taints passed")
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterNodeQueryFailed, version, state)
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterNodesTaintedUnschedulable, version, state)
+	recordHealthCheckPassed(ug, step, upgradev1alpha1.HealthCheckNodeUnschedulableTaints)
 	return nil, nil
 }
+
+// manuallyCordonedNodesHealthCheck is the HealthCheck for manually cordoned worker nodes
+type manuallyCordonedNodesHealthCheck struct {
+	c *clusterUpgrader
+}
+
+// Name returns the name of the health check
+func (h *manuallyCordonedNodesHealthCheck) Name() string {
+	return upgradev1alpha1.HealthCheckManuallyCordonedNodes
+}
+
+// Check checks whether there are manually cordoned worker nodes
+func (h *manuallyCordonedNodesHealthCheck) Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error) {
+	nodes, err := ManuallyCordonedNodes(h.c.metrics, h.c.machinery, h.c.client, h.c.upgradeConfig, step, logger, version)
+	if err != nil || nodes != nil {
+		return fmt.Sprintf("NodeUnschedulableHealthcheckFailed:(%s)", strings.Join(nodes, ",")), healthCheckError(h.Name(), err)
+	}
+	return "", nil
+}
+
+// nodeUnschedulableTaintsHealthCheck is the HealthCheck for nodes tainted unschedulable
+type nodeUnschedulableTaintsHealthCheck struct {
+	c *clusterUpgrader
+}
+
+// Name returns the name of the health check
+func (h *nodeUnschedulableTaintsHealthCheck) Name() string {
+	return upgradev1alpha1.HealthCheckNodeUnschedulableTaints
+}
+
+// Check checks whether there are nodes tainted unschedulable
+func (h *nodeUnschedulableTaintsHealthCheck) Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error) {
+	nodes, err := NodeUnschedulableTaints(h.c.metrics, h.c.machinery, h.c.client, h.c.upgradeConfig, step, logger, version)
+	if err != nil || nodes != nil {
+		return fmt.Sprintf("NodeUnschedulableTaintHealthcheckFailed:(%s)", strings.Join(nodes, ",")), healthCheckError(h.Name(), err)
+	}
+	return "", nil
+}
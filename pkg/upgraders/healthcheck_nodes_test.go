@@ -81,7 +81,7 @@ var _ = Describe("HealthCheck Manually Cordoned node", func() {
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterNodeQueryFailed, gomock.Any(), gomock.Any()),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterNodesManuallyCordoned, gomock.Any(), gomock.Any()),
 			)
-			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, logger, version)
+			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(result).Should(BeNil())
 		})
@@ -106,7 +106,7 @@ var _ = Describe("HealthCheck Manually Cordoned node", func() {
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterNodeQueryFailed, gomock.Any(), gomock.Any()),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckSucceeded(upgradeConfig.Name, metrics.ClusterNodesManuallyCordoned, gomock.Any(), gomock.Any()),
 			)
-			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, logger, version)
+			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(result).Should(BeNil())
 		})
@@ -116,7 +116,7 @@ var _ = Describe("HealthCheck Manually Cordoned node", func() {
 		It("Prehealth check will fail", func() {
 			mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("Fake cannot fetch all worker nodes"))
 			mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any())
-			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, logger, version)
+			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(BeNil())
 		})
@@ -140,9 +140,15 @@ var _ = Describe("HealthCheck Manually Cordoned node", func() {
 				mockMachineryClient.EXPECT().IsNodeUpgrading(gomock.Any()).Return(false),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any()),
 			)
-			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, logger, version)
+			result, err := ManuallyCordonedNodes(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(Not(BeNil()))
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			healthCheck := history.GetHealthCheck(upgradev1alpha1.HealthCheckManuallyCordonedNodes, upgradev1alpha1.UpgradePreHealthCheck)
+			Expect(healthCheck).NotTo(BeNil())
+			Expect(healthCheck.Result).To(Equal(upgradev1alpha1.HealthCheckFailed))
+			Expect(healthCheck.Reason).To(Equal(metrics.ClusterNodesManuallyCordoned))
+			Expect(healthCheck.AffectedObjects).To(Equal([]upgradev1alpha1.HealthCheckObject{{Kind: "Node", Name: "testNode"}}))
 		})
 	})
 	Context("When nodes has unschedule taints", func() {
@@ -152,7 +158,7 @@ var _ = Describe("HealthCheck Manually Cordoned node", func() {
 				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("Fake cannot fetch all worker nodes")),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, gomock.Any(), gomock.Any(), gomock.Any()),
 			)
-			result, err := NodeUnschedulableTaints(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, logger, version)
+			result, err := NodeUnschedulableTaints(mockMetricsClient, mockMachineryClient, mockKubeClient, upgradeConfig, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).Should(HaveOccurred())
 			Expect(result).Should(BeNil())
 		})
@@ -2,12 +2,15 @@ package upgraders
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/drain"
 	"github.com/openshift/managed-upgrade-operator/pkg/dvo"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
 	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -16,10 +19,11 @@ import (
 
 var namespaceException = []string{"openshift-logging", "openshift-redhat-marketplace", "openshift-operators", "openshift-customer-monitoring", "openshift-cnv", "openshift-route-monitoring-operator", "openshift-user-workload-monitoring", "openshift-pipelines"}
 
-// HealthCheckPDB performs a health check on the PodDisruptionBudget (PDB) metrics.
+// HealthCheckPDB performs a health check on the PodDisruptionBudget (PDB) metrics, and on the
+// PDBs which a simulated drain of the worker nodes predicts would refuse the eviction of pods.
 // It returns true if the health check passes, false otherwise.
 // It also returns an error if there was an issue performing the health check.
-func HealthCheckPDB(metricsClient metrics.Metrics, c client.Client, dvo dvo.DvoClientBuilder, ug *upgradev1alpha1.UpgradeConfig, logger logr.Logger, version string) ([]PDBDetails, bool, error) {
+func HealthCheckPDB(metricsClient metrics.Metrics, c client.Client, dvo dvo.DvoClientBuilder, ug *upgradev1alpha1.UpgradeConfig, cfg *drain.NodeDrain, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) ([]PDBDetails, bool, error) {
 
 	// Get current cluster version and upgrade state info
 	history := ug.Status.History.GetHistory(ug.Spec.Desired.Version)
@@ -28,21 +32,40 @@ func HealthCheckPDB(metricsClient metrics.Metrics, c client.Client, dvo dvo.DvoC
 	pdbDetails, reason, err := checkPodDisruptionBudgets(c, logger)
 	if err != nil {
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, reason, version, state)
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckPodDisruptionBudgets, reason, err, pdbObjects(pdbDetails))
+		return pdbDetails, false, err
+	}
+
+	pdbDetails, reason, err = checkDrainSimulation(c, ug, cfg, logger)
+	if err != nil {
+		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, reason, version, state)
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckPodDisruptionBudgets, reason, err, pdbObjects(pdbDetails))
 		return pdbDetails, false, err
 	}
 
 	reason, err = checkDvoMetrics(c, dvo, logger)
 	if err != nil {
 		metricsClient.UpdateMetricHealthcheckFailed(ug.Name, reason, version, state)
+		recordHealthCheckFailed(ug, step, upgradev1alpha1.HealthCheckPodDisruptionBudgets, reason, err, pdbObjects(pdbDetails))
 		return pdbDetails, false, err
 	}
 	// Health check passed
 	logger.Info("Prehealth check for PodDisruptionBudget passed")
 	metricsClient.UpdateMetricHealthcheckSucceeded(ug.Name, metrics.ClusterInvalidPDB, version, state)
+	recordHealthCheckPassed(ug, step, upgradev1alpha1.HealthCheckPodDisruptionBudgets)
 
 	return pdbDetails, true, nil
 }
 
+// pdbObjects returns the PodDisruptionBudgets as health check objects
+func pdbObjects(pdbDetails []PDBDetails) []upgradev1alpha1.HealthCheckObject {
+	objects := []upgradev1alpha1.HealthCheckObject{}
+	for _, pdb := range pdbDetails {
+		objects = append(objects, upgradev1alpha1.HealthCheckObject{Kind: "PodDisruptionBudget", Namespace: pdb.Namespace, Name: pdb.Name})
+	}
+	return objects
+}
+
 func checkPodDisruptionBudgets(c client.Client, logger logr.Logger) ([]PDBDetails, string, error) {
 	// List all PodDisruptionBudgets
 	pdbList := &policyv1.PodDisruptionBudgetList{}
@@ -54,7 +77,7 @@ func checkPodDisruptionBudgets(c client.Client, logger logr.Logger) ([]PDBDetail
 	}
 
 	for _, pdb := range pdbList.Items {
-		if !strings.HasPrefix(pdb.Namespace, "openshift-*") || checkNamespaceExistsInArray(namespaceException, pdb.Namespace) {
+		if isCheckedPDBNamespace(pdb.Namespace) {
 			pdbDetail := PDBDetails{}
 			pdbDetail.Name = pdb.Name
 			pdbDetail.Namespace = pdb.Namespace
@@ -76,6 +99,46 @@ func checkPodDisruptionBudgets(c client.Client, logger logr.Logger) ([]PDBDetail
 	return pdbDetails, "", nil
 }
 
+// checkDrainSimulation simulates the drain of the worker nodes and returns the PodDisruptionBudgets
+// which are predicted to refuse the eviction of pods
+func checkDrainSimulation(c client.Client, ug *upgradev1alpha1.UpgradeConfig, cfg *drain.NodeDrain, logger logr.Logger) ([]PDBDetails, string, error) {
+	pdbDetails := []PDBDetails{}
+	nodes, err := machinery.ListWorkerNodes(c)
+	if err != nil {
+		logger.Info("Unable to fetch node list")
+		return pdbDetails, metrics.ClusterNodeQueryFailed, err
+	}
+
+	plans, err := drain.SimulateNodeDrains(c, nodes.Items, ug, cfg, logger)
+	if err != nil {
+		logger.Info("Unable to simulate the drain of the worker nodes")
+		return pdbDetails, metrics.DrainSimulationFailed, err
+	}
+
+	var blocking []string
+	for i := range plans {
+		for _, pdb := range plans[i].BlockingPDBs() {
+			namespace, name, _ := strings.Cut(pdb, "/")
+			if !isCheckedPDBNamespace(namespace) || checkNamespaceExistsInArray(blocking, pdb) {
+				continue
+			}
+			logger.Info(fmt.Sprintf("PodDisruptionBudget %s is predicted to block the drain of node %s", pdb, plans[i].Node))
+			blocking = append(blocking, pdb)
+			pdbDetails = append(pdbDetails, PDBDetails{Name: name, Namespace: namespace})
+		}
+	}
+	if len(blocking) > 0 {
+		return pdbDetails, metrics.ClusterDrainBlockingPDB, fmt.Errorf("PodDisruptionBudgets predicted to block the drain of worker nodes: %s", strings.Join(blocking, ", "))
+	}
+
+	return pdbDetails, "", nil
+}
+
+// isCheckedPDBNamespace returns whether the PodDisruptionBudgets of the namespace are health checked
+func isCheckedPDBNamespace(namespace string) bool {
+	return !strings.HasPrefix(namespace, "openshift-") || checkNamespaceExistsInArray(namespaceException, namespace)
+}
+
 func checkNamespaceExistsInArray(namespaceException []string, s string) bool {
 	for _, namespace := range namespaceException {
 		if namespace == s {
@@ -147,3 +210,26 @@ func validateMinAvailable(p policyv1.PodDisruptionBudget, l logr.Logger) (bool,
 	}
 	return true, nil
 }
+
+// podDisruptionBudgetsHealthCheck is the HealthCheck for misconfigured PodDisruptionBudgets
+type podDisruptionBudgetsHealthCheck struct {
+	c *clusterUpgrader
+}
+
+// Name returns the name of the health check
+func (h *podDisruptionBudgetsHealthCheck) Name() string {
+	return upgradev1alpha1.HealthCheckPodDisruptionBudgets
+}
+
+// Check checks whether there are PodDisruptionBudgets which would block node drains
+func (h *podDisruptionBudgetsHealthCheck) Check(ctx context.Context, step upgradev1alpha1.UpgradeConditionType, logger logr.Logger, version string) (string, error) {
+	pdbDetails, ok, err := HealthCheckPDB(h.c.metrics, h.c.client, h.c.dvo, h.c.upgradeConfig, &h.c.config.NodeDrain, step, logger, version)
+	if err != nil || !ok {
+		pdbList, mErr := json.Marshal(&pdbDetails)
+		if mErr != nil {
+			logger.Info(fmt.Sprintf("upgrade delayed due PDB: Marshal error %s", mErr))
+		}
+		return "PDBHealthcheckFailed: " + string(pdbList), healthCheckError(h.Name(), err)
+	}
+	return "", nil
+}
@@ -6,13 +6,17 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	machineconfigv1 "github.com/openshift/api/machineconfiguration/v1"
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/drain"
 	dvoMocks "github.com/openshift/managed-upgrade-operator/pkg/dvo/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
 	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
 	"github.com/openshift/managed-upgrade-operator/util/mocks"
 	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
 	gomock "go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -83,7 +87,7 @@ var _ = Describe("checkPodDisruptionBudgets", func() {
 				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pdbList),
 				mockMetricsClient.EXPECT().UpdateMetricHealthcheckFailed(upgradeConfig.Name, reason, version, "New"),
 			)
-			pdbDetails, result, err := HealthCheckPDB(mockMetricsClient, mockClient, mockdvoclientbulder, upgradeConfig, logger, version)
+			pdbDetails, result, err := HealthCheckPDB(mockMetricsClient, mockClient, mockdvoclientbulder, upgradeConfig, &drain.NodeDrain{}, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
 			Expect(err).To(HaveOccurred())
 			Expect(result).To(Equal(false))
 			Expect(pdbDetails).ShouldNot(BeEmpty())
@@ -234,6 +238,86 @@ var _ = Describe("checkPodDisruptionBudgets", func() {
 		})
 	})
 
+	Context("When simulating the drain of the worker nodes", func() {
+		var (
+			nodes   *corev1.NodeList
+			pdbList *policyv1.PodDisruptionBudgetList
+			pods    *corev1.PodList
+		)
+
+		BeforeEach(func() {
+			nodes = &corev1.NodeList{
+				Items: []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}},
+			}
+			pdbList = &policyv1.PodDisruptionBudgetList{
+				Items: []policyv1.PodDisruptionBudget{
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "my-app", Name: "pdb-1"},
+						Spec: policyv1.PodDisruptionBudgetSpec{
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+						},
+					},
+				},
+			}
+			pods = &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "my-app", Name: "pod-1", Labels: map[string]string{"app": "my-app"}},
+						Spec:       corev1.PodSpec{NodeName: "worker-1"},
+					},
+				},
+			}
+		})
+
+		It("fails if a PodDisruptionBudget allows no disruption of a pod on a worker node", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *workerPools()),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *nodes),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pdbList),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pods),
+			)
+			pdbDetails, result, err := checkDrainSimulation(mockClient, upgradeConfig, &drain.NodeDrain{}, logger)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(Equal(metrics.ClusterDrainBlockingPDB))
+			Expect(pdbDetails).To(Equal([]PDBDetails{{Name: "pdb-1", Namespace: "my-app"}}))
+		})
+
+		It("passes if the PodDisruptionBudgets allow the disruption of the pods", func() {
+			pdbList.Items[0].Status.DisruptionsAllowed = 1
+			gomock.InOrder(
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *workerPools()),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *nodes),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pdbList),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pods),
+			)
+			pdbDetails, result, err := checkDrainSimulation(mockClient, upgradeConfig, &drain.NodeDrain{}, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeEmpty())
+			Expect(pdbDetails).To(BeEmpty())
+		})
+
+		It("only simulates the drain of the nodes of the worker MachineConfigPools", func() {
+			pools := workerPools()
+			pools.Items[0].Spec.NodeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/worker": ""}}
+			gomock.InOrder(
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pools),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *nodes),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(1, *pdbList),
+			)
+			pdbDetails, result, err := checkDrainSimulation(mockClient, upgradeConfig, &drain.NodeDrain{}, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeEmpty())
+			Expect(pdbDetails).To(BeEmpty())
+		})
+
+		It("fails if the worker nodes cannot be listed", func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+			_, result, err := checkDrainSimulation(mockClient, upgradeConfig, &drain.NodeDrain{}, logger)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(Equal(metrics.ClusterNodeQueryFailed))
+		})
+	})
+
 	Context("When DVO service is down", func() {
 		It("checkDvoMetrics check will fail", func() {
 			reason := "DVO metrics query failed"
@@ -264,6 +348,14 @@ var _ = Describe("checkPodDisruptionBudgets", func() {
 		})
 	})
 
+	Context("When checking whether the PDBs of a namespace are health checked", func() {
+		It("skips the openshift namespaces which are not excepted", func() {
+			Expect(isCheckedPDBNamespace("openshift-monitoring")).To(BeFalse())
+			Expect(isCheckedPDBNamespace("openshift-logging")).To(BeTrue())
+			Expect(isCheckedPDBNamespace("my-namespace")).To(BeTrue())
+		})
+	})
+
 	Context("When there is an error getting DVO metrics", func() {
 		It("should return DvoMetricsQueryFailed", func() {
 
@@ -288,3 +380,13 @@ var _ = Describe("checkPodDisruptionBudgets", func() {
 		})
 	})
 })
+
+// workerPools returns a worker MachineConfigPool list whose pool selects every node
+func workerPools() *machineconfigv1.MachineConfigPoolList {
+	return &machineconfigv1.MachineConfigPoolList{
+		Items: []machineconfigv1.MachineConfigPool{{
+			ObjectMeta: metav1.ObjectMeta{Name: machinery.WorkerPool},
+			Spec:       machineconfigv1.MachineConfigPoolSpec{NodeSelector: &metav1.LabelSelector{}},
+		}},
+	}
+}
@@ -0,0 +1,81 @@
+package upgraders
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+// recordHealthCheckPassed records in the UpgradeConfig's history that a health check
+// run by the given upgrade step has passed
+func recordHealthCheckPassed(ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, name string) {
+	recordHealthCheck(ug, upgradev1alpha1.HealthCheckResult{
+		Name:   name,
+		Step:   step,
+		Result: upgradev1alpha1.HealthCheckPassed,
+	})
+}
+
+// recordHealthCheckFailed records in the UpgradeConfig's history that a health check
+// run by the given upgrade step has failed, along with the objects that caused it to fail
+func recordHealthCheckFailed(ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, name string, reason string, err error, objects []upgradev1alpha1.HealthCheckObject) {
+	result := upgradev1alpha1.HealthCheckResult{
+		Name:            name,
+		Step:            step,
+		Result:          upgradev1alpha1.HealthCheckFailed,
+		Reason:          reason,
+		AffectedObjects: objects,
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	recordHealthCheck(ug, result)
+}
+
+// clearHealthCheck removes the result of a health check run by the given upgrade step from
+// the UpgradeConfig's history, for a health check which could not be run
+func clearHealthCheck(ug *upgradev1alpha1.UpgradeConfig, step upgradev1alpha1.UpgradeConditionType, name string) {
+	history := ug.Status.History.GetHistory(ug.Spec.Desired.Version)
+	if history == nil {
+		return
+	}
+	results := []upgradev1alpha1.HealthCheckResult{}
+	for _, result := range history.HealthChecks {
+		if result.Name != name || result.Step != step {
+			results = append(results, result)
+		}
+	}
+	history.HealthChecks = results
+	ug.Status.History.SetHistory(*history)
+}
+
+func recordHealthCheck(ug *upgradev1alpha1.UpgradeConfig, result upgradev1alpha1.HealthCheckResult) {
+	history := ug.Status.History.GetHistory(ug.Spec.Desired.Version)
+	if history == nil {
+		return
+	}
+	result.Time = metav1.Time{Time: time.Now()}
+	result.LastTransitionTime = &result.Time
+	if previous := history.GetHealthCheck(result.Name, result.Step); previous != nil && previous.Result == result.Result && previous.LastTransitionTime != nil {
+		result.LastTransitionTime = previous.LastTransitionTime
+	}
+	history.SetHealthCheck(result)
+	ug.Status.History.SetHistory(*history)
+}
+
+// healthCheckObjects returns the named objects of the given kind as health check objects,
+// omitting any duplicate names
+func healthCheckObjects(kind string, names []string) []upgradev1alpha1.HealthCheckObject {
+	objects := []upgradev1alpha1.HealthCheckObject{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		objects = append(objects, upgradev1alpha1.HealthCheckObject{Kind: kind, Name: name})
+	}
+	return objects
+}
@@ -2,7 +2,6 @@ package upgraders
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,7 +9,6 @@ import (
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
 	cv "github.com/openshift/managed-upgrade-operator/pkg/clusterversion"
-	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
 	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
 )
 
@@ -32,104 +30,57 @@ func (c *clusterUpgrader) PreUpgradeHealthCheck(ctx context.Context, logger logr
 
 	version := getCurrentVersion(c.cvClient, logger)
 
-	// Based on the "PreHealthCheck" featuregate, we invoke the legacy healthchecks for clusteroperator and critical alerts
-	// which will not be tied to the notifications but only log the error and set metric.
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	phase := healthCheckPhasePreSchedule
+	if history.Phase == upgradev1alpha1.UpgradePhaseUpgrading {
+		phase = healthCheckPhasePreUpgrade
+	}
+
+	// Without the "PreHealthCheck" featuregate, only the legacy healthchecks for clusteroperator and critical alerts run
+	checks := c.healthChecksFor(phase)
 	if !c.config.IsFeatureEnabled(string(upgradev1alpha1.PreHealthCheckFeatureGate)) {
-		ok, err := CriticalAlerts(c.metrics, c.config, c.upgradeConfig, logger, version)
-		if err != nil || !ok {
-			return false, err
-		}
+		checks = withNames(checks, legacyHealthChecks)
+	}
 
-		ok, err = ClusterOperators(c.metrics, c.cvClient, c.upgradeConfig, logger, version)
-		if err != nil || !ok {
-			return false, err
+	failures := c.runHealthChecks(ctx, checks, upgradev1alpha1.UpgradePreHealthCheck, logger, version)
+	healthCheckFailed := []string{}
+	var blockedBy error
+	for _, failure := range failures {
+		healthCheckFailed = append(healthCheckFailed, failure.result)
+		if failure.blocking && blockedBy == nil {
+			blockedBy = failure.err
 		}
 	}
+	blocked := blockedBy != nil
 
-	// We invoke and handle the additional healthchecks accordingly with notifications enabled (or disabled via it's own featuregate)
-	if len(c.config.FeatureGate.Enabled) > 0 && c.config.IsFeatureEnabled(string(upgradev1alpha1.PreHealthCheckFeatureGate)) {
-
-		healthCheckFailed := []string{}
-		var pdbDetails []PDBDetails
-		history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
-		state := string(history.Phase)
+	// Based on the "PreHealthCheck" featuregate, the healthchecks are either not tied to the notifications but only
+	// log the error and set metric, or are handled accordingly with notifications enabled (or disabled via it's own featuregate)
+	if !c.config.IsFeatureEnabled(string(upgradev1alpha1.PreHealthCheckFeatureGate)) {
+		return !blocked, blockedBy
+	}
 
-		ok, err := CriticalAlerts(c.metrics, c.config, c.upgradeConfig, logger, version)
-		if err != nil || !ok {
-			logger.Info("upgrade may delay due to firing critical alerts")
-			healthCheckFailed = append(healthCheckFailed, "CriticalAlertsHealthcheckFailed")
-		}
+	if len(healthCheckFailed) > 0 {
+		result := strings.Join(healthCheckFailed, ",")
+		logger.Info(fmt.Sprintf("Upgrade may delay due to following PreHealthCheck failure: %s", result))
 
-		ok, err = ClusterOperators(c.metrics, c.cvClient, c.upgradeConfig, logger, version)
-		if err != nil || !ok {
-			logger.Info("upgrade may delay due to cluster operators not ready")
-			healthCheckFailed = append(healthCheckFailed, "ClusterOperatorsHealthcheckFailed")
-		}
-
-		if c.upgradeConfig.Spec.CapacityReservation {
-			ok, err := c.scaler.CanScale(c.client, logger)
-			if !ok || err != nil {
-				c.metrics.UpdateMetricHealthcheckFailed(c.upgradeConfig.Name, metrics.DefaultWorkerMachinepoolNotFound, version, state)
-				healthCheckFailed = append(healthCheckFailed, "CapacityReservationHealthcheckFailed")
-			} else {
-				logger.Info("Prehealth check for CapacityReservation passed")
-				c.metrics.UpdateMetricHealthcheckSucceeded(c.upgradeConfig.Name, metrics.DefaultWorkerMachinepoolNotFound, version, state)
+		switch history.Phase {
+		case upgradev1alpha1.UpgradePhaseNew:
+			err := c.notifier.NotifyResult(notifier.MuoStatePreHealthCheckSL, result)
+			if err != nil {
+				return false, err
 			}
-		}
-		var nodes []string
-		nodes, err = ManuallyCordonedNodes(c.metrics, c.machinery, c.client, c.upgradeConfig, logger, version)
-		if err != nil || nodes != nil {
-			logger.Info(fmt.Sprintf("upgrade may delay due to there are manually cordoned nodes: %s", err))
-			nodeNames := strings.Join(nodes, ",")
-			healthCheckFailed = append(healthCheckFailed, fmt.Sprintf("NodeUnschedulableHealthcheckFailed:(%s)", nodeNames))
-		}
-
-		nodes, err = NodeUnschedulableTaints(c.metrics, c.machinery, c.client, c.upgradeConfig, logger, version)
-		if err != nil || nodes != nil {
-			logger.Info(fmt.Sprintf("upgrade delayed due to there are unschedulable taints on nodes: %s", err))
-			nodeNames := strings.Join(nodes, ",")
-			healthCheckFailed = append(healthCheckFailed, fmt.Sprintf("NodeUnschedulableTaintHealthcheckFailed:(%s)", nodeNames))
-		}
-
-		// HealthCheckPDB
-		pdbDetails, ok, err = HealthCheckPDB(c.metrics, c.client, c.dvo, c.upgradeConfig, logger, version)
-		if err != nil || !ok {
-			logger.Info(fmt.Sprintf("upgrade delayed due PDB %s", err))
-			pdbList, err := json.Marshal(&pdbDetails)
+		case upgradev1alpha1.UpgradePhaseUpgrading:
+			err := c.notifier.NotifyResult(notifier.MuoStateHealthCheckSL, result)
 			if err != nil {
-				logger.Info(fmt.Sprintf("upgrade delayed due PDB: Marshal error %s", err))
+				return false, err
 			}
-			healthCheckFailed = append(healthCheckFailed, "PDBHealthcheckFailed: "+string(pdbList))
-		}
 
-		if len(healthCheckFailed) > 0 {
-			result := strings.Join(healthCheckFailed, ",")
-			logger.Info(fmt.Sprintf("Upgrade may delay due to following PreHealthCheck failure: %s", result))
-
-			switch history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version); history.Phase {
-			case upgradev1alpha1.UpgradePhaseNew:
-				err := c.notifier.NotifyResult(notifier.MuoStatePreHealthCheckSL, result)
-				if err != nil {
-					return false, err
-				}
-			case upgradev1alpha1.UpgradePhaseUpgrading:
-				err := c.notifier.NotifyResult(notifier.MuoStateHealthCheckSL, result)
-				if err != nil {
-					return false, err
-				}
-
-				// Return false if the healthCheckFailed slice contains "CriticalAlertsHealthcheckFailed" or "ClusterOperatorsHealthcheckFailed"
-				for _, healthcheck := range healthCheckFailed {
-					if healthcheck == "CriticalAlertsHealthcheckFailed" || healthcheck == "ClusterOperatorsHealthcheckFailed" {
-						return false, nil
-					}
-				}
-				return true, nil
-			case " ":
-				logger.Info(fmt.Sprintf("upgradeconfig history doesn't exist for version: %s", c.upgradeConfig.Spec.Desired.Version))
-			}
-			return false, nil
+			// Only the failures of blocking health checks hold the upgrade
+			return !blocked, nil
+		case " ":
+			logger.Info(fmt.Sprintf("upgradeconfig history doesn't exist for version: %s", c.upgradeConfig.Spec.Desired.Version))
 		}
+		return false, nil
 	}
 	return true, nil
 }
@@ -137,14 +88,15 @@ func (c *clusterUpgrader) PreUpgradeHealthCheck(ctx context.Context, logger logr
 // PostUpgradeHealthCheck performs cluster healthy check
 func (c *clusterUpgrader) PostUpgradeHealthCheck(ctx context.Context, logger logr.Logger) (bool, error) {
 	version := getCurrentVersion(c.cvClient, logger)
-	ok, err := CriticalAlerts(c.metrics, c.config, c.upgradeConfig, logger, version)
-	if err != nil || !ok {
-		return false, err
-	}
-
-	ok, err = ClusterOperators(c.metrics, c.cvClient, c.upgradeConfig, logger, version)
-	if err != nil || !ok {
-		return false, err
+	for _, hc := range c.healthChecksFor(healthCheckPhasePostUpgrade) {
+		_, err := hc.Check(ctx, upgradev1alpha1.PostClusterHealthCheck, logger, version)
+		if err == nil {
+			continue
+		}
+		if hc.severity == healthCheckSeverityBlock {
+			return false, err
+		}
+		logger.Info(fmt.Sprintf("Health check %s failed: %s", hc.Name(), err))
 	}
 
 	// Reset all node drain metrics after successful upgrade to prevent stale alerts
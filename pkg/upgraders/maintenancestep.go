@@ -31,7 +31,7 @@ func (c *clusterUpgrader) RemoveControlPlaneMaintWindow(ctx context.Context, log
 
 // CreateWorkerMaintWindow creates the maintenance window for workers
 func (c *clusterUpgrader) CreateWorkerMaintWindow(ctx context.Context, logger logr.Logger) (bool, error) {
-	upgradingResult, err := c.machinery.IsUpgrading(c.client, "worker")
+	upgradingResult, err := c.machinery.IsWorkerPoolsUpgrading(c.client)
 	if err != nil {
 		return false, err
 	}
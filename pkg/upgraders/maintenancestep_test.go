@@ -114,7 +114,7 @@ var _ = Describe("MaintenanceStep", func() {
 
 	Context("When creating a worker maintenance window", func() {
 		It("Asks the maintenance client to do so", func() {
-			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true, MachineCount: 4, UpdatedCount: 2}, nil)
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true, MachineCount: 4, UpdatedCount: 2}}, nil)
 			mockMaintClient.EXPECT().SetWorker(gomock.Any(), upgradeConfig.Spec.Desired.Version, gomock.Any())
 			result, err := upgrader.CreateWorkerMaintWindow(context.TODO(), logger)
 			Expect(err).NotTo(HaveOccurred())
@@ -122,7 +122,7 @@ var _ = Describe("MaintenanceStep", func() {
 		})
 		It("Indicates when creating the maintenance window has failed", func() {
 			fakeError := fmt.Errorf("fake error")
-			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true, MachineCount: 4, UpdatedCount: 2}, nil)
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true, MachineCount: 4, UpdatedCount: 2}}, nil)
 			mockMaintClient.EXPECT().SetWorker(gomock.Any(), upgradeConfig.Spec.Desired.Version, gomock.Any()).Return(fakeError)
 			result, err := upgrader.CreateWorkerMaintWindow(context.TODO(), logger)
 			Expect(err).To(HaveOccurred())
@@ -130,19 +130,19 @@ var _ = Describe("MaintenanceStep", func() {
 			Expect(result).To(BeFalse())
 		})
 		It("Skip creating maintenance window if no pending worker node left", func() {
-			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true, MachineCount: 4, UpdatedCount: 4}, nil)
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true, MachineCount: 4, UpdatedCount: 4}}, nil)
 			result, err := upgrader.CreateWorkerMaintWindow(context.TODO(), logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(BeTrue())
 		})
 		It("Does not proceed if isUpgrading check fails", func() {
-			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(nil, fmt.Errorf("fake error"))
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(nil, fmt.Errorf("fake error"))
 			result, err := upgrader.CreateWorkerMaintWindow(context.TODO(), logger)
 			Expect(err).To(HaveOccurred())
 			Expect(result).To(BeFalse())
 		})
 		It("Will not do so if workers are already upgraded", func() {
-			mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: false}, nil)
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: false}}, nil)
 			result, err := upgrader.CreateWorkerMaintWindow(context.TODO(), logger)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(BeTrue())
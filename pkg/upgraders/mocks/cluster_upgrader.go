@@ -56,6 +56,21 @@ func (mr *MockClusterUpgraderMockRecorder) HealthCheck(arg0, arg1, arg2 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockClusterUpgrader)(nil).HealthCheck), arg0, arg1, arg2)
 }
 
+// Preflight mocks base method.
+func (m *MockClusterUpgrader) Preflight(arg0 context.Context, arg1 *v1alpha1.UpgradeConfig, arg2 logr.Logger) ([]v1alpha1.PreflightCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preflight", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]v1alpha1.PreflightCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preflight indicates an expected call of Preflight.
+func (mr *MockClusterUpgraderMockRecorder) Preflight(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preflight", reflect.TypeOf((*MockClusterUpgrader)(nil).Preflight), arg0, arg1, arg2)
+}
+
 // UpgradeCluster mocks base method.
 func (m *MockClusterUpgrader) UpgradeCluster(arg0 context.Context, arg1 *v1alpha1.UpgradeConfig, arg2 logr.Logger) (v1alpha1.UpgradePhase, error) {
 	m.ctrl.T.Helper()
@@ -2,10 +2,12 @@ package upgraders
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
@@ -30,7 +32,7 @@ type osdUpgrader struct {
 }
 
 // NewOSDUpgrader creates a new instance of an osdUpgrader
-func NewOSDUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics.Metrics, notifier eventmanager.EventManager) (*osdUpgrader, error) {
+func NewOSDUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics.Metrics, notifier eventmanager.EventManager, recorder record.EventRecorder) (*osdUpgrader, error) {
 	cfg := &upgraderConfig{}
 	err := cfm.Into(cfg)
 	if err != nil {
@@ -55,7 +57,8 @@ func NewOSDUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics
 			notifier:             notifier,
 			config:               cfg,
 			scaler:               scaler.NewScaler(),
-			drainstrategyBuilder: drain.NewBuilder(),
+			drainstrategyBuilder: drain.NewBuilder(recorder),
+			recorder:             recorder,
 			maintenance:          m,
 			machinery:            machinery.NewMachinery(),
 			availabilityCheckers: acs,
@@ -74,7 +77,11 @@ func NewOSDUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics
 		upgradesteps.Action(string(upgradev1alpha1.CommenceUpgrade), ou.CommenceUpgrade),
 		upgradesteps.Action(string(upgradev1alpha1.ControlPlaneUpgraded), ou.ControlPlaneUpgraded),
 		upgradesteps.Action(string(upgradev1alpha1.RemoveControlPlaneMaintWindow), ou.RemoveControlPlaneMaintWindow),
+		upgradesteps.Action(string(upgradev1alpha1.ControlPlaneSoak), ou.ControlPlaneSoak),
 		upgradesteps.Action(string(upgradev1alpha1.WorkersMaintWindow), ou.CreateWorkerMaintWindow),
+		upgradesteps.Action(string(upgradev1alpha1.CanaryWorkerNodesUpgraded), ou.CanaryWorkersUpgraded),
+		upgradesteps.Action(string(upgradev1alpha1.CanaryHealthCheck), ou.CanaryHealthCheck),
+		upgradesteps.Action(string(upgradev1alpha1.ReleaseWorkerNodes), ou.ReleaseWorkers),
 		upgradesteps.Action(string(upgradev1alpha1.AllWorkerNodesUpgraded), ou.AllWorkersUpgraded),
 		upgradesteps.Action(string(upgradev1alpha1.RemoveExtraScaledNodes), ou.RemoveExtraScaledNodes),
 		upgradesteps.Action(string(upgradev1alpha1.RemoveMaintWindow), ou.RemoveMaintWindow),
@@ -82,7 +89,11 @@ func NewOSDUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics
 		upgradesteps.Action(string(upgradev1alpha1.PostUpgradeProcedures), ou.PostUpgradeProcedures),
 		upgradesteps.Action(string(upgradev1alpha1.SendCompletedNotification), ou.SendCompletedNotification),
 	}
-	ou.steps = steps
+	steps, err = withCustomSteps(steps, cfg, ou.clusterUpgrader)
+	if err != nil {
+		return nil, err
+	}
+	ou.steps = withStepTimeouts(steps, cfg)
 
 	return &ou, nil
 }
@@ -95,9 +106,14 @@ func NewOSDUpgrader(c client.Client, cfm configmanager.ConfigManager, mc metrics
 func (u *osdUpgrader) UpgradeCluster(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) (upgradev1alpha1.UpgradePhase, error) {
 	u.upgradeConfig = upgradeConfig
 
-	// OSD upgrader enforces a 'failure' policy if the upgrade does not commence within a time period
+	// OSD upgrader enforces a 'failure' policy if the upgrade does not commence within a time period.
+	// The policy is not enforced while the upgrade is paused.
 	if cancelUpgrade, _ := shouldFailUpgrade(u.cvClient, u.config, u.upgradeConfig); cancelUpgrade {
-		return performUpgradeFailure(u.client, u.metrics, u.scaler, u.notifier, u.upgradeConfig, logger)
+		phase, err := performUpgradeFailure(u.client, u.metrics, u.scaler, u.notifier, u.machinery, u.upgradeConfig, logger)
+		if phase == upgradev1alpha1.UpgradePhaseFailed {
+			u.recordEvent(corev1.EventTypeWarning, UpgradeFailedReason, fmt.Sprintf("Upgrade to version %s has failed as it did not commence in time", u.upgradeConfig.Spec.Desired.Version))
+		}
+		return phase, err
 	}
 
 	return u.runSteps(ctx, logger, u.steps)
@@ -116,6 +132,10 @@ func (u *osdUpgrader) HealthCheck(ctx context.Context, upgradeConfig *upgradev1a
 // If the cluster should fail its upgrade a condition of 'true' is returned.
 // Any error encountered in making this decision is returned.
 func shouldFailUpgrade(cvClient cv.ClusterVersion, cfg *upgraderConfig, upgradeConfig *upgradev1alpha1.UpgradeConfig) (bool, error) {
+	if upgradeConfig.IsPaused() {
+		return false, nil
+	}
+
 	commenced, err := cvClient.HasUpgradeCommenced(upgradeConfig)
 	if err != nil {
 		return false, err
@@ -132,15 +152,17 @@ func shouldFailUpgrade(cvClient cv.ClusterVersion, cfg *upgraderConfig, upgradeC
 	}
 	startTime := h.StartTime.Time
 
+	// The time the upgrade spent paused does not count towards its window
+	now := time.Now()
 	upgradeWindowDuration := cfg.UpgradeWindow.GetUpgradeWindowTimeOutDuration()
-	if !startTime.IsZero() && upgradeWindowDuration > 0 && time.Now().After(startTime.Add(upgradeWindowDuration)) {
+	if !startTime.IsZero() && upgradeWindowDuration > 0 && now.Sub(startTime)-upgradesteps.UpgradePausedDuration(h, now) > upgradeWindowDuration {
 		return true, nil
 	}
 	return false, nil
 }
 
 // performUpgradeFailure carries out routines related to moving to an upgrade-failed state
-func performUpgradeFailure(c client.Client, metricsClient metrics.Metrics, s scaler.Scaler, nc eventmanager.EventManager, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) (upgradev1alpha1.UpgradePhase, error) {
+func performUpgradeFailure(c client.Client, metricsClient metrics.Metrics, s scaler.Scaler, nc eventmanager.EventManager, m machinery.Machinery, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) (upgradev1alpha1.UpgradePhase, error) {
 	// Set up return condition
 	h := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
 	condition := &upgradev1alpha1.UpgradeCondition{
@@ -150,8 +172,16 @@ func performUpgradeFailure(c client.Client, metricsClient metrics.Metrics, s sca
 		Message: "FailedUpgrade notification sent",
 	}
 
+	// Restore the maxUnavailable of the worker MachineConfigPools tuned during the upgrade
+	upgradingResult, err := m.IsWorkerPoolsUpgrading(c)
+	if err != nil {
+		logger.Error(err, "Failed to get the worker MachineConfigPools to restore their maxUnavailable when upgrade failed")
+	} else {
+		restoreWorkerMaxUnavailable(c, m, upgradingResult.Pools, logger)
+	}
+
 	// TearDown the extra machineset
-	_, err := s.EnsureScaleDownNodes(c, nil, logger)
+	_, err = s.EnsureScaleDownNodes(c, nil, logger)
 	if err != nil {
 		logger.Error(err, "Failed to scale down the temporary upgrade machine when upgrade failed")
 		h.Conditions.SetCondition(*condition)
@@ -0,0 +1,92 @@
+package upgraders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
+)
+
+// Preflight runs the upgrade steps that are carried out before an upgrade commences,
+// without commencing it, and returns the result of each. Notifications raised by the
+// steps are recorded into the results instead of being sent.
+func (c *clusterUpgrader) Preflight(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) ([]upgradev1alpha1.PreflightCheck, error) {
+	c.upgradeConfig = upgradeConfig
+
+	recorder := &preflightNotifier{}
+	sender := c.notifier
+	c.notifier = recorder
+	defer func() { c.notifier = sender }()
+
+	steps := []struct {
+		name upgradev1alpha1.UpgradeConditionType
+		run  func(context.Context, logr.Logger) (bool, error)
+	}{
+		{upgradev1alpha1.IsClusterUpgradable, c.IsUpgradeable},
+		{upgradev1alpha1.UpgradePreHealthCheck, c.PreUpgradeHealthCheck},
+		{upgradev1alpha1.ExtDepAvailabilityCheck, c.ExternalDependencyAvailabilityCheck},
+	}
+
+	checks := []upgradev1alpha1.PreflightCheck{}
+	for _, step := range steps {
+		recorder.results = nil
+		logger.Info(fmt.Sprintf("Running preflight check %s", step.name))
+		ok, err := step.run(ctx, logger)
+		checks = append(checks, preflightCheck(string(step.name), ok, err, recorder.results))
+	}
+
+	if !upgradeConfig.Spec.CapacityReservation {
+		checks = append(checks, upgradev1alpha1.PreflightCheck{
+			Name:    upgradev1alpha1.PreflightCheckCanScale,
+			Result:  upgradev1alpha1.PreflightCheckSkipped,
+			Message: "Capacity reservation is not requested",
+		})
+	} else {
+		ok, err := c.scaler.CanScale(c.client, logger)
+		checks = append(checks, preflightCheck(upgradev1alpha1.PreflightCheckCanScale, ok, err, nil))
+	}
+
+	return checks, nil
+}
+
+// preflightCheck builds the result of a preflight check from the outcome of running it
+func preflightCheck(name string, ok bool, err error, results []string) upgradev1alpha1.PreflightCheck {
+	check := upgradev1alpha1.PreflightCheck{
+		Name:   name,
+		Result: upgradev1alpha1.PreflightCheckPassed,
+	}
+	if err == nil && ok {
+		return check
+	}
+
+	check.Result = upgradev1alpha1.PreflightCheckFailed
+	switch {
+	case err != nil:
+		check.Message = err.Error()
+	case len(results) > 0:
+		check.Message = results[len(results)-1]
+	default:
+		check.Message = "Check did not pass, see the operator logs for details"
+	}
+	return check
+}
+
+// preflightNotifier is an eventmanager.EventManager which records the results of
+// notifications raised during preflight checks rather than sending them
+type preflightNotifier struct {
+	results []string
+}
+
+// Notify discards the notification
+func (p *preflightNotifier) Notify(state notifier.MuoState) error {
+	return nil
+}
+
+// NotifyResult records the result of the notification
+func (p *preflightNotifier) NotifyResult(state notifier.MuoState, result string) error {
+	p.results = append(p.results, result)
+	return nil
+}
@@ -0,0 +1,125 @@
+package upgraders
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	gomock "go.uber.org/mock/gomock"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	ac "github.com/openshift/managed-upgrade-operator/pkg/availabilitychecks"
+	acMocks "github.com/openshift/managed-upgrade-operator/pkg/availabilitychecks/mocks"
+	cvMocks "github.com/openshift/managed-upgrade-operator/pkg/clusterversion/mocks"
+	emMocks "github.com/openshift/managed-upgrade-operator/pkg/eventmanager/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
+	mockScaler "github.com/openshift/managed-upgrade-operator/pkg/scaler/mocks"
+	"github.com/openshift/managed-upgrade-operator/util/mocks"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("Preflight", func() {
+	var (
+		logger logr.Logger
+		// mocks
+		mockKubeClient   *mocks.MockClient
+		mockCtrl         *gomock.Controller
+		mockScalerClient *mockScaler.MockScaler
+		mockCVClient     *cvMocks.MockClusterVersion
+		mockEMClient     *emMocks.MockEventManager
+		mockAC           *acMocks.MockAvailabilityChecker
+
+		upgradeConfig *upgradev1alpha1.UpgradeConfig
+		upgrader      *clusterUpgrader
+	)
+
+	BeforeEach(func() {
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(types.NamespacedName{
+			Name:      "test-upgradeconfig",
+			Namespace: "test-namespace",
+		}).WithPhase(upgradev1alpha1.UpgradePhaseNew).GetUpgradeConfig()
+		upgradeConfig.Spec.CapacityReservation = false
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKubeClient = mocks.NewMockClient(mockCtrl)
+		mockScalerClient = mockScaler.NewMockScaler(mockCtrl)
+		mockCVClient = cvMocks.NewMockClusterVersion(mockCtrl)
+		mockEMClient = emMocks.NewMockEventManager(mockCtrl)
+		mockAC = acMocks.NewMockAvailabilityChecker(mockCtrl)
+		logger = logf.Log.WithName("preflight test logger")
+		upgrader = &clusterUpgrader{
+			client:               mockKubeClient,
+			cvClient:             mockCVClient,
+			notifier:             mockEMClient,
+			config:               buildTestUpgraderConfig(90, 30, 8, 120, 30),
+			scaler:               mockScalerClient,
+			availabilityCheckers: []ac.AvailabilityChecker{mockAC},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When running the preflight checks", func() {
+		It("reports the result of each check", func() {
+			fakeErr := fmt.Errorf("dependency unavailable")
+			gomock.InOrder(
+				mockCVClient.EXPECT().HasUpgradeCommenced(gomock.Any()).Return(true, nil),
+				mockCVClient.EXPECT().HasUpgradeCommenced(gomock.Any()).Return(true, nil),
+				mockCVClient.EXPECT().HasUpgradeCommenced(gomock.Any()).Return(false, nil),
+				mockAC.EXPECT().AvailabilityCheck().Return(fakeErr),
+			)
+			checks, err := upgrader.Preflight(context.TODO(), upgradeConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checks).To(Equal([]upgradev1alpha1.PreflightCheck{
+				{Name: string(upgradev1alpha1.IsClusterUpgradable), Result: upgradev1alpha1.PreflightCheckPassed},
+				{Name: string(upgradev1alpha1.UpgradePreHealthCheck), Result: upgradev1alpha1.PreflightCheckPassed},
+				{Name: string(upgradev1alpha1.ExtDepAvailabilityCheck), Result: upgradev1alpha1.PreflightCheckFailed, Message: fakeErr.Error()},
+				{Name: upgradev1alpha1.PreflightCheckCanScale, Result: upgradev1alpha1.PreflightCheckSkipped, Message: "Capacity reservation is not requested"},
+			}))
+		})
+
+		It("probes whether capacity can be reserved if requested", func() {
+			upgradeConfig.Spec.CapacityReservation = true
+			gomock.InOrder(
+				mockCVClient.EXPECT().HasUpgradeCommenced(gomock.Any()).Return(true, nil).Times(3),
+				mockScalerClient.EXPECT().CanScale(gomock.Any(), gomock.Any()).Return(false, nil),
+			)
+			checks, err := upgrader.Preflight(context.TODO(), upgradeConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checks[3].Name).To(Equal(upgradev1alpha1.PreflightCheckCanScale))
+			Expect(checks[3].Result).To(Equal(upgradev1alpha1.PreflightCheckFailed))
+		})
+
+		It("does not send notifications", func() {
+			gomock.InOrder(
+				mockCVClient.EXPECT().HasUpgradeCommenced(gomock.Any()).Return(true, nil).Times(3),
+			)
+			mockEMClient.EXPECT().NotifyResult(gomock.Any(), gomock.Any()).Times(0)
+			_, err := upgrader.Preflight(context.TODO(), upgradeConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(upgrader.notifier).To(Equal(mockEMClient))
+		})
+	})
+
+	Context("When building the result of a preflight check", func() {
+		It("uses the last notified result as the message of a failed check", func() {
+			recorder := &preflightNotifier{}
+			Expect(recorder.NotifyResult(notifier.MuoStatePreHealthCheckSL, "CriticalAlertsHealthcheckFailed")).To(Succeed())
+			check := preflightCheck("ClusterHealthyBeforeUpgrade", false, nil, recorder.results)
+			Expect(check.Result).To(Equal(upgradev1alpha1.PreflightCheckFailed))
+			Expect(check.Message).To(Equal("CriticalAlertsHealthcheckFailed"))
+		})
+
+		It("ignores notified results of a passing check", func() {
+			check := preflightCheck("ClusterHealthyBeforeUpgrade", true, nil, []string{"NodeUnschedulableHealthcheckFailed"})
+			Expect(check.Result).To(Equal(upgradev1alpha1.PreflightCheckPassed))
+			Expect(check.Message).To(BeEmpty())
+		})
+	})
+})
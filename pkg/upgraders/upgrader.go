@@ -2,8 +2,11 @@ package upgraders
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
@@ -15,10 +18,18 @@ import (
 	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
 	"github.com/openshift/managed-upgrade-operator/pkg/maintenance"
 	"github.com/openshift/managed-upgrade-operator/pkg/metrics"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
 	"github.com/openshift/managed-upgrade-operator/pkg/scaler"
 	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
 )
 
+const (
+	// UpgradeFailedReason is the reason of the Event recorded when an upgrade fails
+	UpgradeFailedReason = "UpgradeFailed"
+	// UpgradeStepSkippedReason is the reason of the Event recorded when an upgrade step is skipped
+	UpgradeStepSkippedReason = "UpgradeStepSkipped"
+)
+
 type clusterUpgrader struct {
 	// Ordered list of steps to carry out a cluster upgrade
 	steps []upgradesteps.UpgradeStep
@@ -36,6 +47,9 @@ type clusterUpgrader struct {
 	// EventManager client used for publishing upgrade events to a recipient
 	notifier eventmanager.EventManager
 
+	// Recorder used for recording Kubernetes Events about the upgrade
+	recorder record.EventRecorder
+
 	// Scaler used for performing upgrade-related capacity scaling
 	scaler scaler.Scaler
 
@@ -63,10 +77,100 @@ type clusterUpgrader struct {
 // runSteps runs the upgrader's upgrade steps and returns the last-executed
 // upgrade phase and any associated error
 func (c *clusterUpgrader) runSteps(ctx context.Context, logger logr.Logger, s []upgradesteps.UpgradeStep) (upgradev1alpha1.UpgradePhase, error) {
-	phase, err := upgradesteps.Run(ctx, c.upgradeConfig, logger, s)
+	err := c.syncWorkerPoolPause(logger)
+	if err != nil {
+		return upgradev1alpha1.UpgradePhaseUpgrading, err
+	}
+	phase, err := upgradesteps.Run(ctx, c.upgradeConfig, logger, s, c)
+	if phase == upgradev1alpha1.UpgradePhaseFailed {
+		phase, err = performUpgradeFailure(c.client, c.metrics, c.scaler, c.notifier, c.machinery, c.upgradeConfig, logger)
+		// The upgrade failure is retried until it has been carried out, the event is only recorded once it has
+		if phase == upgradev1alpha1.UpgradePhaseFailed {
+			c.recordEvent(corev1.EventTypeWarning, UpgradeFailedReason, fmt.Sprintf("Upgrade to version %s has failed", c.upgradeConfig.Spec.Desired.Version))
+		}
+	}
 	return phase, err
 }
 
+// withStepTimeouts applies the configured maximum duration and timeout policy
+// to each of the upgrade steps
+func withStepTimeouts(steps []upgradesteps.UpgradeStep, cfg *upgraderConfig) []upgradesteps.UpgradeStep {
+	for i, step := range steps {
+		timeout, policy := cfg.GetStepTimeout(step.String())
+		steps[i] = upgradesteps.WithTimeout(step, timeout, policy)
+	}
+	return steps
+}
+
+// StepSkipped flags and notifies that an upgrade step is being skipped as it
+// has exceeded its maximum duration
+func (c *clusterUpgrader) StepSkipped(step upgradesteps.UpgradeStep, logger logr.Logger) error {
+	c.metrics.UpdateMetricUpgradeStepTimeout(c.upgradeConfig.Name, step.String())
+	c.recordEvent(corev1.EventTypeWarning, UpgradeStepSkippedReason, fmt.Sprintf("Upgrade step %s has been skipped as it exceeded its maximum duration", step.String()))
+	return c.notifier.Notify(notifier.MuoStateSkipped)
+}
+
+// StepOverdue flags that an upgrade step has exceeded its maximum duration
+// and is still being retried
+func (c *clusterUpgrader) StepOverdue(step upgradesteps.UpgradeStep, logger logr.Logger) {
+	c.metrics.UpdateMetricUpgradeStepTimeout(c.upgradeConfig.Name, step.String())
+}
+
+// recordEvent records a Kubernetes Event about the upgrade on the UpgradeConfig
+func (c *clusterUpgrader) recordEvent(eventType string, reason string, message string) {
+	if c.recorder == nil || c.upgradeConfig == nil {
+		return
+	}
+	c.recorder.Event(c.upgradeConfig, eventType, reason, message)
+}
+
+// syncWorkerPoolPause pauses each worker MachineConfigPool while the upgrade is paused,
+// or while the pool is held for the canary rollout or the pool upgrade order, and
+// unpauses it again otherwise. The maxUnavailable of a pool is restored as it is paused.
+// Pools which are already in the desired state are left untouched.
+func (c *clusterUpgrader) syncWorkerPoolPause(logger logr.Logger) error {
+	upgradingResult, err := c.machinery.IsWorkerPoolsUpgrading(c.client)
+	if err != nil {
+		return err
+	}
+	for _, pool := range upgradingResult.Pools {
+		held, reason := c.isPoolHeld(pool.Name, upgradingResult.Pools)
+		switch {
+		case held && !pool.Paused:
+			logger.Info(fmt.Sprintf("%s, pausing MachineConfigPool %s", reason, pool.Name))
+			// The maxUnavailable is restored first, as a paused pool is no longer tuned
+			err = c.machinery.RestoreMachineConfigPoolMaxUnavailable(c.client, pool.Name)
+			if err == nil {
+				err = c.machinery.PauseMachineConfigPool(c.client, pool.Name)
+			}
+		case !held && pool.PausedByOperator:
+			logger.Info(fmt.Sprintf("Unpausing MachineConfigPool %s", pool.Name))
+			err = c.machinery.UnpauseMachineConfigPool(c.client, pool.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPoolHeld returns whether the worker MachineConfigPool must be held paused, and why
+func (c *clusterUpgrader) isPoolHeld(name string, pools []machinery.PoolUpgradingResult) (bool, string) {
+	if c.upgradeConfig.IsPaused() {
+		return true, "Upgrade is paused"
+	}
+	if c.workersHeldForSoak() {
+		return true, "Control plane is soaking"
+	}
+	if name == machinery.WorkerPool && c.workersHeldForCanary() {
+		return true, "Canary workers have not been released"
+	}
+	if c.heldByPoolOrder(name, pools) {
+		return true, "Preceding MachineConfigPools have not been upgraded"
+	}
+	return false, ""
+}
+
 // UpgradeCluster performs the upgrade of the cluster and returns an indication of the
 // last-executed upgrade phase and any error associated with the phase execution.
 func (c *clusterUpgrader) UpgradeCluster(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger) (upgradev1alpha1.UpgradePhase, error) {
@@ -0,0 +1,178 @@
+package upgraders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	cvMocks "github.com/openshift/managed-upgrade-operator/pkg/clusterversion/mocks"
+	emMocks "github.com/openshift/managed-upgrade-operator/pkg/eventmanager/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	mockMachinery "github.com/openshift/managed-upgrade-operator/pkg/machinery/mocks"
+	mockMetrics "github.com/openshift/managed-upgrade-operator/pkg/metrics/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
+	mockScaler "github.com/openshift/managed-upgrade-operator/pkg/scaler/mocks"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradesteps"
+	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
+)
+
+var _ = Describe("ClusterUpgrader", func() {
+	var (
+		logger            logr.Logger
+		mockCtrl          *gomock.Controller
+		mockMetricsClient *mockMetrics.MockMetrics
+		mockEMClient      *emMocks.MockEventManager
+		recorder          *record.FakeRecorder
+		upgradeConfig     *upgradev1alpha1.UpgradeConfig
+		upgrader          *clusterUpgrader
+	)
+
+	BeforeEach(func() {
+		upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(types.NamespacedName{Name: "test-upgradeconfig", Namespace: "test-namespace"}).GetUpgradeConfig()
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockMetricsClient = mockMetrics.NewMockMetrics(mockCtrl)
+		mockEMClient = emMocks.NewMockEventManager(mockCtrl)
+		recorder = record.NewFakeRecorder(10)
+		logger = logf.Log.WithName("cluster upgrader test logger")
+		upgrader = &clusterUpgrader{
+			metrics:       mockMetricsClient,
+			notifier:      mockEMClient,
+			recorder:      recorder,
+			upgradeConfig: upgradeConfig,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When an upgrade step is skipped", func() {
+		It("records an Event on the UpgradeConfig", func() {
+			step := upgradesteps.Action(string(upgradev1alpha1.ControlPlaneSoak), func(context.Context, logr.Logger) (bool, error) {
+				return true, nil
+			})
+			gomock.InOrder(
+				mockMetricsClient.EXPECT().UpdateMetricUpgradeStepTimeout(upgradeConfig.Name, step.String()),
+				mockEMClient.EXPECT().Notify(notifier.MuoStateSkipped),
+			)
+			err := upgrader.StepSkipped(step, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(Equal("Warning " + UpgradeStepSkippedReason + " Upgrade step " + step.String() + " has been skipped as it exceeded its maximum duration"))
+		})
+	})
+	Context("When the upgrade has not commenced", func() {
+		var (
+			mockCVClient *cvMocks.MockClusterVersion
+			cfg          *upgraderConfig
+		)
+
+		BeforeEach(func() {
+			mockCVClient = cvMocks.NewMockClusterVersion(mockCtrl)
+			cfg = &upgraderConfig{UpgradeWindow: upgradeWindow{TimeOut: 60}}
+			upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			history.StartTime = &metav1.Time{Time: time.Now().Add(-90 * time.Minute)}
+			upgradeConfig.Status.History.SetHistory(*history)
+			mockCVClient.EXPECT().HasUpgradeCommenced(gomock.Any()).Return(false, nil).AnyTimes()
+		})
+
+		It("fails the upgrade once its window has passed", func() {
+			Expect(shouldFailUpgrade(mockCVClient, cfg, upgradeConfig)).To(BeTrue())
+		})
+
+		It("does not fail the upgrade while it is paused", func() {
+			upgradeConfig.Spec.Paused = true
+			Expect(shouldFailUpgrade(mockCVClient, cfg, upgradeConfig)).To(BeFalse())
+		})
+
+		It("does not count the time spent paused towards its window once resumed", func() {
+			// The upgrade started 90 minutes ago and was paused for 75 minutes of its 60 minute window
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			history.PausedTime = &metav1.Time{Time: time.Now().Add(-75 * time.Minute)}
+			upgradeConfig.Status.History.SetHistory(*history)
+			Expect(shouldFailUpgrade(mockCVClient, cfg, upgradeConfig)).To(BeFalse())
+
+			history.PausedTime = nil
+			history.PausedDuration = &metav1.Duration{Duration: 75 * time.Minute}
+			upgradeConfig.Status.History.SetHistory(*history)
+			Expect(shouldFailUpgrade(mockCVClient, cfg, upgradeConfig)).To(BeFalse())
+		})
+	})
+
+	Context("When the upgrade fails", func() {
+		It("restores the maxUnavailable of the worker pools", func() {
+			mockMachineryClient := mockMachinery.NewMockMachinery(mockCtrl)
+			mockScalerClient := mockScaler.NewMockScaler(mockCtrl)
+			upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{Pools: []machinery.PoolUpgradingResult{{Name: "infra"}, {Name: machinery.WorkerPool}}}, nil),
+				mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "infra"),
+				mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), machinery.WorkerPool),
+				mockScalerClient.EXPECT().EnsureScaleDownNodes(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil),
+				mockEMClient.EXPECT().Notify(notifier.MuoStateFailed),
+				mockMetricsClient.EXPECT().UpdateMetricUpgradeWindowBreached(upgradeConfig.Name),
+				mockMetricsClient.EXPECT().ResetFailureMetrics(),
+			)
+			phase, err := performUpgradeFailure(nil, mockMetricsClient, mockScalerClient, mockEMClient, mockMachineryClient, upgradeConfig, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseFailed))
+		})
+	})
+
+	Context("When an upgrade step fails the upgrade", func() {
+		var (
+			mockMachineryClient *mockMachinery.MockMachinery
+			mockScalerClient    *mockScaler.MockScaler
+			steps               []upgradesteps.UpgradeStep
+		)
+
+		BeforeEach(func() {
+			mockMachineryClient = mockMachinery.NewMockMachinery(mockCtrl)
+			mockScalerClient = mockScaler.NewMockScaler(mockCtrl)
+			upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			upgrader.upgradeConfig = upgradeConfig
+			upgrader.machinery = mockMachineryClient
+			upgrader.scaler = mockScalerClient
+			steps = []upgradesteps.UpgradeStep{
+				upgradesteps.Action(string(upgradev1alpha1.ControlPlaneSoak), func(context.Context, logr.Logger) (bool, error) {
+					return false, upgradesteps.FailUpgrade(fmt.Errorf("control plane has regressed"))
+				}),
+			}
+			mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{}, nil).AnyTimes()
+			mockScalerClient.EXPECT().EnsureScaleDownNodes(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+		})
+
+		It("records an Event on the UpgradeConfig once the upgrade has failed", func() {
+			gomock.InOrder(
+				mockEMClient.EXPECT().Notify(notifier.MuoStateFailed),
+				mockMetricsClient.EXPECT().UpdateMetricUpgradeWindowBreached(upgradeConfig.Name),
+				mockMetricsClient.EXPECT().ResetFailureMetrics(),
+			)
+			phase, err := upgrader.runSteps(context.TODO(), logger, steps)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseFailed))
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(Equal("Warning " + UpgradeFailedReason + " Upgrade to version " + upgradeConfig.Spec.Desired.Version + " has failed"))
+		})
+
+		It("does not record an Event while the upgrade failure is retried", func() {
+			mockEMClient.EXPECT().Notify(notifier.MuoStateFailed).Return(fmt.Errorf("notification failed"))
+			phase, err := upgrader.runSteps(context.TODO(), logger, steps)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).NotTo(Equal(upgradev1alpha1.UpgradePhaseFailed))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+})
@@ -3,15 +3,23 @@ package upgraders
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	"github.com/openshift/managed-upgrade-operator/pkg/drain"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
 	"github.com/openshift/managed-upgrade-operator/pkg/notifier"
 )
 
-// AllWorkersUpgraded checks whether all the worker nodes are ready with new config
+// AllWorkersUpgraded checks whether all the worker nodes, in every worker MachineConfigPool,
+// are ready with new config
 func (c *clusterUpgrader) AllWorkersUpgraded(ctx context.Context, logger logr.Logger) (bool, error) {
-	upgradingResult, errUpgrade := c.machinery.IsUpgrading(c.client, "worker")
+	upgradingResult, errUpgrade := c.machinery.IsWorkerPoolsUpgrading(c.client)
 	if errUpgrade != nil {
 		return false, errUpgrade
 	}
@@ -21,8 +29,16 @@ func (c *clusterUpgrader) AllWorkersUpgraded(ctx context.Context, logger logr.Lo
 		return false, errSilence
 	}
 
+	c.recordNodeDrains(ctx, logger)
+
 	if upgradingResult.IsUpgrading {
+		c.tuneWorkerMaxUnavailable(upgradingResult.Pools, logger)
 		logger.Info(fmt.Sprintf("not all workers are upgraded, upgraded: %v, total: %v", upgradingResult.UpdatedCount, upgradingResult.MachineCount))
+		for _, pool := range upgradingResult.Pools {
+			if pool.IsUpgrading {
+				logger.Info(fmt.Sprintf("MachineConfigPool %s is upgrading, upgraded: %v, total: %v", pool.Name, pool.UpdatedCount, pool.MachineCount))
+			}
+		}
 		if !silenceActive {
 			logger.Info("Workers upgrading and no maintenance window active. Setting worker upgrade timeout metric.")
 			c.metrics.UpdateMetricUpgradeWorkerTimeout(c.upgradeConfig.Name, c.upgradeConfig.Spec.Desired.Version)
@@ -33,6 +49,8 @@ func (c *clusterUpgrader) AllWorkersUpgraded(ctx context.Context, logger logr.Lo
 		return false, nil
 	}
 
+	restoreWorkerMaxUnavailable(c.client, c.machinery, upgradingResult.Pools, logger)
+
 	err := c.notifier.Notify(notifier.MuoStateWorkerPlaneUpgradeFinishedSL)
 	if err != nil {
 		logger.Error(err, "failed to notify worker plane upgrade completion")
@@ -44,3 +62,109 @@ func (c *clusterUpgrader) AllWorkersUpgraded(ctx context.Context, logger logr.Lo
 	c.metrics.ResetMetricUpgradeWorkerTimeout(c.upgradeConfig.Name, c.upgradeConfig.Spec.Desired.Version)
 	return true, nil
 }
+
+// tuneWorkerMaxUnavailable sets the maxUnavailable of each worker MachineConfigPool which is upgrading to
+// the number of its nodes which may be unavailable at once, within the configured bounds. The spare capacity
+// of the worker nodes is shared evenly by the pools upgrading at once. Paused pools are left untouched. A
+// failure to do so is logged rather than holding up the upgrade.
+func (c *clusterUpgrader) tuneWorkerMaxUnavailable(pools []machinery.PoolUpgradingResult, logger logr.Logger) {
+	if !c.config.MachineConfigPools.MaxUnavailable.IsEnabled() {
+		return
+	}
+	upgrading := []machinery.PoolUpgradingResult{}
+	for _, pool := range pools {
+		if pool.IsUpgrading && !pool.Paused {
+			upgrading = append(upgrading, pool)
+		}
+	}
+	if len(upgrading) == 0 {
+		return
+	}
+	capacity, err := drain.GetWorkerCapacity(c.client)
+	if err != nil {
+		logger.Error(err, "failed to determine the spare capacity of the worker nodes")
+		return
+	}
+	for _, pool := range upgrading {
+		maxUnavailable := c.config.MachineConfigPools.MaxUnavailable.Bound(capacity.AllowedPoolUnavailable(pool.Name, len(upgrading)))
+		logger.Info(fmt.Sprintf("Worker nodes unavailable: %v, spare capacity for %v more, setting MachineConfigPool %s maxUnavailable to %v",
+			capacity.Unavailable, capacity.Spare, pool.Name, maxUnavailable))
+		err = c.machinery.SetMachineConfigPoolMaxUnavailable(c.client, pool.Name, maxUnavailable)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("failed to set the maxUnavailable of MachineConfigPool %s", pool.Name))
+		}
+	}
+}
+
+// restoreWorkerMaxUnavailable restores the maxUnavailable of each worker MachineConfigPool tuned while it
+// upgraded. A failure to do so is logged rather than holding up the upgrade.
+func restoreWorkerMaxUnavailable(c client.Client, m machinery.Machinery, pools []machinery.PoolUpgradingResult, logger logr.Logger) {
+	for _, pool := range pools {
+		err := m.RestoreMachineConfigPoolMaxUnavailable(c, pool.Name)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("failed to restore the maxUnavailable of MachineConfigPool %s", pool.Name))
+		}
+	}
+}
+
+// recordNodeDrains summarises in the upgrade history the drain status recorded on each node
+// drained during the upgrade. A failure to do so is logged rather than holding up the upgrade.
+func (c *clusterUpgrader) recordNodeDrains(ctx context.Context, logger logr.Logger) {
+	version := c.upgradeConfig.Spec.Desired.Version
+	history := c.upgradeConfig.Status.History.GetHistory(version)
+	if history == nil {
+		return
+	}
+	nodes := &corev1.NodeList{}
+	if err := c.client.List(ctx, nodes); err != nil {
+		logger.Error(err, "failed to list nodes to record their drain status")
+		return
+	}
+
+	summaries := []upgradev1alpha1.NodeDrainSummary{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		status, err := drain.GetNodeDrainStatus(node)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("failed to read the drain status of node %s", node.Name))
+			continue
+		}
+		if status == nil || status.Version != version {
+			continue
+		}
+		summaries = append(summaries, drain.SummarizeNodeDrain(node.Name, status))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Node < summaries[j].Node
+	})
+	history.NodeDrains = summaries
+	c.upgradeConfig.Status.History.SetHistory(*history)
+}
+
+// heldByPoolOrder returns whether the worker MachineConfigPool must be held paused
+// until the MachineConfigPools preceding it in the configured order have upgraded.
+// Pools are held from when the upgrade has commenced, and are released in turn once
+// the control plane has upgraded.
+func (c *clusterUpgrader) heldByPoolOrder(name string, pools []machinery.PoolUpgradingResult) bool {
+	// The canary pool is always upgraded first
+	if c.config.Canary.IsEnabled() && name == c.config.Canary.Pool {
+		return false
+	}
+	position := c.config.MachineConfigPools.GetPosition(name)
+	if position == 0 {
+		return false
+	}
+	history := c.upgradeConfig.Status.History.GetHistory(c.upgradeConfig.Spec.Desired.Version)
+	if history == nil || history.Conditions.GetCondition(upgradev1alpha1.CommenceUpgrade) == nil {
+		return false
+	}
+	if !history.Conditions.IsTrueFor(upgradev1alpha1.ControlPlaneUpgraded) {
+		return true
+	}
+	for _, pool := range pools {
+		if c.config.MachineConfigPools.GetPosition(pool.Name) < position && pool.IsUpgrading {
+			return true
+		}
+	}
+	return false
+}
@@ -8,7 +8,11 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/go-logr/logr"
+	machineconfigv1 "github.com/openshift/api/machineconfiguration/v1"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -102,7 +106,7 @@ var _ = Describe("WorkerStep", func() {
 			It("Should return error", func() {
 				fakeError := fmt.Errorf("fake upgrading result error")
 				gomock.InOrder(
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, fakeError),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, fakeError),
 				)
 				result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
 				Expect(err).To(HaveOccurred())
@@ -113,7 +117,7 @@ var _ = Describe("WorkerStep", func() {
 			It("Should return error", func() {
 				fakeError := fmt.Errorf("fake cannot fetch maintenance window error")
 				gomock.InOrder(
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockMaintClient.EXPECT().IsActive().Return(false, fakeError),
 				)
 				result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
@@ -124,8 +128,10 @@ var _ = Describe("WorkerStep", func() {
 		Context("When all workers are upgraded", func() {
 			It("Indicates that all workers are upgraded", func() {
 				gomock.InOrder(
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: false}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{Pools: []machinery.PoolUpgradingResult{{Name: "infra"}, {Name: machinery.WorkerPool}}}, nil),
 					mockMaintClient.EXPECT().IsActive(),
+					mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "infra"),
+					mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), machinery.WorkerPool),
 					mockEMClient.EXPECT().Notify(gomock.Any()),
 					mockCVClient.EXPECT().GetClusterId(),
 					mockMetricsClient.EXPECT().UpdateMetricWorkernodeUpgradeCompletedTimestamp(gomock.Any(), upgradeConfig.Name, upgradeConfig.Spec.Desired.Version, gomock.Any()),
@@ -139,7 +145,7 @@ var _ = Describe("WorkerStep", func() {
 		Context("When the workers are upgrading and the silence is active", func() {
 			It("Should reset the upgrade worker timeout metric", func() {
 				gomock.InOrder(
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockMaintClient.EXPECT().IsActive().Return(true, nil),
 					mockMetricsClient.EXPECT().ResetMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
 				)
@@ -151,7 +157,7 @@ var _ = Describe("WorkerStep", func() {
 		Context("When all workers are not upgraded", func() {
 			It("Indicates that all workers are not upgraded", func() {
 				gomock.InOrder(
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: true}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
 					mockMaintClient.EXPECT().IsActive(),
 					mockMetricsClient.EXPECT().UpdateMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
 				)
@@ -164,8 +170,10 @@ var _ = Describe("WorkerStep", func() {
 			It("Should return error", func() {
 				fakeError := fmt.Errorf("fake notification error")
 				gomock.InOrder(
-					mockMachineryClient.EXPECT().IsUpgrading(gomock.Any(), "worker").Return(&machinery.UpgradingResult{IsUpgrading: false}, nil),
+					mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{Pools: []machinery.PoolUpgradingResult{{Name: "infra"}, {Name: machinery.WorkerPool}}}, nil),
 					mockMaintClient.EXPECT().IsActive(),
+					mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), "infra"),
+					mockMachineryClient.EXPECT().RestoreMachineConfigPoolMaxUnavailable(gomock.Any(), machinery.WorkerPool),
 					mockEMClient.EXPECT().Notify(gomock.Any()).Return(fakeError),
 				)
 				result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
@@ -175,4 +183,186 @@ var _ = Describe("WorkerStep", func() {
 		})
 	})
 
+	Context("When tuning the maxUnavailable of the worker MachineConfigPools", func() {
+		var upgradingResult *machinery.WorkerPoolsUpgradingResult
+
+		BeforeEach(func() {
+			config.MachineConfigPools.MaxUnavailable = maxUnavailableConfig{Min: 1, Max: 2}
+			upgradingResult = &machinery.WorkerPoolsUpgradingResult{
+				UpgradingResult: machinery.UpgradingResult{IsUpgrading: true},
+				Pools: []machinery.PoolUpgradingResult{
+					{Name: "infra", UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}},
+					{Name: machinery.WorkerPool, UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}},
+				},
+			}
+		})
+		It("sets the maxUnavailable of each upgrading pool from the spare capacity of the worker nodes within the bounds", func() {
+			config.MachineConfigPools.MaxUnavailable.Max = 5
+			pools := machineconfigv1.MachineConfigPoolList{}
+			for _, name := range []string{"infra", machinery.WorkerPool} {
+				pools.Items = append(pools.Items, machineconfigv1.MachineConfigPool{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: machineconfigv1.MachineConfigPoolSpec{
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"node-role.kubernetes.io/" + name: ""}},
+					},
+				})
+			}
+			nodes := corev1.NodeList{}
+			for i, name := range []string{"n1", "n2", "n3", "n4", "n5", "n6", "n7"} {
+				labels := map[string]string{"node-role.kubernetes.io/worker": ""}
+				if i == 0 {
+					labels["node-role.kubernetes.io/infra"] = ""
+				}
+				nodes.Items = append(nodes.Items, corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+					Spec:       corev1.NodeSpec{Unschedulable: i == 1},
+					Status: corev1.NodeStatus{
+						Allocatable: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("4"),
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+						Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+					},
+				})
+			}
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMaintClient.EXPECT().IsActive().Return(true, nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, pools),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, nodes),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()),
+				// Spare capacity for 6 nodes is shared by the 2 pools, the worker pool has an unavailable node
+				mockMachineryClient.EXPECT().SetMachineConfigPoolMaxUnavailable(gomock.Any(), "infra", 3),
+				mockMachineryClient.EXPECT().SetMachineConfigPoolMaxUnavailable(gomock.Any(), machinery.WorkerPool, 4),
+				mockMetricsClient.EXPECT().ResetMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
+			)
+			result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+		It("does not tune the pools which are paused", func() {
+			upgradingResult.Pools[0].Paused = true
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMaintClient.EXPECT().IsActive().Return(true, nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()),
+				mockMachineryClient.EXPECT().SetMachineConfigPoolMaxUnavailable(gomock.Any(), machinery.WorkerPool, 1),
+				mockMetricsClient.EXPECT().ResetMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
+			)
+			result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+		It("does not hold up the upgrade if the spare capacity cannot be determined", func() {
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(upgradingResult, nil),
+				mockMaintClient.EXPECT().IsActive().Return(true, nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+				mockMetricsClient.EXPECT().ResetMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
+			)
+			result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+	})
+
+	Context("When recording the drain status of the worker nodes", func() {
+		BeforeEach(func() {
+			upgradeConfig = testStructs.NewUpgradeConfigBuilder().WithNamespacedName(upgradeConfigName).WithPhase(upgradev1alpha1.UpgradePhaseUpgrading).GetUpgradeConfig()
+			upgrader.upgradeConfig = upgradeConfig
+		})
+		It("summarises the drain status of the nodes drained during the upgrade", func() {
+			drained := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "drained", Annotations: map[string]string{
+				drain.NodeDrainStatusAnnotation: `{"version":"` + upgradeConfig.Spec.Desired.Version + `","failed":true,"blockingPDBs":["ns/pdb"],` +
+					`"strategies":[{"name":"EVICT","firstExecutionTime":"2024-06-20T00:00:00Z","lastExecutionTime":"2024-06-20T00:01:00Z","podsEvicted":["ns/a","ns/b"]},` +
+					`{"name":"PDB-DELETE","firstExecutionTime":"2024-06-20T01:00:00Z","lastExecutionTime":"2024-06-20T01:00:00Z","podsDeleted":["ns/c"]}]}`,
+			}}}
+			earlier := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "earlier", Annotations: map[string]string{
+				drain.NodeDrainStatusAnnotation: `{"version":"4.0.0"}`,
+			}}}
+			undrained := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "undrained"}}
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
+				mockMaintClient.EXPECT().IsActive().Return(true, nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, corev1.NodeList{Items: []corev1.Node{undrained, earlier, drained}}),
+				mockMetricsClient.EXPECT().ResetMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
+			)
+			result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			Expect(history.NodeDrains).To(Equal([]upgradev1alpha1.NodeDrainSummary{
+				{
+					Node:         "drained",
+					Strategies:   []string{"EVICT", "PDB-DELETE"},
+					PodsEvicted:  2,
+					PodsDeleted:  1,
+					BlockingPDBs: []string{"ns/pdb"},
+					Failed:       true,
+				},
+			}))
+		})
+		It("does not hold up the upgrade if the nodes cannot be listed", func() {
+			gomock.InOrder(
+				mockMachineryClient.EXPECT().IsWorkerPoolsUpgrading(gomock.Any()).Return(&machinery.WorkerPoolsUpgradingResult{UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}}, nil),
+				mockMaintClient.EXPECT().IsActive().Return(true, nil),
+				mockKubeClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+				mockMetricsClient.EXPECT().ResetMetricUpgradeWorkerTimeout(upgradeConfig.Name, upgradeConfig.Spec.Desired.Version),
+			)
+			result, err := upgrader.AllWorkersUpgraded(context.TODO(), logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeFalse())
+		})
+	})
+
+	Context("When upgrading the worker MachineConfigPools in order", func() {
+		var pools []machinery.PoolUpgradingResult
+
+		setCondition := func(conditionType upgradev1alpha1.UpgradeConditionType, status corev1.ConditionStatus) {
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			history.Conditions.SetCondition(upgradev1alpha1.UpgradeCondition{Type: conditionType, Status: status})
+			upgradeConfig.Status.History.SetHistory(*history)
+		}
+
+		BeforeEach(func() {
+			config.MachineConfigPools.Order = []string{"infra", "worker"}
+			upgradeConfig.Status.History = []upgradev1alpha1.UpgradeHistory{{Version: upgradeConfig.Spec.Desired.Version, Phase: upgradev1alpha1.UpgradePhaseUpgrading}}
+			pools = []machinery.PoolUpgradingResult{
+				{Name: "gpu", UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}},
+				{Name: "infra", UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}},
+				{Name: "worker", UpgradingResult: machinery.UpgradingResult{IsUpgrading: true}},
+			}
+		})
+
+		It("does not hold any pool before the upgrade has commenced", func() {
+			for _, pool := range pools {
+				Expect(upgrader.heldByPoolOrder(pool.Name, pools)).To(BeFalse())
+			}
+		})
+
+		It("holds all but the first pool until the control plane has upgraded", func() {
+			setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue)
+			setCondition(upgradev1alpha1.ControlPlaneUpgraded, corev1.ConditionFalse)
+			pools[1].IsUpgrading = false
+			Expect(upgrader.heldByPoolOrder("infra", pools)).To(BeFalse())
+			Expect(upgrader.heldByPoolOrder("worker", pools)).To(BeTrue())
+			Expect(upgrader.heldByPoolOrder("gpu", pools)).To(BeTrue())
+		})
+
+		It("releases each pool once the preceding pools have upgraded", func() {
+			setCondition(upgradev1alpha1.CommenceUpgrade, corev1.ConditionTrue)
+			setCondition(upgradev1alpha1.ControlPlaneUpgraded, corev1.ConditionTrue)
+			Expect(upgrader.heldByPoolOrder("worker", pools)).To(BeTrue())
+			Expect(upgrader.heldByPoolOrder("gpu", pools)).To(BeTrue())
+
+			pools[1].IsUpgrading = false
+			Expect(upgrader.heldByPoolOrder("worker", pools)).To(BeFalse())
+			Expect(upgrader.heldByPoolOrder("gpu", pools)).To(BeTrue())
+
+			pools[2].IsUpgrading = false
+			Expect(upgrader.heldByPoolOrder("gpu", pools)).To(BeFalse())
+		})
+	})
 })
@@ -2,6 +2,7 @@ package upgradesteps
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,28 +19,105 @@ type UpgradeStep interface {
 	String() string
 }
 
+// UpgradeFailedError is returned by an upgrade step which has determined that
+// the upgrade cannot succeed, and fails the upgrade rather than being retried.
+type UpgradeFailedError struct {
+	Err error
+}
+
+func (e *UpgradeFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UpgradeFailedError) Unwrap() error {
+	return e.Err
+}
+
+// FailUpgrade returns an error which fails the upgrade when returned by an upgrade step
+func FailUpgrade(err error) error {
+	return &UpgradeFailedError{Err: err}
+}
+
 // Run executes the provided steps in order until one fails or all steps
 // are completed. The function returns an indication of the last-completed
 // UpgradePhase any associated error.
-func Run(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger, steps []UpgradeStep) (upgradev1alpha1.UpgradePhase, error) {
+// If the UpgradeConfig is paused, no steps are executed and the current step
+// is marked as paused.
+// Steps which have exceeded their maximum duration are treated according to
+// their TimeoutPolicy, notifying the TimeoutHandler if one is supplied.
+func Run(ctx context.Context, upgradeConfig *upgradev1alpha1.UpgradeConfig, logger logr.Logger, steps []UpgradeStep, handler TimeoutHandler) (upgradev1alpha1.UpgradePhase, error) {
+	if upgradeConfig.IsPaused() {
+		step := currentStep(steps, upgradeConfig)
+		if step != nil {
+			logger.Info(fmt.Sprintf("upgrade is paused, not running step %s", step))
+			setConditionPaused(step, upgradeConfig)
+		}
+		setHistoryPaused(upgradeConfig)
+		return upgradev1alpha1.UpgradePhaseUpgrading, nil
+	}
+	setHistoryResumed(upgradeConfig)
+
 	for _, step := range steps {
+		if isSkipped(step, upgradeConfig) {
+			logger.Info(fmt.Sprintf("step %s was skipped, not running it", step))
+			continue
+		}
 		logger.Info(fmt.Sprintf("running step %s", step))
 		setConditionStart(step, upgradeConfig)
 		result, err := step.run(ctx, logger)
 
+		if err == nil && result {
+			setConditionComplete(step, upgradeConfig)
+			continue
+		}
+
+		var failed *UpgradeFailedError
+		if errors.As(err, &failed) {
+			logger.Info(fmt.Sprintf("%s failed the upgrade: %s", step.String(), failed.Err))
+			setConditionFailed(step, failed.Err.Error(), upgradeConfig)
+			return upgradev1alpha1.UpgradePhaseFailed, nil
+		}
+
+		message := fmt.Sprintf("%s still in progress", step.String())
 		if err != nil {
-			logger.Error(err, fmt.Sprintf("error when %s", step.String()))
-			setConditionInProgress(step, err.Error(), upgradeConfig)
-			return upgradev1alpha1.UpgradePhaseUpgrading, err
+			message = err.Error()
+		}
+
+		if timedOut, timeout, policy := hasTimedOut(step, upgradeConfig); timedOut {
+			switch policy {
+			case TimeoutPolicyFail:
+				logger.Info(fmt.Sprintf("%s did not complete within %s, failing upgrade", step.String(), timeout))
+				setConditionTimedOut(step, fmt.Sprintf("%s did not complete within %s: %s", step.String(), timeout, message), upgradeConfig)
+				return upgradev1alpha1.UpgradePhaseFailed, nil
+			case TimeoutPolicySkip:
+				logger.Info(fmt.Sprintf("%s did not complete within %s, skipping step", step.String(), timeout))
+				if handler != nil {
+					if herr := handler.StepSkipped(step, logger); herr != nil {
+						logger.Error(herr, fmt.Sprintf("failed to skip %s", step.String()))
+						setConditionInProgress(step, herr.Error(), upgradeConfig)
+						return upgradev1alpha1.UpgradePhaseUpgrading, herr
+					}
+				}
+				setConditionSkipped(step, fmt.Sprintf("%s did not complete within %s and was skipped", step.String(), timeout), upgradeConfig)
+				continue
+			case TimeoutPolicyRetry:
+				logger.Info(fmt.Sprintf("%s did not complete within %s, retrying", step.String(), timeout))
+				if handler != nil {
+					handler.StepOverdue(step, logger)
+				}
+				message = fmt.Sprintf("%s (exceeded maximum duration of %s)", message, timeout)
+			}
 		}
 
-		if !result {
-			logger.Info(fmt.Sprintf("%s not done, skip following steps", step.String()))
-			setConditionInProgress(step, fmt.Sprintf("%s still in progress", step.String()), upgradeConfig)
-			return upgradev1alpha1.UpgradePhaseUpgrading, nil
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("error when %s", step.String()))
+			setConditionInProgress(step, message, upgradeConfig)
+			return upgradev1alpha1.UpgradePhaseUpgrading, err
 		}
 
-		setConditionComplete(step, upgradeConfig)
+		logger.Info(fmt.Sprintf("%s not done, skip following steps", step.String()))
+		setConditionInProgress(step, message, upgradeConfig)
+		return upgradev1alpha1.UpgradePhaseUpgrading, nil
 	}
 	return upgradev1alpha1.UpgradePhaseUpgraded, nil
 }
@@ -55,9 +133,35 @@ func newUpgradeCondition(reason, msg string, conditionType upgradev1alpha1.Upgra
 	}
 }
 
+// currentStep returns the first step which has not yet completed, or nil if
+// all steps have completed.
+func currentStep(steps []UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeConfig) UpgradeStep {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return nil
+	}
+	for _, step := range steps {
+		if !history.Conditions.IsTrueFor(upgradev1alpha1.UpgradeConditionType(step.String())) {
+			return step
+		}
+	}
+	return nil
+}
+
+// isSkipped returns whether the step has previously been skipped after exceeding
+// its maximum duration.
+func isSkipped(step UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeConfig) bool {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return false
+	}
+	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
+	return c != nil && c.IsTrue() && c.Reason == upgradev1alpha1.UpgradeConditionReasonSkipped
+}
+
 // setConditionStart adds an UpgradeCondition to the UpgradeConfig indicating
 // that a given step has commenced execution.
-// If the UpgradeCondition already exists, no action is taken.
+// If the UpgradeCondition already exists with a start time, no action is taken.
 func setConditionStart(step UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeConfig) {
 	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
 	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
@@ -70,7 +174,84 @@ func setConditionStart(step UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeC
 		condition.StartTime = &metav1.Time{Time: time.Now()}
 		history.Conditions.SetCondition(*condition)
 		upgradeConfig.Status.History.SetHistory(*history)
+		return
+	}
+	// A step which was paused before it started has no start time yet
+	if c.StartTime == nil {
+		c.StartTime = &metav1.Time{Time: time.Now()}
+		c.PausedTime = nil
+		history.Conditions.SetCondition(*c)
+		upgradeConfig.Status.History.SetHistory(*history)
+		return
+	}
+	// A step resuming from a pause accumulates the time it spent paused
+	if c.PausedTime != nil {
+		c.PausedDuration = &metav1.Duration{Duration: pausedDuration(c, time.Now())}
+		c.PausedTime = nil
+		history.Conditions.SetCondition(*c)
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+}
+
+// setConditionPaused adds or updates an UpgradeCondition in the UpgradeConfig indicating
+// that a given step is paused. The start time of the step is left untouched, and the
+// time the pause began is recorded so that it can be excluded from the step's duration.
+func setConditionPaused(step UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeConfig) {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	message := fmt.Sprintf("%s is paused", step.String())
+	if upgradeConfig.Spec.PausedReason != "" {
+		message = fmt.Sprintf("%s: %s", message, upgradeConfig.Spec.PausedReason)
+	}
+	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
+	if c == nil {
+		c = newUpgradeCondition(upgradev1alpha1.UpgradeConditionReasonPaused, message,
+			upgradev1alpha1.UpgradeConditionType(step.String()),
+			corev1.ConditionFalse)
+	}
+	if c.PausedTime == nil {
+		c.PausedTime = &metav1.Time{Time: time.Now()}
 	}
+	c.Reason = upgradev1alpha1.UpgradeConditionReasonPaused
+	c.Message = message
+	c.Status = corev1.ConditionFalse
+	history.Conditions.SetCondition(*c)
+	upgradeConfig.Status.History.SetHistory(*history)
+}
+
+// setHistoryPaused records the time the upgrade was paused in its history, unless it is already
+// paused.
+func setHistoryPaused(upgradeConfig *upgradev1alpha1.UpgradeConfig) {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	if history == nil || history.PausedTime != nil {
+		return
+	}
+	history.PausedTime = &metav1.Time{Time: time.Now()}
+	upgradeConfig.Status.History.SetHistory(*history)
+}
+
+// setHistoryResumed adds the time the upgrade spent in its last pause to the paused duration in
+// its history, if it was paused.
+func setHistoryResumed(upgradeConfig *upgradev1alpha1.UpgradeConfig) {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	if history == nil || history.PausedTime == nil {
+		return
+	}
+	history.PausedDuration = &metav1.Duration{Duration: UpgradePausedDuration(history, time.Now())}
+	history.PausedTime = nil
+	upgradeConfig.Status.History.SetHistory(*history)
+}
+
+// UpgradePausedDuration returns the total time the upgrade has spent paused since its start time,
+// including any current pause.
+func UpgradePausedDuration(history *upgradev1alpha1.UpgradeHistory, now time.Time) time.Duration {
+	var paused time.Duration
+	if history.PausedDuration != nil {
+		paused = history.PausedDuration.Duration
+	}
+	if history.PausedTime != nil {
+		paused += now.Sub(history.PausedTime.Time)
+	}
+	return paused
 }
 
 // setConditionInProgress adds or updates an UpgradeCondition in the UpgradeConfig indicating
@@ -79,6 +260,7 @@ func setConditionInProgress(step UpgradeStep, message string, upgradeConfig *upg
 	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
 	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
 	if c != nil {
+		c.Reason = fmt.Sprintf("%s not done", step.String())
 		c.Message = message
 		c.Status = corev1.ConditionFalse
 		// Reset completion time because some steps can fail after an earlier success
@@ -88,6 +270,54 @@ func setConditionInProgress(step UpgradeStep, message string, upgradeConfig *upg
 	}
 }
 
+// setConditionTimedOut updates an UpgradeCondition in the UpgradeConfig indicating
+// that a given step has exceeded its maximum duration and failed the upgrade.
+func setConditionTimedOut(step UpgradeStep, message string, upgradeConfig *upgradev1alpha1.UpgradeConfig) {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
+	if c != nil {
+		c.Reason = upgradev1alpha1.UpgradeConditionReasonTimedOut
+		c.Message = message
+		c.Status = corev1.ConditionFalse
+		c.CompleteTime = nil
+		history.Conditions.SetCondition(*c)
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+}
+
+// setConditionFailed updates an UpgradeCondition in the UpgradeConfig indicating
+// that a given step has failed the upgrade.
+func setConditionFailed(step UpgradeStep, message string, upgradeConfig *upgradev1alpha1.UpgradeConfig) {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
+	if c != nil {
+		c.Reason = upgradev1alpha1.UpgradeConditionReasonFailed
+		c.Message = message
+		c.Status = corev1.ConditionFalse
+		c.CompleteTime = nil
+		history.Conditions.SetCondition(*c)
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+}
+
+// setConditionSkipped updates an UpgradeCondition in the UpgradeConfig indicating
+// that a given step has exceeded its maximum duration and has been skipped. A
+// skipped step is treated as completed by the runner.
+func setConditionSkipped(step UpgradeStep, message string, upgradeConfig *upgradev1alpha1.UpgradeConfig) {
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
+	if c != nil {
+		c.Reason = upgradev1alpha1.UpgradeConditionReasonSkipped
+		c.Message = message
+		c.Status = corev1.ConditionTrue
+		if c.CompleteTime == nil {
+			c.CompleteTime = &metav1.Time{Time: time.Now()}
+		}
+		history.Conditions.SetCondition(*c)
+		upgradeConfig.Status.History.SetHistory(*history)
+	}
+}
+
 // setConditionComplete adds or updates an UpgradeCondition in the UpgradeConfig indicating
 // that a given step has completed.
 func setConditionComplete(step UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeConfig) {
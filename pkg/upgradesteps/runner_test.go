@@ -3,8 +3,11 @@ package upgradesteps
 import (
 	"context"
 	"fmt"
+	"time"
+
 	testStructs "github.com/openshift/managed-upgrade-operator/util/mocks/structs"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	. "github.com/onsi/ginkgo"
@@ -55,12 +58,12 @@ var _ = Describe("HealthCheckStep", func() {
 			Action(finalStepName, successfulStep),
 		}
 		It("should return an upgrade completed phase", func() {
-			phase, err := Run(context.TODO(), upgradeConfig, logger, steps)
+			phase, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(err).To(BeNil())
 			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgraded))
 		})
 		It("should have a successful condition for each step", func() {
-			_, err := Run(context.TODO(), upgradeConfig, logger, steps)
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(err).To(BeNil())
 			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
 			Expect(history).ToNot(BeNil())
@@ -84,13 +87,13 @@ var _ = Describe("HealthCheckStep", func() {
 		}
 
 		It("should indicate the upgrade is still ongoing", func() {
-			phase, err := Run(context.TODO(), upgradeConfig, logger, steps)
+			phase, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(err).To(BeNil())
 			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
 		})
 
 		It("should correctly indicate condition states", func() {
-			_, err := Run(context.TODO(), upgradeConfig, logger, steps)
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(err).To(BeNil())
 			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
 			Expect(history).ToNot(BeNil())
@@ -109,6 +112,87 @@ var _ = Describe("HealthCheckStep", func() {
 		})
 	})
 
+	Context("When the upgrade is paused", func() {
+		completedStepName := "step 1"
+		pausedStepName := "step 2"
+		notRunStepName := "step 3"
+		var stepRuns int
+		countingStep := func(ctx context.Context, logger logr.Logger) (bool, error) {
+			stepRuns++
+			return true, nil
+		}
+		steps := []UpgradeStep{
+			Action(completedStepName, countingStep),
+			Action(pausedStepName, countingStep),
+			Action(notRunStepName, countingStep),
+		}
+
+		BeforeEach(func() {
+			stepRuns = 0
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			history.Conditions.SetCondition(upgradev1alpha1.UpgradeCondition{
+				Type:   upgradev1alpha1.UpgradeConditionType(completedStepName),
+				Status: corev1.ConditionTrue,
+			})
+			upgradeConfig.Status.History.SetHistory(*history)
+			upgradeConfig.Spec.Paused = true
+			upgradeConfig.Spec.PausedReason = "incident"
+		})
+
+		It("should not run any steps", func() {
+			phase, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).To(BeNil())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
+			Expect(stepRuns).To(Equal(0))
+		})
+
+		It("should mark the current step as paused", func() {
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).To(BeNil())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			pausedStepCondition := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(pausedStepName))
+			Expect(pausedStepCondition).ToNot(BeNil())
+			Expect(pausedStepCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(pausedStepCondition.Reason).To(Equal(upgradev1alpha1.UpgradeConditionReasonPaused))
+			Expect(pausedStepCondition.Message).To(ContainSubstring("incident"))
+			Expect(pausedStepCondition.StartTime).To(BeNil())
+			Expect(history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(notRunStepName))).To(BeNil())
+		})
+
+		It("should resume from the paused step once unpaused", func() {
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).To(BeNil())
+			upgradeConfig.Spec.Paused = false
+			phase, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).To(BeNil())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgraded))
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			pausedStepCondition := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(pausedStepName))
+			Expect(pausedStepCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(pausedStepCondition.StartTime).ToNot(BeNil())
+		})
+
+		It("should record the time the upgrade spent paused in its history", func() {
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).To(BeNil())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			Expect(history.PausedTime).NotTo(BeNil())
+
+			// The upgrade was paused 45 minutes ago, after an earlier pause of 10 minutes
+			history.PausedTime = &metav1.Time{Time: time.Now().Add(-45 * time.Minute)}
+			history.PausedDuration = &metav1.Duration{Duration: 10 * time.Minute}
+			upgradeConfig.Status.History.SetHistory(*history)
+			Expect(UpgradePausedDuration(history, time.Now())).To(BeNumerically("~", 55*time.Minute, time.Minute))
+
+			upgradeConfig.Spec.Paused = false
+			_, err = Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).To(BeNil())
+			history = upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			Expect(history.PausedTime).To(BeNil())
+			Expect(history.PausedDuration.Duration).To(BeNumerically("~", 55*time.Minute, time.Minute))
+		})
+	})
+
 	Context("When a step has errored", func() {
 		erroredStepName := "step that errored"
 		successfulStepName := "step 1"
@@ -120,15 +204,15 @@ var _ = Describe("HealthCheckStep", func() {
 		}
 
 		It("should indicate the upgrade is still ongoing", func() {
-			phase, _ := Run(context.TODO(), upgradeConfig, logger, steps)
+			phase, _ := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
 		})
 		It("should indicate the error associated with the failed step", func() {
-			_, err := Run(context.TODO(), upgradeConfig, logger, steps)
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(err).To(Equal(err))
 		})
 		It("should correctly indicate condition states", func() {
-			_, err := Run(context.TODO(), upgradeConfig, logger, steps)
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
 			Expect(err).To(Equal(err))
 			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
 			Expect(history).ToNot(BeNil())
@@ -146,4 +230,173 @@ var _ = Describe("HealthCheckStep", func() {
 			Expect(erroredStepCondition.CompleteTime).To(BeNil())
 		})
 	})
+
+	Context("When a step has failed the upgrade", func() {
+		failedStepName := "step that failed"
+		notRunStepName := "step 3"
+		failedStep := func(ctx context.Context, logger logr.Logger) (bool, error) {
+			return false, FailUpgrade(fmt.Errorf("control plane regressed"))
+		}
+		steps := []UpgradeStep{
+			Action("step 1", successfulStep),
+			Action(failedStepName, failedStep),
+			Action(notRunStepName, successfulStep),
+		}
+
+		It("should fail the upgrade and mark the step as failed", func() {
+			phase, err := Run(context.TODO(), upgradeConfig, logger, steps, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseFailed))
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			failedStepCondition := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(failedStepName))
+			Expect(failedStepCondition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(failedStepCondition.Reason).To(Equal(upgradev1alpha1.UpgradeConditionReasonFailed))
+			Expect(failedStepCondition.Message).To(Equal("control plane regressed"))
+			Expect(history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(notRunStepName))).To(BeNil())
+		})
+	})
+
+	Context("When a step has exceeded its maximum duration", func() {
+		timedOutStepName := "step that timed out"
+		successfulStepName := "step 1"
+		followingStepName := "step 3"
+		var handler *testTimeoutHandler
+
+		BeforeEach(func() {
+			handler = &testTimeoutHandler{}
+		})
+
+		stepsWithPolicy := func(policy TimeoutPolicy) []UpgradeStep {
+			return []UpgradeStep{
+				Action(successfulStepName, successfulStep),
+				WithTimeout(Action(timedOutStepName, unsuccessfulStep), 30*time.Minute, policy),
+				Action(followingStepName, successfulStep),
+			}
+		}
+
+		// runTimedOut runs the steps once so the timed out step starts, then
+		// moves its start time beyond its maximum duration and runs them again
+		runTimedOut := func(steps []UpgradeStep) (upgradev1alpha1.UpgradePhase, error) {
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, handler)
+			Expect(err).To(BeNil())
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(timedOutStepName))
+			c.StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			history.Conditions.SetCondition(*c)
+			upgradeConfig.Status.History.SetHistory(*history)
+			return Run(context.TODO(), upgradeConfig, logger, steps, handler)
+		}
+
+		It("should not apply the policy before the maximum duration is exceeded", func() {
+			phase, err := Run(context.TODO(), upgradeConfig, logger, stepsWithPolicy(TimeoutPolicyFail), handler)
+			Expect(err).To(BeNil())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
+			Expect(handler.skipped).To(BeEmpty())
+			Expect(handler.overdue).To(BeEmpty())
+		})
+
+		It("should not count time spent paused towards the maximum duration", func() {
+			steps := stepsWithPolicy(TimeoutPolicyFail)
+			_, err := Run(context.TODO(), upgradeConfig, logger, steps, handler)
+			Expect(err).To(BeNil())
+			upgradeConfig.Spec.Paused = true
+			_, err = Run(context.TODO(), upgradeConfig, logger, steps, handler)
+			Expect(err).To(BeNil())
+
+			// The step started an hour ago but has been paused for the last 45 minutes
+			history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(timedOutStepName))
+			c.StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			c.PausedTime = &metav1.Time{Time: time.Now().Add(-45 * time.Minute)}
+			history.Conditions.SetCondition(*c)
+			upgradeConfig.Status.History.SetHistory(*history)
+
+			upgradeConfig.Spec.Paused = false
+			phase, err := Run(context.TODO(), upgradeConfig, logger, steps, handler)
+			Expect(err).To(BeNil())
+			Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
+			history = upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+			c = history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(timedOutStepName))
+			Expect(c.Reason).NotTo(Equal(upgradev1alpha1.UpgradeConditionReasonTimedOut))
+			Expect(c.PausedTime).To(BeNil())
+			Expect(c.PausedDuration.Duration).To(BeNumerically("~", 45*time.Minute, time.Minute))
+		})
+
+		Context("and its policy is to fail", func() {
+			It("should fail the upgrade and mark the step as timed out", func() {
+				phase, err := runTimedOut(stepsWithPolicy(TimeoutPolicyFail))
+				Expect(err).To(BeNil())
+				Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseFailed))
+				history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+				c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(timedOutStepName))
+				Expect(c.Status).To(Equal(corev1.ConditionFalse))
+				Expect(c.Reason).To(Equal(upgradev1alpha1.UpgradeConditionReasonTimedOut))
+				Expect(history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(followingStepName))).To(BeNil())
+			})
+		})
+
+		Context("and its policy is to skip", func() {
+			It("should notify the handler, skip the step and run the following steps", func() {
+				phase, err := runTimedOut(stepsWithPolicy(TimeoutPolicySkip))
+				Expect(err).To(BeNil())
+				Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgraded))
+				Expect(handler.skipped).To(Equal([]string{timedOutStepName}))
+				history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+				c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(timedOutStepName))
+				Expect(c.Status).To(Equal(corev1.ConditionTrue))
+				Expect(c.Reason).To(Equal(upgradev1alpha1.UpgradeConditionReasonSkipped))
+				Expect(history.Conditions.IsTrueFor(upgradev1alpha1.UpgradeConditionType(followingStepName))).To(BeTrue())
+			})
+			It("should not run the skipped step again", func() {
+				steps := stepsWithPolicy(TimeoutPolicySkip)
+				_, err := runTimedOut(steps)
+				Expect(err).To(BeNil())
+				phase, err := Run(context.TODO(), upgradeConfig, logger, steps, handler)
+				Expect(err).To(BeNil())
+				Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgraded))
+				Expect(handler.skipped).To(HaveLen(1))
+			})
+			It("should not skip the step if the handler fails", func() {
+				handler.err = fmt.Errorf("notification failed")
+				phase, err := runTimedOut(stepsWithPolicy(TimeoutPolicySkip))
+				Expect(err).To(HaveOccurred())
+				Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
+				history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+				Expect(history.Conditions.IsFalseFor(upgradev1alpha1.UpgradeConditionType(timedOutStepName))).To(BeTrue())
+				Expect(history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(followingStepName))).To(BeNil())
+			})
+		})
+
+		Context("and its policy is to retry", func() {
+			It("should flag the step as overdue and keep retrying it", func() {
+				phase, err := runTimedOut(stepsWithPolicy(TimeoutPolicyRetry))
+				Expect(err).To(BeNil())
+				Expect(phase).To(Equal(upgradev1alpha1.UpgradePhaseUpgrading))
+				Expect(handler.overdue).To(Equal([]string{timedOutStepName}))
+				history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+				c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(timedOutStepName))
+				Expect(c.Status).To(Equal(corev1.ConditionFalse))
+				Expect(c.Message).To(ContainSubstring("exceeded maximum duration"))
+				Expect(history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(followingStepName))).To(BeNil())
+			})
+		})
+	})
 })
+
+type testTimeoutHandler struct {
+	skipped []string
+	overdue []string
+	err     error
+}
+
+func (h *testTimeoutHandler) StepSkipped(step UpgradeStep, logger logr.Logger) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.skipped = append(h.skipped, step.String())
+	return nil
+}
+
+func (h *testTimeoutHandler) StepOverdue(step UpgradeStep, logger logr.Logger) {
+	h.overdue = append(h.overdue, step.String())
+}
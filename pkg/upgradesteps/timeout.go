@@ -0,0 +1,97 @@
+package upgradesteps
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+)
+
+// TimeoutPolicy determines how the runner treats a step which has exceeded
+// its maximum duration.
+type TimeoutPolicy string
+
+const (
+	// TimeoutPolicyFail fails the upgrade
+	TimeoutPolicyFail TimeoutPolicy = "Fail"
+	// TimeoutPolicySkip skips the step after notifying the TimeoutHandler and
+	// continues with the following steps
+	TimeoutPolicySkip TimeoutPolicy = "Skip"
+	// TimeoutPolicyRetry keeps retrying the step, notifying the TimeoutHandler
+	// on every attempt that the step is overdue
+	TimeoutPolicyRetry TimeoutPolicy = "Retry"
+)
+
+// IsValid returns whether the TimeoutPolicy is one the runner knows how to enforce
+func (p TimeoutPolicy) IsValid() bool {
+	switch p {
+	case TimeoutPolicyFail, TimeoutPolicySkip, TimeoutPolicyRetry:
+		return true
+	}
+	return false
+}
+
+// TimeoutHandler is notified by the runner of steps which have exceeded
+// their maximum duration.
+type TimeoutHandler interface {
+	// StepSkipped is called before a step with TimeoutPolicySkip is skipped.
+	// If an error is returned, the step is not skipped and will be retried.
+	StepSkipped(step UpgradeStep, logger logr.Logger) error
+	// StepOverdue is called each time a step with TimeoutPolicyRetry is retried
+	// after it has exceeded its maximum duration.
+	StepOverdue(step UpgradeStep, logger logr.Logger)
+}
+
+// timedStep is an UpgradeStep with a maximum duration, after which it is
+// treated according to its TimeoutPolicy.
+type timedStep struct {
+	UpgradeStep
+	timeout time.Duration
+	policy  TimeoutPolicy
+}
+
+// WithTimeout returns an UpgradeStep which behaves as the supplied step, but
+// which is treated according to the policy once it has been running for longer
+// than the timeout. A zero timeout leaves the step unbounded.
+func WithTimeout(step UpgradeStep, timeout time.Duration, policy TimeoutPolicy) UpgradeStep {
+	if timeout <= 0 {
+		return step
+	}
+	return timedStep{UpgradeStep: step, timeout: timeout, policy: policy}
+}
+
+// hasTimedOut returns whether the step has been running for longer than its
+// maximum duration, as measured from the start time of its condition excluding
+// any time spent paused, and if so its maximum duration and TimeoutPolicy.
+func hasTimedOut(step UpgradeStep, upgradeConfig *upgradev1alpha1.UpgradeConfig) (bool, time.Duration, TimeoutPolicy) {
+	ts, ok := step.(timedStep)
+	if !ok {
+		return false, 0, ""
+	}
+	history := upgradeConfig.Status.History.GetHistory(upgradeConfig.Spec.Desired.Version)
+	if history == nil {
+		return false, 0, ""
+	}
+	c := history.Conditions.GetCondition(upgradev1alpha1.UpgradeConditionType(step.String()))
+	if c == nil || c.StartTime == nil {
+		return false, 0, ""
+	}
+	now := time.Now()
+	if now.Sub(c.StartTime.Time)-pausedDuration(c, now) > ts.timeout {
+		return true, ts.timeout, ts.policy
+	}
+	return false, 0, ""
+}
+
+// pausedDuration returns the total time the upgrade has spent paused at the
+// condition, including any current pause.
+func pausedDuration(c *upgradev1alpha1.UpgradeCondition, now time.Time) time.Duration {
+	var paused time.Duration
+	if c.PausedDuration != nil {
+		paused = c.PausedDuration.Duration
+	}
+	if c.PausedTime != nil {
+		paused += now.Sub(c.PausedTime.Time)
+	}
+	return paused
+}
@@ -26,6 +26,7 @@ import (
 	imagereference "github.com/openshift/library-go/pkg/image/reference"
 	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
 	cv "github.com/openshift/managed-upgrade-operator/pkg/clusterversion"
+	"github.com/openshift/managed-upgrade-operator/pkg/scheduler"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -93,6 +94,26 @@ func (v *validator) IsValidUpgradeConfig(c client.Client, uC *upgradev1alpha1.Up
 		}, err
 	}
 
+	// Validate maintenance and freeze windows can be evaluated by the scheduler
+	for _, w := range uC.Spec.MaintenanceWindows {
+		if err := scheduler.ValidateMaintenanceWindow(w); err != nil {
+			return ValidatorResult{
+				IsValid:           false,
+				IsAvailableUpdate: false,
+				Message:           err.Error(),
+			}, err
+		}
+	}
+	for _, f := range uC.Spec.FreezeWindows {
+		if err := scheduler.ValidateFreezeWindow(f); err != nil {
+			return ValidatorResult{
+				IsValid:           false,
+				IsAvailableUpdate: false,
+				Message:           err.Error(),
+			}, err
+		}
+	}
+
 	ucImage := uC.Spec.Desired.Image
 	ucVersion := uC.Spec.Desired.Version
 	ucChannel := uC.Spec.Desired.Channel
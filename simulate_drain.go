@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	upgradev1alpha1 "github.com/openshift/managed-upgrade-operator/api/v1alpha1"
+	muocfg "github.com/openshift/managed-upgrade-operator/config"
+	"github.com/openshift/managed-upgrade-operator/pkg/configmanager"
+	"github.com/openshift/managed-upgrade-operator/pkg/drain"
+	"github.com/openshift/managed-upgrade-operator/pkg/machinery"
+	"github.com/openshift/managed-upgrade-operator/pkg/upgradeconfigmanager"
+)
+
+// simulateDrainCommand is the subcommand predicting how nodes would be drained
+const simulateDrainCommand = "simulate-drain"
+
+// simulateDrainConfig is the part of the operator's configuration used to simulate node drains
+type simulateDrainConfig struct {
+	NodeDrain drain.NodeDrain `yaml:"nodeDrain"`
+}
+
+func (sdc *simulateDrainConfig) IsValid() error {
+	return sdc.NodeDrain.IsValid()
+}
+
+// simulateDrain prints how the pods of the named nodes, or of every worker node if none is named,
+// would leave the nodes if they were drained. No node is cordoned or drained.
+func simulateDrain(args []string) error {
+	fs := flag.NewFlagSet(simulateDrainCommand, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] [node...]\n", os.Args[0], simulateDrainCommand)
+		fs.PrintDefaults()
+	}
+	namespace := fs.String("namespace", muocfg.OperatorNamespace, "The namespace of the operator's ConfigMap and UpgradeConfig.")
+	output := fs.String("output", "table", "The output format, either table or json.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %s", *output)
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	target, err := (&muocfg.CMTarget{Namespace: *namespace}).NewCMTarget()
+	if err != nil {
+		return err
+	}
+	cfg := &simulateDrainConfig{}
+	err = configmanager.NewBuilder().New(c, target).Into(cfg)
+	if err != nil {
+		return err
+	}
+
+	// The PDB drain timeout is taken from the UpgradeConfig, if an upgrade is configured
+	uc := &upgradev1alpha1.UpgradeConfig{}
+	err = c.Get(context.TODO(), client.ObjectKey{Namespace: *namespace, Name: upgradeconfigmanager.UPGRADECONFIG_CR_NAME}, uc)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		uc = nil
+	}
+
+	nodes, err := nodesToSimulate(c, fs.Args())
+	if err != nil {
+		return err
+	}
+	plans, err := drain.SimulateNodeDrains(c, nodes, uc, &cfg.NodeDrain, logf.Log)
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plans)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tNAMESPACE\tPOD\tOUTCOME\tSTRATEGY\tAFTER\tPDB\tPOLICY")
+	for _, plan := range plans {
+		for _, p := range plan.Pods {
+			after := ""
+			if p.After != nil {
+				after = p.After.Duration.String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", plan.Node, p.Namespace, p.Name, p.Outcome, p.Strategy, after, p.PDB, p.Policy)
+		}
+	}
+	return w.Flush()
+}
+
+// nodesToSimulate returns the named nodes, or if none is named every node of the worker
+// MachineConfigPools, as simulated by the PDB health check
+func nodesToSimulate(c client.Client, names []string) ([]corev1.Node, error) {
+	if len(names) == 0 {
+		nodes, err := machinery.ListWorkerNodes(c)
+		if err != nil {
+			return nil, err
+		}
+		return nodes.Items, nil
+	}
+
+	var nodes []corev1.Node
+	for _, name := range names {
+		node := corev1.Node{}
+		err := c.Get(context.TODO(), client.ObjectKey{Name: name}, &node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}